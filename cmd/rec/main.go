@@ -15,8 +15,11 @@ import (
 
 	"github.com/rootly/edge-connector/internal/api"
 	"github.com/rootly/edge-connector/internal/config"
+	"github.com/rootly/edge-connector/internal/dedup"
 	"github.com/rootly/edge-connector/internal/executor"
+	"github.com/rootly/edge-connector/internal/journal"
 	"github.com/rootly/edge-connector/internal/metrics"
+	"github.com/rootly/edge-connector/internal/outbox"
 	"github.com/rootly/edge-connector/internal/poller"
 	"github.com/rootly/edge-connector/internal/reporter"
 	"github.com/rootly/edge-connector/internal/worker"
@@ -105,74 +108,13 @@ func main() {
 	apiClient := api.NewClient(cfg.Rootly.APIURL, cfg.Rootly.APIPath, cfg.Rootly.APIKey, version)
 
 	// Register all actions with backend (backend categorizes as automatic/callable)
-	registrationRequest := api.ConvertActionsToRegistrations(actionsConfig.Actions)
-
-	if len(registrationRequest.Actions) > 0 {
-		log.WithFields(log.Fields{
-			"actions_count": len(registrationRequest.Actions),
-		}).Info("Registering actions with backend")
-
-		resp, err := apiClient.RegisterActions(context.Background(), registrationRequest)
-		if err != nil {
-			log.WithError(err).Warn("Failed to register actions with backend (continuing anyway)")
-		} else {
-			log.WithFields(log.Fields{
-				"automatic": resp.Registered.Automatic,
-				"callable":  resp.Registered.Callable,
-				"total":     resp.Registered.Total,
-				"failed":    resp.Failed,
-			}).Info("Successfully registered actions")
-			if resp.Failed > 0 {
-				for _, failure := range resp.Failures {
-					log.WithFields(log.Fields{
-						"action_slug": failure.Slug,
-						"reason":      failure.Reason,
-					}).Warn("Failed to register action")
-				}
-			}
-		}
-	} else {
-		log.Debug("No actions to register")
-	}
+	registerActions(context.Background(), apiClient, actionsConfig.Actions)
 
 	// Initialize Git repository manager for git-based actions
 	gitManager := git.NewManager("/tmp/rec-repos")
 
 	// Pre-download git repositories
-	for i := range actionsConfig.Actions {
-		action := &actionsConfig.Actions[i]
-		if action.SourceType == "git" && action.GitOptions != nil {
-			log.WithField("repo_url", action.GitOptions.URL).Info("Downloading Git repository")
-			if _, err := gitManager.Download(action.GitOptions); err != nil {
-				log.WithError(err).WithFields(log.Fields{
-					"repo_url":      action.GitOptions.URL,
-					fieldActionID:   action.ID,
-					fieldActionName: action.Name,
-				}).Error("Failed to download Git repository - action will be skipped")
-				continue // Skip this action but continue with others
-			}
-
-			// Get script path from repository
-			scriptPath, err := gitManager.GetScriptPath(action.GitOptions.URL, action.Script)
-			if err != nil {
-				log.WithError(err).WithFields(log.Fields{
-					"script":        action.Script,
-					"repo_url":      action.GitOptions.URL,
-					fieldActionID:   action.ID,
-					fieldActionName: action.Name,
-				}).Error("Failed to get script path from repository - action will be skipped")
-				continue // Skip this action but continue with others
-			}
-
-			// Update action to use local script path
-			action.Script = scriptPath
-			log.WithFields(log.Fields{
-				fieldActionID:   action.ID,
-				fieldActionName: action.Name,
-				"script_path":   scriptPath,
-			}).Debug("Updated action script path from Git repository")
-		}
-	}
+	hasGitActions := prepareGitActions(gitManager, actionsConfig.Actions)
 
 	// Initialize script runner with git manager for repository locking
 	scriptRunner := executor.NewScriptRunner(
@@ -181,63 +123,152 @@ func main() {
 	)
 	scriptRunner.SetGitManager(gitManager)
 
+	// Cap captured stdout, stderr and HTTP response bodies
+	outputLimits := executor.OutputLimits{
+		OverflowDir: cfg.Output.OverflowDir,
+		MaxBytes:    cfg.Output.MaxBytes,
+	}
+	scriptRunner.SetOutputLimits(outputLimits)
+
 	// Initialize HTTP executor
 	httpExecutor := executor.NewHTTPExecutor()
+	httpExecutor.SetOutputLimits(outputLimits)
+	httpExecutor.SetTargetLimits(&cfg.HTTPTargets)
+
+	// Initialize outbox for execution reports that fail to send
+	reportOutbox, err := outbox.New(cfg.State.Dir, &cfg.Outbox, apiClient)
+	if err != nil {
+		log.WithError(err).Fatal("Failed to initialize outbox")
+	}
 
 	// Initialize reporter
 	rep := reporter.New(apiClient)
+	rep.SetOutbox(reportOutbox)
+	rep.SetMaxOutputBytes(cfg.Output.MaxBytes)
+
+	// Send live output of actions with progress: true, rate limited across all deliveries
+	apiClient.SetProgressRateLimit(cfg.Progress.MaxUpdatesPerSec)
+	scriptRunner.SetProgressReporter(rep, time.Duration(cfg.Progress.IntervalMs)*time.Millisecond)
+
+	// Open journal of claimed deliveries (used to recover deliveries interrupted by a crash)
+	deliveryJournal, err := journal.Open(cfg.State.Dir)
+	if err != nil {
+		log.WithError(err).Fatal("Failed to open delivery journal")
+	}
+
+	// Remember claimed deliveries to skip redeliveries and duplicate events (dedup.ttl_sec: 0 turns this off)
+	var seenDeliveries *dedup.Store
+	if cfg.Dedup.TTLSec > 0 {
+		seenDeliveries, err = dedup.New(cfg.State.Dir, &cfg.Dedup)
+		if err != nil {
+			log.WithError(err).Fatal("Failed to initialize delivery deduplication")
+		}
+	} else {
+		log.Info("Delivery deduplication disabled")
+	}
 
 	// Initialize executor
 	exec := executor.New(actionsConfig.Actions, scriptRunner, httpExecutor, rep)
+	exec.SetJournal(deliveryJournal)
+
+	// Renew the lease of running deliveries so actions longer than the visibility timeout are not redelivered
+	exec.SetLeaseExtender(apiClient, time.Duration(cfg.Poller.HeartbeatIntervalSec)*time.Second, cfg.Poller.VisibilityTimeoutSec)
 
 	// Initialize worker pool
 	pool := worker.NewPool(&cfg.Pool, exec)
 
 	// Initialize poller
 	poll := poller.New(apiClient, &cfg.Poller, pool)
+	poll.SetJournal(deliveryJournal)
+	poll.SetReporter(rep)
+	poll.SetLeaseHolder(exec)
+	if seenDeliveries != nil {
+		poll.SetDeduplicator(seenDeliveries)
+	}
 
-	// Setup context with cancellation
+	// Receive deliveries by polling, or over the delivery stream with polling as a fallback
+	var source poller.Source = poll
+	if cfg.Poller.Mode == config.PollerModeStream {
+		source = poller.NewStreamer(poll)
+	}
+
+	// Setup contexts with cancellation
+	// ctx stops polling and background loops; execCtx is only canceled if the shutdown drain times out
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
+	execCtx, cancelExec := context.WithCancelCause(context.Background())
+	defer cancelExec(nil)
 
-	// Handle shutdown signals
+	// Handle shutdown signals (SIGHUP reloads actions instead)
 	sigCh := make(chan os.Signal, 1)
-	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
 
-	// Start Git repository periodic pull (if any git-based actions exist)
-	hasGitActions := false
-	for _, action := range actionsConfig.Actions {
-		if action.SourceType == "git" {
-			hasGitActions = true
-			break
-		}
+	// Hot reload of actions.yml
+	reloader := newActionsReloader(*actionsPath, apiClient, exec, gitManager)
+	if cfg.Reload.WatchFile {
+		go reloader.Watch(ctx, time.Duration(cfg.Reload.WatchIntervalMs)*time.Millisecond)
 	}
+
+	// Start Git repository periodic pull (if any git-based actions exist)
 	if hasGitActions {
-		go gitManager.StartPeriodicPull(ctx)
-		log.Info("Started periodic Git repository pull")
+		reloader.startGitPull(ctx)
 	}
 
+	// Replay execution reports left in the outbox
+	go reportOutbox.Start(ctx)
+
 	// Start worker pool
-	pool.Start(ctx)
+	pool.Start(execCtx)
+
+	// Resolve deliveries left running by a previous process before polling for new ones
+	deliveryJournal.Recover(ctx, rep, pool.Submit)
 
 	// Start poller in goroutine
+	pollerDone := make(chan struct{})
 	go func() {
-		if err := poll.Start(ctx); err != nil && err != context.Canceled {
+		defer close(pollerDone)
+		if err := source.Start(ctx); err != nil && err != context.Canceled {
 			log.WithError(err).Error("Poller stopped unexpectedly")
 		}
 	}()
 
 	log.Info("Rootly Edge Connector started successfully")
 
-	// Wait for shutdown signal
+	// Wait for shutdown signal, reloading actions on SIGHUP
 	sig := <-sigCh
+	for sig == syscall.SIGHUP {
+		log.WithField("path", *actionsPath).Info("Received SIGHUP, reloading actions")
+		if err := reloader.Reload(ctx); err != nil {
+			log.WithError(err).WithField("path", *actionsPath).Error("Failed to reload actions, keeping current configuration")
+		}
+		sig = <-sigCh
+	}
 	log.WithField("signal", sig).Info("Received shutdown signal")
 
-	// Cancel context and shutdown gracefully
+	// Phase 1: stop polling so no new deliveries are claimed
 	cancel()
+	<-pollerDone
+
+	// Phase 2: let in-flight actions finish, cutting them off after shutdown_timeout_sec
+	shutdownTimeout := time.Duration(cfg.Pool.ShutdownTimeoutSec) * time.Second
+	log.WithField("shutdown_timeout", shutdownTimeout).Info("Draining worker pool...")
+	notStarted := pool.Drain(shutdownTimeout, func() {
+		cancelExec(worker.ErrShutdownTimeout)
+	})
+
+	// Phase 3: report deliveries that were claimed but never started
+	for _, event := range notStarted {
+		exec.ReportNotStarted(context.Background(), event, worker.ErrShutdownBeforeStart)
+	}
 
-	log.Info("Shutting down worker pool...")
-	pool.Shutdown()
+	if err := deliveryJournal.Close(); err != nil {
+		log.WithError(err).Error("Error closing delivery journal")
+	}
+	if seenDeliveries != nil {
+		if err := seenDeliveries.Close(); err != nil {
+			log.WithError(err).Error("Error closing deduplication store")
+		}
+	}
 
 	// Shutdown metrics server if enabled
 	if metricsServer != nil {
@@ -0,0 +1,215 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/rootly/edge-connector/internal/api"
+	"github.com/rootly/edge-connector/internal/config"
+	"github.com/rootly/edge-connector/internal/executor"
+	"github.com/rootly/edge-connector/pkg/git"
+)
+
+// actionRegistrar registers actions with the Rootly backend
+type actionRegistrar interface {
+	RegisterActions(ctx context.Context, request api.RegisterActionsRequest) (*api.RegisterActionsResponse, error)
+}
+
+// actionsReloader reloads actions.yml into a running executor
+// Reloads are triggered by SIGHUP or, if enabled, by the file watcher
+type actionsReloader struct {
+	registrar   actionRegistrar
+	executor    *executor.Executor
+	gitManager  *git.Manager
+	path        string
+	gitPullOnce sync.Once
+	mu          sync.Mutex // Serializes reloads from SIGHUP and the file watcher
+}
+
+// newActionsReloader creates a reloader for the given actions file
+func newActionsReloader(path string, registrar actionRegistrar, exec *executor.Executor, gitManager *git.Manager) *actionsReloader {
+	return &actionsReloader{
+		path:       path,
+		registrar:  registrar,
+		executor:   exec,
+		gitManager: gitManager,
+	}
+}
+
+// Reload loads and validates the actions file, then swaps the new actions into the executor
+// If the file is invalid the current actions are kept and the validation error is returned
+func (r *actionsReloader) Reload(ctx context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	actionsConfig, err := config.LoadActions(r.path)
+	if err != nil {
+		return err
+	}
+
+	// Clone repositories before the swap so new git actions are runnable as soon as they are visible
+	hasGitActions := prepareGitActions(r.gitManager, actionsConfig.Actions)
+
+	r.executor.SetActions(actionsConfig.Actions)
+	log.WithField("action_count", len(actionsConfig.Actions)).Info("Reloaded actions configuration")
+
+	registerActions(ctx, r.registrar, actionsConfig.Actions)
+
+	if hasGitActions {
+		r.startGitPull(ctx)
+	}
+
+	return nil
+}
+
+// Watch polls the actions file and reloads it whenever its content changes
+func (r *actionsReloader) Watch(ctx context.Context, interval time.Duration) {
+	lastHash, hashErr := fileHash(r.path)
+	if hashErr != nil {
+		log.WithError(hashErr).WithField("path", r.path).Warn("Failed to read actions file for change detection")
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	log.WithFields(log.Fields{
+		"path":     r.path,
+		"interval": interval,
+	}).Info("Watching actions file for changes")
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			hash, err := fileHash(r.path)
+			if err != nil {
+				// The file may be briefly missing while an editor or deploy tool replaces it
+				log.WithError(err).WithField("path", r.path).Debug("Failed to read actions file for change detection")
+				continue
+			}
+			if hash == lastHash {
+				continue
+			}
+			lastHash = hash
+
+			log.WithField("path", r.path).Info("Actions file changed, reloading")
+			if err := r.Reload(ctx); err != nil {
+				log.WithError(err).WithField("path", r.path).Error("Failed to reload actions, keeping current configuration")
+			}
+		}
+	}
+}
+
+// startGitPull starts the periodic pull of git repositories, at most once per process
+func (r *actionsReloader) startGitPull(ctx context.Context) {
+	r.gitPullOnce.Do(func() {
+		go r.gitManager.StartPeriodicPull(ctx)
+		log.Info("Started periodic Git repository pull")
+	})
+}
+
+// fileHash returns the SHA-256 of the file's content
+func fileHash(path string) ([sha256.Size]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return [sha256.Size]byte{}, fmt.Errorf("failed to read file: %w", err)
+	}
+	return sha256.Sum256(data), nil
+}
+
+// registerActions registers all actions with the backend (backend categorizes as automatic/callable)
+// Registration failures are logged and do not stop the connector
+func registerActions(ctx context.Context, registrar actionRegistrar, actions []config.Action) {
+	registrationRequest := api.ConvertActionsToRegistrations(actions)
+
+	if len(registrationRequest.Actions) == 0 {
+		log.Debug("No actions to register")
+		return
+	}
+
+	log.WithFields(log.Fields{
+		"actions_count": len(registrationRequest.Actions),
+	}).Info("Registering actions with backend")
+
+	resp, err := registrar.RegisterActions(ctx, registrationRequest)
+	if err != nil {
+		log.WithError(err).Warn("Failed to register actions with backend (continuing anyway)")
+		return
+	}
+
+	log.WithFields(log.Fields{
+		"automatic": resp.Registered.Automatic,
+		"callable":  resp.Registered.Callable,
+		"total":     resp.Registered.Total,
+		"failed":    resp.Failed,
+	}).Info("Successfully registered actions")
+	for _, failure := range resp.Failures {
+		log.WithFields(log.Fields{
+			"action_slug": failure.Slug,
+			"reason":      failure.Reason,
+		}).Warn("Failed to register action")
+	}
+}
+
+// prepareGitActions downloads the repositories of git-based actions (and pipeline steps) and points their scripts at the local clone
+// Repositories that are already cloned are reused. Returns true if any git-based actions exist
+func prepareGitActions(gitManager *git.Manager, actions []config.Action) bool {
+	hasGitActions := false
+
+	for i := range actions {
+		action := &actions[i]
+		if len(action.Steps) > 0 {
+			// Pipeline steps can be git-based individually
+			if prepareGitActions(gitManager, action.Steps) {
+				hasGitActions = true
+			}
+			continue
+		}
+		if action.SourceType != "git" {
+			continue
+		}
+		hasGitActions = true
+		if action.GitOptions == nil {
+			continue
+		}
+
+		log.WithField("repo_url", action.GitOptions.URL).Info("Downloading Git repository")
+		if _, err := gitManager.Download(action.GitOptions); err != nil {
+			log.WithError(err).WithFields(log.Fields{
+				"repo_url":      action.GitOptions.URL,
+				fieldActionID:   action.ID,
+				fieldActionName: action.Name,
+			}).Error("Failed to download Git repository - action will be skipped")
+			continue // Skip this action but continue with others
+		}
+
+		// Get script path from repository
+		scriptPath, err := gitManager.GetScriptPath(action.GitOptions.URL, action.Script)
+		if err != nil {
+			log.WithError(err).WithFields(log.Fields{
+				"script":        action.Script,
+				"repo_url":      action.GitOptions.URL,
+				fieldActionID:   action.ID,
+				fieldActionName: action.Name,
+			}).Error("Failed to get script path from repository - action will be skipped")
+			continue // Skip this action but continue with others
+		}
+
+		// Update action to use local script path
+		action.Script = scriptPath
+		log.WithFields(log.Fields{
+			fieldActionID:   action.ID,
+			fieldActionName: action.Name,
+			"script_path":   scriptPath,
+		}).Debug("Updated action script path from Git repository")
+	}
+
+	return hasGitActions
+}
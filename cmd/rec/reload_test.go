@@ -0,0 +1,132 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rootly/edge-connector/internal/api"
+	"github.com/rootly/edge-connector/internal/executor"
+	"github.com/rootly/edge-connector/pkg/git"
+)
+
+type mockRegistrar struct {
+	err      error
+	requests []api.RegisterActionsRequest
+	mu       sync.Mutex
+}
+
+func (m *mockRegistrar) RegisterActions(ctx context.Context, request api.RegisterActionsRequest) (*api.RegisterActionsResponse, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.requests = append(m.requests, request)
+	if m.err != nil {
+		return nil, m.err
+	}
+	resp := &api.RegisterActionsResponse{}
+	resp.Registered.Total = len(request.Actions)
+	return resp, nil
+}
+
+func (m *mockRegistrar) calls() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.requests)
+}
+
+const reloadActionsV1 = `on:
+  alert.created:
+    http:
+      url: https://example.com/alert
+      method: POST
+`
+
+const reloadActionsV2 = `on:
+  alert.created:
+    http:
+      url: https://example.com/alert
+      method: POST
+
+  incident.created:
+    http:
+      url: https://example.com/incident
+      method: POST
+`
+
+func newTestReloader(t *testing.T, content string) (*actionsReloader, *mockRegistrar, string) {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "actions.yml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+	registrar := &mockRegistrar{}
+	exec := executor.New(nil, nil, nil, nil)
+	return newActionsReloader(path, registrar, exec, git.NewManager(t.TempDir())), registrar, path
+}
+
+func TestActionsReloader_ReloadSwapsActionsAndRegisters(t *testing.T) {
+	reloader, registrar, path := newTestReloader(t, reloadActionsV1)
+
+	require.NoError(t, reloader.Reload(context.Background()))
+	assert.Len(t, reloader.executor.Actions(), 1)
+
+	require.NoError(t, os.WriteFile(path, []byte(reloadActionsV2), 0o600))
+	require.NoError(t, reloader.Reload(context.Background()))
+
+	assert.Len(t, reloader.executor.Actions(), 2)
+	require.Equal(t, 2, registrar.calls())
+	assert.Len(t, registrar.requests[1].Actions, 2)
+}
+
+func TestActionsReloader_InvalidFileKeepsCurrentActions(t *testing.T) {
+	reloader, registrar, path := newTestReloader(t, reloadActionsV1)
+	require.NoError(t, reloader.Reload(context.Background()))
+
+	// Callable trigger in the "on" section fails validation
+	invalid := `on:
+  action.triggered:
+    http:
+      url: https://example.com/alert
+`
+	require.NoError(t, os.WriteFile(path, []byte(invalid), 0o600))
+
+	err := reloader.Reload(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid actions config")
+	assert.Len(t, reloader.executor.Actions(), 1, "Previous actions should stay active")
+	assert.Equal(t, 1, registrar.calls(), "Invalid config should not be registered")
+}
+
+func TestActionsReloader_RegistrationFailureStillSwapsActions(t *testing.T) {
+	reloader, registrar, _ := newTestReloader(t, reloadActionsV2)
+	registrar.err = errors.New("api down")
+
+	require.NoError(t, reloader.Reload(context.Background()))
+	assert.Len(t, reloader.executor.Actions(), 2)
+}
+
+func TestActionsReloader_WatchReloadsOnChange(t *testing.T) {
+	reloader, registrar, path := newTestReloader(t, reloadActionsV1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go reloader.Watch(ctx, 10*time.Millisecond)
+
+	// Unchanged file should not trigger a reload
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, 0, registrar.calls())
+
+	require.NoError(t, os.WriteFile(path, []byte(reloadActionsV2), 0o600))
+
+	assert.Eventually(t, func() bool {
+		return len(reloader.executor.Actions()) == 2
+	}, 2*time.Second, 10*time.Millisecond)
+	assert.Equal(t, 1, registrar.calls())
+}
@@ -11,6 +11,8 @@ import (
 
 	"github.com/hashicorp/go-retryablehttp"
 	log "github.com/sirupsen/logrus"
+
+	"github.com/rootly/edge-connector/internal/ratelimit"
 )
 
 // String constants for HTTP methods and log field keys
@@ -30,11 +32,14 @@ const (
 
 // Client represents a Rootly API client
 type Client struct {
-	httpClient *retryablehttp.Client
-	baseURL    string
-	apiPath    string
-	apiKey     string
-	userAgent  string
+	httpClient      *retryablehttp.Client
+	fetchClient     *retryablehttp.Client // Same transport as httpClient, but returns 429 responses instead of retrying
+	onceClient      *retryablehttp.Client // Same transport as httpClient, but sends each request only once
+	progressLimiter *ratelimit.Limiter    // Caps ReportProgress calls (nil means unlimited)
+	baseURL         string
+	apiPath         string
+	apiKey          string
+	userAgent       string
 }
 
 // NewClient creates a new Rootly API client
@@ -45,19 +50,48 @@ func NewClient(baseURL, apiPath, apiKey, version string) *Client {
 	retryClient.RetryWaitMax = 10 * time.Second
 	retryClient.Logger = nil // Disable retryablehttp logging, we'll use our own
 
+	fetchClient := retryablehttp.NewClient()
+	fetchClient.HTTPClient = retryClient.HTTPClient
+	fetchClient.RetryMax = retryClient.RetryMax
+	fetchClient.RetryWaitMin = retryClient.RetryWaitMin
+	fetchClient.RetryWaitMax = retryClient.RetryWaitMax
+	fetchClient.Logger = nil
+	fetchClient.CheckRetry = fetchRetryPolicy
+
+	onceClient := retryablehttp.NewClient()
+	onceClient.HTTPClient = retryClient.HTTPClient
+	onceClient.RetryMax = 0
+	onceClient.Logger = nil
+	onceClient.CheckRetry = noRetryPolicy
+
 	return &Client{
-		baseURL:    baseURL,
-		apiPath:    apiPath,
-		apiKey:     apiKey,
-		httpClient: retryClient,
-		userAgent:  fmt.Sprintf("rootly-edge-connector/%s", version),
+		baseURL:     baseURL,
+		apiPath:     apiPath,
+		apiKey:      apiKey,
+		httpClient:  retryClient,
+		fetchClient: fetchClient,
+		onceClient:  onceClient,
+		userAgent:   fmt.Sprintf("rootly-edge-connector/%s", version),
 	}
 }
 
 // FetchEvents fetches events from the Rootly API
 func (c *Client) FetchEvents(ctx context.Context, maxMessages, visibilityTimeout int) ([]Event, error) {
+	response, err := c.FetchEventsPage(ctx, maxMessages, visibilityTimeout, nil)
+	if err != nil {
+		return nil, err
+	}
+	return response.Events, nil
+}
+
+// FetchEventsPage fetches one page of events from the Rootly API
+// A nil cursor fetches the first page; the response's NextCursor is set while more deliveries are waiting
+func (c *Client) FetchEventsPage(ctx context.Context, maxMessages, visibilityTimeout int, cursor *int64) (*EventsResponse, error) {
 	url := fmt.Sprintf("%s%s/deliveries?max_messages=%d&visibility_timeout=%d",
 		c.baseURL, c.apiPath, maxMessages, visibilityTimeout)
+	if cursor != nil {
+		url += fmt.Sprintf("&cursor=%d", *cursor)
+	}
 
 	req, err := retryablehttp.NewRequestWithContext(ctx, methodGET, url, nil)
 	if err != nil {
@@ -80,7 +114,7 @@ func (c *Client) FetchEvents(ctx context.Context, maxMessages, visibilityTimeout
 	}).Debug("HTTP request")
 
 	startTime := time.Now()
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.fetchClient.Do(req)
 	duration := time.Since(startTime)
 
 	if err != nil {
@@ -90,7 +124,7 @@ func (c *Client) FetchEvents(ctx context.Context, maxMessages, visibilityTimeout
 			fieldError:    err.Error(),
 			fieldDuration: duration.String(),
 		}).Error("HTTP request failed")
-		return nil, fmt.Errorf("request failed: %w", err)
+		return nil, &RequestError{Err: err}
 	}
 	defer resp.Body.Close()
 
@@ -106,18 +140,18 @@ func (c *Client) FetchEvents(ctx context.Context, maxMessages, visibilityTimeout
 	c.logRateLimitHeaders(resp)
 
 	// Handle rate limiting (429 Too Many Requests)
+	rateLimit := parseRateLimit(resp)
 	if resp.StatusCode == http.StatusTooManyRequests {
-		remaining := resp.Header.Get("X-RateLimit-Remaining")
-		reset := resp.Header.Get("X-RateLimit-Reset")
 		log.WithFields(log.Fields{
-			"remaining": remaining,
-			"reset":     reset,
+			"remaining":   rateLimit.Remaining,
+			"reset":       resp.Header.Get("X-RateLimit-Reset"),
+			"retry_after": rateLimit.RetryAfter.String(),
 		}).Warn("Rate limit exceeded")
-		return nil, fmt.Errorf("rate limit exceeded (remaining: %s, resets at: %s)", remaining, reset)
+		return nil, &RateLimitError{RateLimit: rateLimit}
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+		return nil, &StatusError{StatusCode: resp.StatusCode}
 	}
 
 	// Read response body
@@ -148,12 +182,14 @@ func (c *Client) FetchEvents(ctx context.Context, maxMessages, visibilityTimeout
 	if err := json.Unmarshal(bodyBytes, &response); err != nil {
 		return nil, fmt.Errorf("failed to decode response: %w", err)
 	}
+	response.RateLimit = rateLimit
 
 	log.WithFields(log.Fields{
 		"event_count": len(response.Events),
+		"has_more":    response.NextCursor != nil,
 	}).Debug("Fetched events from API")
 
-	return response.Events, nil
+	return &response, nil
 }
 
 // logRateLimitHeaders logs Rootly API rate limit headers for monitoring
@@ -220,7 +256,7 @@ func (c *Client) RegisterActions(ctx context.Context, request RegisterActionsReq
 			fieldError:    err.Error(),
 			fieldDuration: duration.String(),
 		}).Error("HTTP request failed")
-		return nil, fmt.Errorf("request failed: %w", err)
+		return nil, &RequestError{Err: err}
 	}
 	defer resp.Body.Close()
 
@@ -254,13 +290,11 @@ func (c *Client) RegisterActions(ctx context.Context, request RegisterActionsReq
 
 	// Handle different status codes
 	if resp.StatusCode == http.StatusTooManyRequests {
-		remaining := resp.Header.Get("X-RateLimit-Remaining")
-		reset := resp.Header.Get("X-RateLimit-Reset")
-		return nil, fmt.Errorf("rate limit exceeded (remaining: %s, resets at: %s)", remaining, reset)
+		return nil, &RateLimitError{RateLimit: parseRateLimit(resp)}
 	}
 
 	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusMultiStatus {
-		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+		return nil, &StatusError{StatusCode: resp.StatusCode}
 	}
 
 	// Decode response
@@ -335,7 +369,7 @@ func (c *Client) MarkDeliveryAsRunning(ctx context.Context, deliveryID string) e
 			fieldError:    err.Error(),
 			fieldDuration: duration.String(),
 		}).Error("HTTP request failed")
-		return fmt.Errorf("request failed: %w", err)
+		return &RequestError{Err: err}
 	}
 	defer resp.Body.Close()
 
@@ -375,13 +409,11 @@ func (c *Client) MarkDeliveryAsRunning(ctx context.Context, deliveryID string) e
 
 	// Handle rate limiting
 	if resp.StatusCode == http.StatusTooManyRequests {
-		remaining := resp.Header.Get("X-RateLimit-Remaining")
-		reset := resp.Header.Get("X-RateLimit-Reset")
-		return fmt.Errorf("rate limit exceeded (remaining: %s, resets at: %s)", remaining, reset)
+		return &RateLimitError{RateLimit: parseRateLimit(resp)}
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+		return &StatusError{StatusCode: resp.StatusCode}
 	}
 
 	log.WithFields(log.Fields{
@@ -448,7 +480,7 @@ func (c *Client) ReportExecution(ctx context.Context, execution ExecutionResult)
 			fieldError:    err.Error(),
 			fieldDuration: duration.String(),
 		}).Error("HTTP request failed")
-		return fmt.Errorf("request failed: %w", err)
+		return &RequestError{Err: err}
 	}
 	defer resp.Body.Close()
 
@@ -489,13 +521,11 @@ func (c *Client) ReportExecution(ctx context.Context, execution ExecutionResult)
 
 	// Handle rate limiting
 	if resp.StatusCode == http.StatusTooManyRequests {
-		remaining := resp.Header.Get("X-RateLimit-Remaining")
-		reset := resp.Header.Get("X-RateLimit-Reset")
-		return fmt.Errorf("rate limit exceeded (remaining: %s, resets at: %s)", remaining, reset)
+		return &RateLimitError{RateLimit: parseRateLimit(resp)}
 	}
 
 	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+		return &StatusError{StatusCode: resp.StatusCode}
 	}
 
 	log.WithFields(log.Fields{
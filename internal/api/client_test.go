@@ -3,10 +3,12 @@ package api_test
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
@@ -68,6 +70,28 @@ func TestClient_FetchEvents_Success(t *testing.T) {
 	assert.Equal(t, "Production API Gateway Outage", events[1].Data["title"])
 }
 
+func TestClient_FetchEventsPage_Cursor(t *testing.T) {
+	var cursors []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cursors = append(cursors, r.URL.Query().Get("cursor"))
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"events": [{"id": "delivery-1"}], "next_cursor": 1700000000}`))
+	}))
+	defer server.Close()
+
+	client := api.NewClient(server.URL, "", "test-key", "test")
+
+	response, err := client.FetchEventsPage(context.Background(), 10, 30, nil)
+	require.NoError(t, err)
+	require.Len(t, response.Events, 1)
+	require.NotNil(t, response.NextCursor)
+	assert.Equal(t, int64(1700000000), *response.NextCursor)
+
+	_, err = client.FetchEventsPage(context.Background(), 10, 30, response.NextCursor)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"", "1700000000"}, cursors, "The first page is fetched without a cursor")
+}
+
 func TestClient_FetchEvents_Empty(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		response := api.EventsResponse{Events: []api.Event{}}
@@ -464,11 +488,14 @@ func TestClient_FetchEvents_Timeout(t *testing.T) {
 }
 
 func TestClient_FetchEvents_RateLimit(t *testing.T) {
+	attemptCount := 0
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attemptCount++
 		w.Header().Set("X-RateLimit-Limit", "100")
 		w.Header().Set("X-RateLimit-Remaining", "0")
 		w.Header().Set("X-RateLimit-Used", "100")
 		w.Header().Set("X-RateLimit-Reset", "1698765432")
+		w.Header().Set("Retry-After", "30")
 		w.WriteHeader(http.StatusTooManyRequests)
 	}))
 	defer server.Close()
@@ -478,8 +505,54 @@ func TestClient_FetchEvents_RateLimit(t *testing.T) {
 
 	require.Error(t, err)
 	assert.Nil(t, events)
-	// retryablehttp retries on 429, so final error will be "giving up after X attempts"
-	assert.Contains(t, err.Error(), "giving up after")
+	assert.Equal(t, 1, attemptCount, "Rate limited fetches are returned to the poller instead of retried")
+
+	var rateLimited *api.RateLimitError
+	require.ErrorAs(t, err, &rateLimited)
+	assert.Equal(t, 100, rateLimited.RateLimit.Limit)
+	assert.Equal(t, 0, rateLimited.RateLimit.Remaining)
+	assert.Equal(t, time.Unix(1698765432, 0), rateLimited.RateLimit.Reset)
+	assert.Equal(t, 30*time.Second, rateLimited.RateLimit.RetryAfter)
+	assert.Contains(t, err.Error(), "rate limit exceeded (remaining: 0, resets at: 2023-10-31T15:17:12Z, retry after: 30s)")
+}
+
+func TestClient_FetchEventsPage_RateLimitHeaders(t *testing.T) {
+	headers := map[string]string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for name, value := range headers {
+			w.Header().Set(name, value)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(api.EventsResponse{Events: []api.Event{}})
+	}))
+	defer server.Close()
+
+	client := api.NewClient(server.URL, "", "test-key", "test")
+
+	// Missing headers are reported as unknown
+	response, err := client.FetchEventsPage(context.Background(), 10, 30, nil)
+	require.NoError(t, err)
+	assert.Equal(t, -1, response.RateLimit.Limit)
+	assert.Equal(t, -1, response.RateLimit.Remaining)
+	assert.True(t, response.RateLimit.Reset.IsZero())
+	assert.Zero(t, response.RateLimit.Wait(time.Now()))
+
+	reset := time.Now().Add(time.Minute).Truncate(time.Second)
+	headers["X-RateLimit-Limit"] = "3000"
+	headers["X-RateLimit-Remaining"] = "0"
+	headers["X-RateLimit-Reset"] = strconv.FormatInt(reset.Unix(), 10)
+	response, err = client.FetchEventsPage(context.Background(), 10, 30, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 3000, response.RateLimit.Limit)
+	assert.Equal(t, 0, response.RateLimit.Remaining)
+	assert.True(t, reset.Equal(response.RateLimit.Reset))
+	assert.InDelta(t, time.Minute, response.RateLimit.Wait(time.Now()), float64(2*time.Second), "An exhausted quota waits until the reset")
+
+	// Retry-After as an HTTP date takes precedence
+	headers["Retry-After"] = time.Now().Add(2 * time.Minute).UTC().Format(http.TimeFormat)
+	response, err = client.FetchEventsPage(context.Background(), 10, 30, nil)
+	require.NoError(t, err)
+	assert.InDelta(t, 2*time.Minute, response.RateLimit.Wait(time.Now()), float64(2*time.Second))
 }
 
 func TestClient_FetchEvents_InvalidJSON(t *testing.T) {
@@ -589,6 +662,208 @@ func TestClient_HTTPStatusCodes(t *testing.T) {
 	}
 }
 
+func TestClient_ReportProgress(t *testing.T) {
+	var received map[string]interface{}
+	var receivedPath string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "PATCH", r.Method)
+		assert.Equal(t, "Bearer test-key", r.Header.Get("Authorization"))
+		receivedPath = r.URL.Path
+
+		err := json.NewDecoder(r.Body).Decode(&received)
+		require.NoError(t, err)
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := api.NewClient(server.URL, "", "test-key", "test")
+
+	err := client.ReportProgress(context.Background(), api.ExecutionProgress{
+		DeliveryID:      "delivery-123",
+		ExecutionStdout: "step 1 done\n",
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "/deliveries/delivery-123", receivedPath)
+	assert.Equal(t, "running", received["execution_status"], "Progress updates keep the delivery running")
+	assert.Equal(t, "step 1 done\n", received["execution_stdout"])
+	assert.NotContains(t, received, "completed_at")
+}
+
+func TestClient_ReportProgress_NoRetry(t *testing.T) {
+	attemptCount := 0
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attemptCount++
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	client := api.NewClient(server.URL, "", "test-key", "test")
+
+	err := client.ReportProgress(context.Background(), api.ExecutionProgress{DeliveryID: "delivery-123"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unexpected status code: 500")
+	assert.Equal(t, 1, attemptCount, "Progress updates are best effort and should not be retried")
+}
+
+func TestClient_ReportProgress_RateLimit(t *testing.T) {
+	attemptCount := 0
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attemptCount++
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := api.NewClient(server.URL, "", "test-key", "test")
+	client.SetProgressRateLimit(2)
+
+	var limited int
+	for i := 0; i < 5; i++ {
+		err := client.ReportProgress(context.Background(), api.ExecutionProgress{DeliveryID: "delivery-123"})
+		if errors.Is(err, api.ErrProgressRateLimited) {
+			limited++
+			continue
+		}
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, 2, attemptCount, "Only the burst should be sent")
+	assert.Equal(t, 3, limited)
+
+	// Disabling the limit sends every update
+	client.SetProgressRateLimit(0)
+	require.NoError(t, client.ReportProgress(context.Background(), api.ExecutionProgress{DeliveryID: "delivery-123"}))
+	assert.Equal(t, 3, attemptCount)
+}
+
+func TestClient_ExtendLease(t *testing.T) {
+	var received map[string]interface{}
+	var receivedPath string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "PATCH", r.Method)
+		assert.Equal(t, "Bearer test-key", r.Header.Get("Authorization"))
+		receivedPath = r.URL.Path
+
+		err := json.NewDecoder(r.Body).Decode(&received)
+		require.NoError(t, err)
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := api.NewClient(server.URL, "", "test-key", "test")
+
+	err := client.ExtendLease(context.Background(), "delivery-123", 30)
+	require.NoError(t, err)
+
+	assert.Equal(t, "/deliveries/delivery-123", receivedPath)
+	assert.Equal(t, "running", received["execution_status"])
+	assert.InDelta(t, 30, received["visibility_timeout"], 0)
+}
+
+func TestClient_ExtendLease_NoRetry(t *testing.T) {
+	attemptCount := 0
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attemptCount++
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	client := api.NewClient(server.URL, "", "test-key", "test")
+
+	err := client.ExtendLease(context.Background(), "delivery-123", 30)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unexpected status code: 500")
+	assert.Equal(t, 1, attemptCount, "The next heartbeat replaces a failed lease extension")
+}
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{"nil", nil, false},
+		{"request failed", &api.RequestError{Err: errors.New("connection refused")}, true},
+		{"wrapped request failed", fmt.Errorf("failed to report: %w", &api.RequestError{Err: context.DeadlineExceeded}), true},
+		{"rate limited", &api.RateLimitError{}, true},
+		{"server error", &api.StatusError{StatusCode: http.StatusBadGateway}, true},
+		{"request timeout", &api.StatusError{StatusCode: http.StatusRequestTimeout}, true},
+		{"rejected", &api.StatusError{StatusCode: http.StatusUnprocessableEntity}, false},
+		{"local error", fmt.Errorf("failed to marshal execution result: %w", errors.New("unsupported value")), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, api.IsRetryable(tt.err))
+		})
+	}
+}
+
+func TestClient_ReportExecution_ErrorsAreRetryable(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	client := api.NewClient(server.URL, "", "test-key", "test")
+
+	// A 429 is retried by the client and then surfaces as a request failure
+	err := client.ReportExecution(context.Background(), api.ExecutionResult{DeliveryID: "delivery-1"})
+	require.Error(t, err)
+	assert.True(t, api.IsRetryable(err))
+
+	// An unreachable API is a request failure too
+	server.Close()
+	err = client.ReportExecution(context.Background(), api.ExecutionResult{DeliveryID: "delivery-1"})
+	require.Error(t, err)
+	var requestErr *api.RequestError
+	assert.ErrorAs(t, err, &requestErr)
+	assert.True(t, api.IsRetryable(err))
+}
+
+func TestClient_DeliveryUpdates_RateLimited(t *testing.T) {
+	attemptCount := 0
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attemptCount++
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("Retry-After", "30")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	client := api.NewClient(server.URL, "", "test-key", "test")
+
+	tests := []struct {
+		name   string
+		update func() error
+	}{
+		{"lease extension", func() error { return client.ExtendLease(context.Background(), "delivery-123", 30) }},
+		{"progress update", func() error {
+			return client.ReportProgress(context.Background(), api.ExecutionProgress{DeliveryID: "delivery-123"})
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attemptCount = 0
+
+			err := tt.update()
+
+			var rateLimited *api.RateLimitError
+			require.ErrorAs(t, err, &rateLimited)
+			assert.Equal(t, 0, rateLimited.RateLimit.Remaining)
+			assert.Equal(t, 30*time.Second, rateLimited.RateLimit.RetryAfter)
+			assert.Equal(t, 1, attemptCount, "Rate limited updates are not retried")
+		})
+	}
+}
+
 func TestClient_ReportExecution_RetryOnFailure(t *testing.T) {
 	attemptCount := 0
 
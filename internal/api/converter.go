@@ -4,10 +4,22 @@ import (
 	"github.com/rootly/edge-connector/internal/config"
 )
 
+// Action types as declared to the registration API
+const (
+	actionTypeScript   = "script"
+	actionTypeHTTP     = "http"
+	actionTypePipeline = "pipeline"
+)
+
 // ConvertActionsToRegistrations converts config actions to API registration format
 // Backend categorizes actions based on trigger patterns
+// Wildcard triggers (alert.*, *.created) are expanded to the event types they match, each registered
+// under its event type. Event types registered by an exact trigger are left to that action, and the
+// most specific pattern registers the rest
 func ConvertActionsToRegistrations(actions []config.Action) RegisterActionsRequest {
 	registrations := make([]ActionRegistration, 0, len(actions))
+	registered := make(map[string]bool)
+	var patterns []config.Action
 
 	for _, action := range actions {
 		eventTypes := action.Trigger.GetEventTypes()
@@ -15,16 +27,23 @@ func ConvertActionsToRegistrations(actions []config.Action) RegisterActionsReque
 		if len(eventTypes) > 0 {
 			trigger = eventTypes[0] // Use first event type as trigger
 		}
+		if config.IsEventTypePattern(trigger) {
+			patterns = append(patterns, action)
+			continue
+		}
 
-		registrations = append(registrations, ActionRegistration{
-			Slug:        action.ID,
-			Name:        action.Name,
-			Description: action.Description,
-			ActionType:  action.Type,
-			Trigger:     trigger,
-			Timeout:     action.Timeout,
-			Parameters:  convertParameterDefinitions(action.ParameterDefinitions),
-		})
+		registrations = append(registrations, newRegistration(action, action.ID, trigger))
+		registered[action.ID] = true
+	}
+
+	for _, action := range patterns {
+		for _, eventType := range action.PatternEventTypes {
+			if registered[eventType] || !mostSpecificPattern(patterns, action, eventType) {
+				continue
+			}
+			registrations = append(registrations, newRegistration(action, eventType, eventType))
+			registered[eventType] = true
+		}
 	}
 
 	return RegisterActionsRequest{
@@ -32,6 +51,48 @@ func ConvertActionsToRegistrations(actions []config.Action) RegisterActionsReque
 	}
 }
 
+// newRegistration declares an action to the API under the given slug and trigger
+func newRegistration(action config.Action, slug, trigger string) ActionRegistration {
+	return ActionRegistration{
+		Slug:        slug,
+		Name:        action.Name,
+		Description: action.Description,
+		ActionType:  registrationType(action),
+		Trigger:     trigger,
+		Timeout:     action.Timeout,
+		Parameters:  convertParameterDefinitions(action.ParameterDefinitions),
+	}
+}
+
+// mostSpecificPattern reports whether no other pattern action matches the event type more specifically
+// Of equally specific patterns, the one listed first registers the event type
+func mostSpecificPattern(patterns []config.Action, action config.Action, eventType string) bool {
+	specificity := action.Trigger.MatchEventType(eventType)
+	for _, other := range patterns {
+		if other.ID == action.ID {
+			continue
+		}
+		if other.Trigger.MatchEventType(eventType) > specificity {
+			return false
+		}
+	}
+	return true
+}
+
+// registrationType returns the action type declared to the API, which accepts "script" and "http"
+// A pipeline is declared as http if every step is an HTTP request, and as script otherwise
+func registrationType(action config.Action) string {
+	if action.Type != actionTypePipeline {
+		return action.Type
+	}
+	for _, step := range action.Steps {
+		if step.Type != actionTypeHTTP {
+			return actionTypeScript
+		}
+	}
+	return actionTypeHTTP
+}
+
 // convertParameterDefinitions converts config parameter definitions to API format
 func convertParameterDefinitions(params []config.ParameterDefinition) []ActionParameter {
 	apiParams := make([]ActionParameter, 0, len(params))
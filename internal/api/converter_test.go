@@ -148,6 +148,69 @@ func TestConvertActionsToRegistrations_AutomaticActions(t *testing.T) {
 	assert.Equal(t, "incident.created", request.Actions[1].Trigger)
 }
 
+func TestConvertActionsToRegistrations_WildcardTrigger(t *testing.T) {
+	actions := []config.Action{
+		{
+			ID:                "*",
+			Type:              "script",
+			Trigger:           config.TriggerConfig{EventType: "*"},
+			PatternEventTypes: []string{"alert.created", "alert.updated", "alert.deleted", "incident.created", "incident.updated", "incident.deleted"},
+		},
+		{
+			ID:                "alert.*",
+			Type:              "http",
+			Timeout:           30,
+			Trigger:           config.TriggerConfig{EventType: "alert.*"},
+			PatternEventTypes: []string{"alert.created", "alert.updated", "alert.deleted"},
+		},
+		{
+			ID:      "alert.created",
+			Type:    "http",
+			Trigger: config.TriggerConfig{EventType: "alert.created"},
+		},
+	}
+
+	request := api.ConvertActionsToRegistrations(actions)
+
+	registered := make(map[string]api.ActionRegistration)
+	for _, registration := range request.Actions {
+		assert.NotContains(t, registration.Trigger, "*", "Patterns are expanded to concrete event types")
+		assert.Equal(t, registration.Trigger, registration.Slug)
+		assert.NotContains(t, registered, registration.Slug, "Each event type is registered once")
+		registered[registration.Slug] = registration
+	}
+
+	assert.Len(t, registered, 6)
+	assert.Equal(t, "http", registered["alert.created"].ActionType, "Exact trigger keeps its event type")
+	assert.Equal(t, 0, registered["alert.created"].Timeout)
+	assert.Equal(t, 30, registered["alert.updated"].Timeout, "alert.* is more specific than *")
+	assert.Equal(t, "http", registered["alert.deleted"].ActionType)
+	assert.Equal(t, "script", registered["incident.created"].ActionType, "Other event types fall to *")
+}
+
+func TestConvertActionsToRegistrations_Pipeline(t *testing.T) {
+	actions := []config.Action{
+		{
+			ID:      "alert.created",
+			Type:    "pipeline",
+			Trigger: config.TriggerConfig{EventType: "alert.created"},
+			Steps:   []config.Action{{Type: "script"}, {Type: "http"}},
+		},
+		{
+			ID:      "incident.created",
+			Type:    "pipeline",
+			Trigger: config.TriggerConfig{EventType: "incident.created"},
+			Steps:   []config.Action{{Type: "http"}, {Type: "http"}},
+		},
+	}
+
+	request := api.ConvertActionsToRegistrations(actions)
+
+	require.Len(t, request.Actions, 2)
+	assert.Equal(t, "script", request.Actions[0].ActionType, "Pipelines are declared with a type the API accepts")
+	assert.Equal(t, "http", request.Actions[1].ActionType)
+}
+
 func TestConvertActionsToRegistrations_WithID(t *testing.T) {
 	actions := []config.Action{
 		{
@@ -0,0 +1,47 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// StatusError is returned when the API answers with an unexpected HTTP status
+type StatusError struct {
+	StatusCode int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("unexpected status code: %d", e.StatusCode)
+}
+
+// RequestError is returned when a request got no usable response: a network error, a timeout,
+// or server errors until the retries ran out
+type RequestError struct {
+	Err error
+}
+
+func (e *RequestError) Error() string {
+	return "request failed: " + e.Err.Error()
+}
+
+func (e *RequestError) Unwrap() error {
+	return e.Err
+}
+
+// IsRetryable reports whether a request that failed with err may succeed if sent again
+// Failed requests, rate limits, request timeouts and 5xx responses are retryable; other 4xx
+// responses and local errors (such as a body that cannot be encoded) are not
+func IsRetryable(err error) bool {
+	var requestErr *RequestError
+	var rateLimitErr *RateLimitError
+	var statusErr *StatusError
+	switch {
+	case errors.As(err, &requestErr), errors.As(err, &rateLimitErr):
+		return true
+	case errors.As(err, &statusErr):
+		return statusErr.StatusCode >= http.StatusInternalServerError ||
+			statusErr.StatusCode == http.StatusRequestTimeout
+	}
+	return false
+}
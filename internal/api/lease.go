@@ -0,0 +1,22 @@
+package api
+
+import (
+	"context"
+	"net/http"
+)
+
+// leaseExtension is the body of a lease heartbeat for a running delivery
+type leaseExtension struct {
+	ExecutionStatus   string `json:"execution_status"`   // Always "running"
+	VisibilityTimeout int    `json:"visibility_timeout"` // Seconds from now until the delivery may be handed out again
+}
+
+// ExtendLease renews the visibility timeout of a delivery that is still running, so it is not redelivered
+// Uses PATCH /rec/v1/deliveries/:id with execution_status: running. Extensions are not retried:
+// the heartbeat sends the next one well before the lease runs out
+func (c *Client) ExtendLease(ctx context.Context, deliveryID string, visibilityTimeoutSec int) error {
+	return c.patchDelivery(ctx, deliveryID, "lease extension", leaseExtension{
+		ExecutionStatus:   "running",
+		VisibilityTimeout: visibilityTimeoutSec,
+	}, http.StatusOK)
+}
@@ -22,8 +22,9 @@ type Event struct {
 
 // EventsResponse represents the response from GET /rec/v1/deliveries
 type EventsResponse struct {
-	NextCursor *int64  `json:"next_cursor"` // Unix timestamp or null
-	Events     []Event `json:"events"`      // Note: Still called "events" in response for backward compatibility
+	NextCursor *int64    `json:"next_cursor"` // Unix timestamp or null
+	Events     []Event   `json:"events"`      // Note: Still called "events" in response for backward compatibility
+	RateLimit  RateLimit `json:"-"`           // From the response headers
 }
 
 // ExecutionResult represents execution results to send to PATCH /rec/v1/deliveries/:id
@@ -31,17 +32,44 @@ type EventsResponse struct {
 type ExecutionResult struct {
 	// Note: DeliveryID is NOT sent in JSON body - it's in the URL path
 	DeliveryID          string `json:"-"`                               // Delivery UUID (used for URL path, not in body)
-	ExecutionStatus     string `json:"execution_status"`                // "running", "completed", "failed" (required)
+	ExecutionStatus     string `json:"execution_status"`                // "running", "completed" or "failed" (required)
 	CompletedAt         string `json:"completed_at,omitempty"`          // ISO8601 timestamp when completed (for completed status)
 	FailedAt            string `json:"failed_at,omitempty"`             // ISO8601 timestamp when failed (for failed status)
 	RunningAt           string `json:"running_at,omitempty"`            // ISO8601 timestamp when started running
-	ExecutionStdout     string `json:"execution_stdout,omitempty"`      // Script stdout (optional, truncated to 10k chars)
-	ExecutionStderr     string `json:"execution_stderr,omitempty"`      // Script stderr (optional, truncated to 10k chars)
+	ExecutionStdout     string `json:"execution_stdout,omitempty"`      // Script stdout (optional, head and tail kept within 10k bytes)
+	ExecutionStderr     string `json:"execution_stderr,omitempty"`      // Script stderr (optional, head and tail kept within 10k bytes)
 	ExecutionError      string `json:"execution_error,omitempty"`       // Error message if failed (optional)
 	ExecutionActionID   string `json:"execution_action_id,omitempty"`   // Action UUID from event.action.id (optional)
 	ExecutionActionName string `json:"execution_action_name,omitempty"` // Action slug/identifier from config.id (e.g., "test_manual_action_http")
 	ExecutionDurationMs int64  `json:"execution_duration_ms,omitempty"` // Execution duration in milliseconds (optional)
 	ExecutionExitCode   int    `json:"execution_exit_code,omitempty"`   // Exit code (optional, 0 for success)
+
+	// Structured output written by the script (optional)
+	ExecutionSummary string                 `json:"execution_summary,omitempty"` // Short human-readable outcome
+	ExecutionLinks   []ExecutionLink        `json:"execution_links,omitempty"`   // Links shown with the result
+	ExecutionOutputs map[string]interface{} `json:"execution_outputs,omitempty"` // Key/value results
+
+	ExecutionOutputTruncated bool `json:"execution_output_truncated,omitempty"` // Stdout or stderr was cut to the output limit
+
+	// Every run of an action with a retry policy; the fields above describe the last one
+	ExecutionAttempts []ExecutionAttempt `json:"execution_attempts,omitempty"`
+}
+
+// ExecutionAttempt is one run of an action that was retried
+type ExecutionAttempt struct {
+	Status     string `json:"status"`           // "completed" or "failed"
+	StartedAt  string `json:"started_at"`       // ISO8601 timestamp
+	Error      string `json:"error,omitempty"`  // Error message if failed
+	Stderr     string `json:"stderr,omitempty"` // Stderr of the attempt (truncated to 1000 bytes)
+	Attempt    int    `json:"attempt"`          // 1 for the first run
+	ExitCode   int    `json:"exit_code"`        // Exit code or HTTP status
+	DurationMs int64  `json:"duration_ms"`
+}
+
+// ExecutionLink is a link reported with an execution result
+type ExecutionLink struct {
+	Title string `json:"title,omitempty"`
+	URL   string `json:"url"`
 }
 
 // ExecutionResponse represents the response from PATCH /rec/v1/deliveries/:id
@@ -80,7 +108,7 @@ type ActionRegistration struct {
 	Slug        string            `json:"slug"`                  // Action slug (machine identifier)
 	Name        string            `json:"name,omitempty"`        // Display name in UI (optional, backend humanizes if empty)
 	Description string            `json:"description,omitempty"` // Description in UI (optional)
-	ActionType  string            `json:"action_type"`           // "script" or "http"
+	ActionType  string            `json:"action_type"`           // "script" or "http" (pipelines are declared as one of them)
 	Trigger     string            `json:"trigger"`               // Event type (e.g., "action.triggered", "alert.created")
 	Timeout     int               `json:"timeout"`               // Execution timeout
 	Parameters  []ActionParameter `json:"parameters,omitempty"`  // UI form fields (optional, for callable actions)
@@ -0,0 +1,44 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/rootly/edge-connector/internal/ratelimit"
+)
+
+// ErrProgressRateLimited is returned when a progress update is dropped by the client's rate limit
+var ErrProgressRateLimited = errors.New("progress update rate limit reached")
+
+// ExecutionProgress is a live output update for a delivery that is still running
+// Stdout and stderr hold all output so far, so a dropped update loses nothing
+type ExecutionProgress struct {
+	DeliveryID               string `json:"-"`                                    // Delivery UUID (used for URL path, not in body)
+	ExecutionStatus          string `json:"execution_status"`                     // Always "running"
+	ExecutionStdout          string `json:"execution_stdout,omitempty"`           // Script stdout so far
+	ExecutionStderr          string `json:"execution_stderr,omitempty"`           // Script stderr so far
+	ExecutionOutputTruncated bool   `json:"execution_output_truncated,omitempty"` // Stdout or stderr was cut to the output limit
+}
+
+// SetProgressRateLimit caps progress updates across all deliveries (<= 0 disables the cap)
+func (c *Client) SetProgressRateLimit(perSec float64) {
+	if perSec <= 0 {
+		c.progressLimiter = nil
+		return
+	}
+	c.progressLimiter = ratelimit.New(perSec, 0)
+}
+
+// ReportProgress sends live output for a running delivery
+// Uses PATCH /rec/v1/deliveries/:id with execution_status: running. Updates are best effort:
+// they are not retried, and ErrProgressRateLimited is returned without sending when over the rate limit
+func (c *Client) ReportProgress(ctx context.Context, progress ExecutionProgress) error {
+	if c.progressLimiter != nil && !c.progressLimiter.Allow(time.Now()) {
+		return ErrProgressRateLimited
+	}
+
+	progress.ExecutionStatus = "running"
+	return c.patchDelivery(ctx, progress.DeliveryID, "progress update", progress, http.StatusOK, http.StatusCreated)
+}
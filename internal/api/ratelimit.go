@@ -0,0 +1,85 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/go-retryablehttp"
+)
+
+// RateLimit is the API rate limit state reported in response headers
+type RateLimit struct {
+	Reset      time.Time     // When the quota window resets (zero if not reported)
+	RetryAfter time.Duration // Wait requested by the Retry-After header (zero if not reported)
+	Limit      int           // Requests allowed per window (-1 if not reported)
+	Remaining  int           // Requests left in the window (-1 if not reported)
+}
+
+// RateLimitError is returned when the API rejects a request with 429 Too Many Requests
+type RateLimitError struct {
+	RateLimit RateLimit
+}
+
+func (e *RateLimitError) Error() string {
+	reset := ""
+	if !e.RateLimit.Reset.IsZero() {
+		reset = e.RateLimit.Reset.UTC().Format(time.RFC3339)
+	}
+	return fmt.Sprintf("rate limit exceeded (remaining: %d, resets at: %s, retry after: %s)",
+		e.RateLimit.Remaining, reset, e.RateLimit.RetryAfter)
+}
+
+// Wait returns how long to wait before the next request is allowed, or 0 if the headers don't say
+func (r RateLimit) Wait(now time.Time) time.Duration {
+	if r.RetryAfter > 0 {
+		return r.RetryAfter
+	}
+	if r.Remaining == 0 && r.Reset.After(now) {
+		return r.Reset.Sub(now)
+	}
+	return 0
+}
+
+// parseRateLimit reads the X-RateLimit-* and Retry-After headers of a response
+// X-RateLimit-Reset is a Unix timestamp; Retry-After is either seconds or an HTTP date
+func parseRateLimit(resp *http.Response) RateLimit {
+	limit := RateLimit{
+		Limit:     headerInt(resp.Header, "X-RateLimit-Limit"),
+		Remaining: headerInt(resp.Header, "X-RateLimit-Remaining"),
+	}
+
+	if reset, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64); err == nil && reset > 0 {
+		limit.Reset = time.Unix(reset, 0)
+	}
+
+	if retryAfter := resp.Header.Get("Retry-After"); retryAfter != "" {
+		if seconds, err := strconv.Atoi(retryAfter); err == nil && seconds > 0 {
+			limit.RetryAfter = time.Duration(seconds) * time.Second
+		} else if date, err := http.ParseTime(retryAfter); err == nil {
+			limit.RetryAfter = max(time.Until(date), 0)
+		}
+	}
+
+	return limit
+}
+
+// headerInt parses an integer header, returning -1 if it is missing or malformed
+func headerInt(header http.Header, name string) int {
+	value, err := strconv.Atoi(header.Get(name))
+	if err != nil {
+		return -1
+	}
+	return value
+}
+
+// fetchRetryPolicy retries like the default policy, except for 429 responses
+// A rate limited fetch is returned to the poller, which waits for the quota to reset instead
+func fetchRetryPolicy(ctx context.Context, resp *http.Response, err error) (bool, error) {
+	if err == nil && resp != nil && resp.StatusCode == http.StatusTooManyRequests {
+		return false, nil
+	}
+	return retryablehttp.DefaultRetryPolicy(ctx, resp, err)
+}
@@ -0,0 +1,98 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/hashicorp/go-retryablehttp"
+	log "github.com/sirupsen/logrus"
+)
+
+// noRetryPolicy sends a request once; used for best-effort requests the caller repeats on its own schedule
+func noRetryPolicy(ctx context.Context, _ *http.Response, _ error) (bool, error) {
+	return false, ctx.Err()
+}
+
+// newRequest builds an authenticated API request, with a JSON content type when it has a body
+func (c *Client) newRequest(ctx context.Context, method, url string, body []byte) (*retryablehttp.Request, error) {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+
+	req, err := retryablehttp.NewRequestWithContext(ctx, method, url, reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.apiKey))
+	req.Header.Set("User-Agent", c.userAgent)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	return req, nil
+}
+
+// responseError returns a *RateLimitError for a 429 response, a *StatusError for any other status
+// not in okStatuses, and nil otherwise
+func responseError(resp *http.Response, okStatuses ...int) error {
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return &RateLimitError{RateLimit: parseRateLimit(resp)}
+	}
+	if !slices.Contains(okStatuses, resp.StatusCode) {
+		return &StatusError{StatusCode: resp.StatusCode}
+	}
+	return nil
+}
+
+// patchDelivery sends a single PATCH /rec/v1/deliveries/:id with payload as the body
+// The request is sent once, without retries; kind names the update in errors and logs (e.g. "lease extension")
+func (c *Client) patchDelivery(ctx context.Context, deliveryID, kind string, payload any, okStatuses ...int) error {
+	url := fmt.Sprintf("%s%s/deliveries/%s", c.baseURL, c.apiPath, deliveryID)
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s: %w", kind, err)
+	}
+
+	req, err := c.newRequest(ctx, methodPATCH, url, body)
+	if err != nil {
+		return err
+	}
+
+	log.WithFields(log.Fields{
+		fieldMethod:     methodPATCH,
+		fieldURL:        url,
+		fieldDeliveryID: deliveryID,
+		"body_size":     len(body),
+	}).Debug("HTTP request")
+
+	startTime := time.Now()
+	resp, err := c.onceClient.Do(req)
+	duration := time.Since(startTime)
+	if err != nil {
+		return &RequestError{Err: err}
+	}
+	defer resp.Body.Close()
+
+	if _, err := io.Copy(io.Discard, resp.Body); err != nil {
+		log.WithError(err).Debugf("Failed to drain %s response body", kind)
+	}
+
+	log.WithFields(log.Fields{
+		fieldMethod:   methodPATCH,
+		fieldURL:      url,
+		fieldStatus:   resp.StatusCode,
+		fieldDuration: duration.String(),
+	}).Debug("HTTP response")
+
+	c.logRateLimitHeaders(resp)
+
+	return responseError(resp, okStatuses...)
+}
@@ -0,0 +1,148 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// maxStreamMessageBytes bounds a single line of the delivery stream
+const maxStreamMessageBytes = 10 * 1024 * 1024
+
+var (
+	// ErrStreamUnavailable is returned when the API does not offer a delivery stream
+	ErrStreamUnavailable = errors.New("delivery stream unavailable")
+
+	// ErrStreamIdle is returned when nothing, not even a keepalive, arrived within the idle timeout
+	ErrStreamIdle = errors.New("delivery stream idle")
+
+	// ErrStreamClosed is returned when the server ended the stream
+	ErrStreamClosed = errors.New("delivery stream closed by server")
+)
+
+// StreamDeliveries receives deliveries over a server-sent events stream
+// Uses GET /rec/v1/deliveries/stream. onConnected is called once the stream is open, and onEvents for
+// each "deliveries" message (data is an EventsResponse). Blocks until the stream ends and always returns
+// a non-nil error: ErrStreamUnavailable, ErrStreamIdle, ErrStreamClosed or the context's cause
+func (c *Client) StreamDeliveries(ctx context.Context, visibilityTimeout int, idleTimeout time.Duration, onConnected func(), onEvents func([]Event)) error {
+	url := fmt.Sprintf("%s%s/deliveries/stream?visibility_timeout=%d", c.baseURL, c.apiPath, visibilityTimeout)
+
+	streamCtx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	// Also covers a server that accepts the connection but never responds
+	idle := time.AfterFunc(idleTimeout, func() { cancel(ErrStreamIdle) })
+	defer idle.Stop()
+
+	req, err := c.newRequest(streamCtx, methodGET, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	req.Header.Set("Cache-Control", "no-cache")
+
+	log.WithFields(log.Fields{
+		fieldMethod: methodGET,
+		fieldURL:    url,
+	}).Debug("HTTP request")
+
+	// Sent once: reconnects are paced by the caller
+	resp, err := c.onceClient.Do(req)
+	if err != nil {
+		if cause := context.Cause(streamCtx); cause != nil {
+			return cause
+		}
+		return &RequestError{Err: err}
+	}
+	defer resp.Body.Close()
+
+	log.WithFields(log.Fields{
+		fieldMethod: methodGET,
+		fieldURL:    url,
+		fieldStatus: resp.StatusCode,
+	}).Debug("HTTP response")
+
+	c.logRateLimitHeaders(resp)
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound, http.StatusMethodNotAllowed, http.StatusNotImplemented:
+		return fmt.Errorf("%w: status code %d", ErrStreamUnavailable, resp.StatusCode)
+	default:
+		return responseError(resp)
+	}
+	if contentType := resp.Header.Get("Content-Type"); !strings.HasPrefix(contentType, "text/event-stream") {
+		return fmt.Errorf("%w: unexpected content type %q", ErrStreamUnavailable, contentType)
+	}
+
+	idle.Reset(idleTimeout)
+	onConnected()
+
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxStreamMessageBytes)
+
+	var eventType string
+	var data strings.Builder
+	for scanner.Scan() {
+		idle.Reset(idleTimeout)
+		line := scanner.Text()
+
+		switch {
+		case line == "":
+			// A blank line ends the message
+			if data.Len() > 0 {
+				dispatchStreamMessage(eventType, data.String(), onEvents)
+			}
+			eventType = ""
+			data.Reset()
+		case strings.HasPrefix(line, ":"):
+			// Comment, sent by the server as a keepalive
+		default:
+			field, value, _ := strings.Cut(line, ":")
+			value = strings.TrimPrefix(value, " ")
+			switch field {
+			case "event":
+				eventType = value
+			case "data":
+				if data.Len() > 0 {
+					data.WriteByte('\n')
+				}
+				data.WriteString(value)
+			}
+		}
+	}
+
+	if cause := context.Cause(streamCtx); cause != nil {
+		return cause
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read delivery stream: %w", err)
+	}
+	return ErrStreamClosed
+}
+
+// dispatchStreamMessage decodes a "deliveries" message and passes its events on; other messages are ignored
+func dispatchStreamMessage(eventType, data string, onEvents func([]Event)) {
+	if eventType != "" && eventType != "deliveries" {
+		log.WithField("event", eventType).Trace("Ignoring delivery stream message")
+		return
+	}
+
+	var response EventsResponse
+	if err := json.Unmarshal([]byte(data), &response); err != nil {
+		log.WithError(err).Warn("Skipping malformed delivery stream message")
+		return
+	}
+
+	log.WithField("event_count", len(response.Events)).Debug("Received events from delivery stream")
+	if len(response.Events) > 0 {
+		onEvents(response.Events)
+	}
+}
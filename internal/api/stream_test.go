@@ -0,0 +1,118 @@
+package api_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rootly/edge-connector/internal/api"
+)
+
+func TestClient_StreamDeliveries(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/deliveries/stream", r.URL.Path)
+		assert.Equal(t, "30", r.URL.Query().Get("visibility_timeout"))
+		assert.Equal(t, "Bearer test-key", r.Header.Get("Authorization"))
+		assert.Equal(t, "text/event-stream", r.Header.Get("Accept"))
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, ": keepalive\n\n")
+		fmt.Fprint(w, "event: deliveries\ndata: {\"events\": [{\"id\": \"delivery-1\", \"event_type\": \"alert.created\"}]}\n\n")
+		fmt.Fprint(w, "event: status\ndata: {\"connectors\": 2}\n\n")
+		fmt.Fprint(w, "data: {\"events\":\ndata: [{\"id\": \"delivery-2\"}]}\n\n")
+		fmt.Fprint(w, "event: deliveries\ndata: {not json\n\n")
+	}))
+	defer server.Close()
+
+	client := api.NewClient(server.URL, "", "test-key", "test")
+
+	connected := false
+	var received []string
+	err := client.StreamDeliveries(context.Background(), 30, time.Second, func() { connected = true }, func(events []api.Event) {
+		for _, event := range events {
+			received = append(received, event.ID)
+		}
+	})
+
+	require.ErrorIs(t, err, api.ErrStreamClosed)
+	assert.True(t, connected)
+	assert.Equal(t, []string{"delivery-1", "delivery-2"}, received,
+		"Other message types and malformed messages are skipped; data lines are joined")
+}
+
+func TestClient_StreamDeliveries_Unavailable(t *testing.T) {
+	attemptCount := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attemptCount++
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	client := api.NewClient(server.URL, "", "test-key", "test")
+
+	err := client.StreamDeliveries(context.Background(), 30, time.Second, func() {
+		t.Error("Stream should not be reported as connected")
+	}, func([]api.Event) {})
+	require.ErrorIs(t, err, api.ErrStreamUnavailable)
+	assert.Equal(t, 1, attemptCount, "Reconnects are paced by the caller, not retried")
+}
+
+func TestClient_StreamDeliveries_RateLimited(t *testing.T) {
+	attemptCount := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attemptCount++
+		w.Header().Set("Retry-After", "15")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	client := api.NewClient(server.URL, "", "test-key", "test")
+
+	err := client.StreamDeliveries(context.Background(), 30, time.Second, func() {
+		t.Error("Stream should not be reported as connected")
+	}, func([]api.Event) {})
+
+	var rateLimited *api.RateLimitError
+	require.ErrorAs(t, err, &rateLimited)
+	assert.Equal(t, 15*time.Second, rateLimited.RateLimit.RetryAfter)
+	assert.Equal(t, 1, attemptCount)
+}
+
+func TestClient_StreamDeliveries_Idle(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(http.StatusOK)
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	client := api.NewClient(server.URL, "", "test-key", "test")
+
+	start := time.Now()
+	err := client.StreamDeliveries(context.Background(), 30, 50*time.Millisecond, func() {}, func([]api.Event) {})
+	require.ErrorIs(t, err, api.ErrStreamIdle)
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestClient_StreamDeliveries_Canceled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(http.StatusOK)
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	client := api.NewClient(server.URL, "", "test-key", "test")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	err := client.StreamDeliveries(ctx, 30, time.Minute, cancel, func([]api.Event) {})
+	require.ErrorIs(t, err, context.Canceled)
+}
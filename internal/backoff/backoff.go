@@ -0,0 +1,30 @@
+package backoff
+
+import (
+	"math"
+	"time"
+)
+
+// Backoff strategies
+const (
+	Exponential = "exponential" // base * 2^retry
+	Linear      = "linear"      // base * retry
+)
+
+// Delay returns the wait before the given retry (1 for the first retry), capped at maxDelay
+// Any strategy other than Exponential is treated as Linear
+func Delay(strategy string, base time.Duration, retry int, maxDelay time.Duration) time.Duration {
+	var delay time.Duration
+	if strategy == Exponential {
+		multiplier := math.Pow(2, float64(retry))
+		delay = time.Duration(float64(base) * multiplier)
+	} else {
+		delay = base * time.Duration(retry)
+	}
+
+	// Also guards against overflow for large retry counts
+	if delay > maxDelay || delay < 0 {
+		delay = maxDelay
+	}
+	return delay
+}
@@ -0,0 +1,34 @@
+package backoff_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/rootly/edge-connector/internal/backoff"
+)
+
+func TestDelay(t *testing.T) {
+	tests := []struct {
+		name     string
+		strategy string
+		retry    int
+		expected time.Duration
+	}{
+		{"exponential first retry", backoff.Exponential, 1, 2 * time.Second},
+		{"exponential third retry", backoff.Exponential, 3, 8 * time.Second},
+		{"exponential capped", backoff.Exponential, 10, time.Minute},
+		{"exponential overflow capped", backoff.Exponential, 200, time.Minute},
+		{"linear first retry", backoff.Linear, 1, time.Second},
+		{"linear third retry", backoff.Linear, 3, 3 * time.Second},
+		{"linear capped", backoff.Linear, 100, time.Minute},
+		{"unknown strategy is linear", "", 2, 2 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, backoff.Delay(tt.strategy, time.Second, tt.retry, time.Minute))
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package breaker
+
+import (
+	"sync"
+	"time"
+)
+
+// State is the state of a circuit breaker
+type State int
+
+// Breaker states; the values are exported as the state gauge
+const (
+	Closed   State = iota // Requests pass
+	HalfOpen              // One trial request passes after the cooldown
+	Open                  // Requests fail fast
+)
+
+// String returns the state's name as used in logs
+func (s State) String() string {
+	switch s {
+	case HalfOpen:
+		return "half_open"
+	case Open:
+		return "open"
+	default:
+		return "closed"
+	}
+}
+
+// Breaker opens after a number of consecutive failures and fails fast until a cooldown has passed
+// It then half-opens: a single trial request is let through, closing the breaker on success
+// and opening it again on failure
+type Breaker struct {
+	openedAt  time.Time
+	onChange  func(State)
+	cooldown  time.Duration
+	threshold int
+	failures  int
+	state     State
+	probing   bool // The half-open trial request is in flight
+	mu        sync.Mutex
+}
+
+// New creates a closed breaker that opens after threshold consecutive failures
+// onChange, if set, is called with the new state whenever it changes
+func New(threshold int, cooldown time.Duration, onChange func(State)) *Breaker {
+	return &Breaker{threshold: threshold, cooldown: cooldown, onChange: onChange}
+}
+
+// Allow reports whether a request may be sent at the given time
+// Every allowed request must be followed by Record or Release
+func (b *Breaker) Allow(now time.Time) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case Open:
+		if now.Sub(b.openedAt) < b.cooldown {
+			return false
+		}
+		b.setStateLocked(HalfOpen)
+		b.probing = true
+		return true
+	case HalfOpen:
+		if b.probing {
+			return false
+		}
+		b.probing = true
+		return true
+	default:
+		return true
+	}
+}
+
+// Record counts the outcome of an allowed request
+func (b *Breaker) Record(success bool, now time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probing = false
+	if success {
+		b.failures = 0
+		b.setStateLocked(Closed)
+		return
+	}
+
+	b.failures++
+	if b.state == HalfOpen || b.failures >= b.threshold {
+		b.openedAt = now
+		b.setStateLocked(Open)
+	}
+}
+
+// Release ends an allowed request without counting it, e.g. when it was canceled by shutdown
+func (b *Breaker) Release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+}
+
+// State returns the current state
+func (b *Breaker) State() State {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state
+}
+
+// RetryAt returns when an open breaker lets the next trial request through
+func (b *Breaker) RetryAt() time.Time {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.openedAt.Add(b.cooldown)
+}
+
+// setStateLocked changes the state and notifies onChange
+// Caller must hold b.mu
+func (b *Breaker) setStateLocked(state State) {
+	if b.state == state {
+		return
+	}
+	b.state = state
+	if b.onChange != nil {
+		b.onChange(state)
+	}
+}
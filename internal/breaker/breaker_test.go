@@ -0,0 +1,88 @@
+package breaker_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/rootly/edge-connector/internal/breaker"
+)
+
+func TestBreaker_OpensAfterConsecutiveFailures(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	var changes []breaker.State
+	b := breaker.New(3, 30*time.Second, func(s breaker.State) { changes = append(changes, s) })
+
+	for range 2 {
+		assert.True(t, b.Allow(now))
+		b.Record(false, now)
+	}
+	assert.Equal(t, breaker.Closed, b.State())
+
+	// A success resets the count
+	assert.True(t, b.Allow(now))
+	b.Record(true, now)
+	for range 2 {
+		assert.True(t, b.Allow(now))
+		b.Record(false, now)
+	}
+	assert.Equal(t, breaker.Closed, b.State())
+
+	assert.True(t, b.Allow(now))
+	b.Record(false, now)
+	assert.Equal(t, breaker.Open, b.State())
+	assert.False(t, b.Allow(now.Add(10*time.Second)), "Open breaker fails fast")
+	assert.Equal(t, now.Add(30*time.Second), b.RetryAt())
+	assert.Equal(t, []breaker.State{breaker.Open}, changes)
+}
+
+func TestBreaker_HalfOpen(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	var changes []breaker.State
+	b := breaker.New(1, 30*time.Second, func(s breaker.State) { changes = append(changes, s) })
+
+	assert.True(t, b.Allow(now))
+	b.Record(false, now)
+	assert.Equal(t, breaker.Open, b.State())
+
+	// After the cooldown only one trial request goes through
+	now = now.Add(30 * time.Second)
+	assert.True(t, b.Allow(now))
+	assert.Equal(t, breaker.HalfOpen, b.State())
+	assert.False(t, b.Allow(now), "Only one trial request while half-open")
+
+	// A failed trial opens the breaker for another cooldown
+	b.Record(false, now)
+	assert.Equal(t, breaker.Open, b.State())
+	assert.False(t, b.Allow(now.Add(29*time.Second)))
+
+	// A successful trial closes it
+	now = now.Add(30 * time.Second)
+	assert.True(t, b.Allow(now))
+	b.Record(true, now)
+	assert.Equal(t, breaker.Closed, b.State())
+	assert.True(t, b.Allow(now))
+
+	assert.Equal(t, []breaker.State{breaker.Open, breaker.HalfOpen, breaker.Open, breaker.HalfOpen, breaker.Closed}, changes)
+}
+
+func TestBreaker_ReleaseFreesTrial(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	b := breaker.New(1, time.Second, nil)
+
+	assert.True(t, b.Allow(now))
+	b.Record(false, now)
+
+	now = now.Add(time.Second)
+	assert.True(t, b.Allow(now))
+	b.Release()
+	assert.Equal(t, breaker.HalfOpen, b.State(), "Released trial leaves the state unchanged")
+	assert.True(t, b.Allow(now), "Next request becomes the trial")
+}
+
+func TestState_String(t *testing.T) {
+	assert.Equal(t, "closed", breaker.Closed.String())
+	assert.Equal(t, "half_open", breaker.HalfOpen.String())
+	assert.Equal(t, "open", breaker.Open.String())
+}
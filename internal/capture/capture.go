@@ -0,0 +1,170 @@
+package capture
+
+import (
+	"fmt"
+	"io"
+	"unicode/utf8"
+)
+
+// Buffer is an io.Writer that keeps the head and tail of everything written to it
+// Memory use is bounded by the limit no matter how much is written; the dropped middle
+// is replaced by a marker when the content is read back, and the marker counts towards the limit
+type Buffer struct {
+	spill     io.Writer // Receives every byte written, if set
+	spillErr  error
+	spillPath string
+	head      []byte
+	tail      []byte // Last bytes written after the head is full, compacted as it grows
+	limit     int    // Maximum bytes kept (<= 0 keeps everything)
+	total     int64  // Total bytes written
+}
+
+// NewBuffer creates a buffer that keeps at most limit bytes (<= 0 keeps everything)
+func NewBuffer(limit int) *Buffer {
+	return &Buffer{limit: limit}
+}
+
+// SpillTo also writes the full, untruncated output to w
+// The path is mentioned in the truncation marker so the full output can be found
+func (b *Buffer) SpillTo(w io.Writer, path string) {
+	b.spill = w
+	b.spillPath = path
+}
+
+// Write keeps the bytes that fit in the head and tail; it never fails
+func (b *Buffer) Write(p []byte) (int, error) {
+	b.write(p)
+	return len(p), nil
+}
+
+func (b *Buffer) write(p []byte) {
+	b.total += int64(len(p))
+
+	if b.spill != nil && b.spillErr == nil {
+		if _, err := b.spill.Write(p); err != nil {
+			b.spillErr = err
+		}
+	}
+
+	if b.limit <= 0 {
+		b.head = append(b.head, p...)
+		return
+	}
+
+	rest := p
+	if room := b.headLimit() - len(b.head); room > 0 {
+		n := min(room, len(rest))
+		b.head = append(b.head, rest[:n]...)
+		rest = rest[n:]
+	}
+	if len(rest) == 0 {
+		return
+	}
+
+	tailLimit := b.limit - b.headLimit()
+	if len(rest) >= tailLimit {
+		b.tail = append(b.tail[:0], rest[len(rest)-tailLimit:]...)
+		return
+	}
+	b.tail = append(b.tail, rest...)
+	// Compact only once the tail doubles so small writes stay cheap
+	if len(b.tail) > 2*tailLimit {
+		b.tail = append(b.tail[:0], b.tail[len(b.tail)-tailLimit:]...)
+	}
+}
+
+// Truncated reports whether any output was dropped
+func (b *Buffer) Truncated() bool {
+	return b.limit > 0 && b.total > int64(b.limit)
+}
+
+// Len returns the total number of bytes written, including dropped ones
+func (b *Buffer) Len() int64 {
+	return b.total
+}
+
+// Limit returns the maximum number of bytes kept (<= 0 keeps everything)
+func (b *Buffer) Limit() int {
+	return b.limit
+}
+
+// SpillErr returns the first error writing the full output, if any
+func (b *Buffer) SpillErr() error {
+	return b.spillErr
+}
+
+// String returns the kept output, with a marker in place of the dropped middle
+// The result fits in the limit unless the limit is smaller than the marker itself, so
+// truncating it again is a no-op. The cut points are moved to rune boundaries so the
+// result stays valid UTF-8
+func (b *Buffer) String() string {
+	if !b.Truncated() {
+		return string(b.head) + string(b.tail)
+	}
+
+	// The marker is sized for the worst case, so the real one is never longer
+	room := max(b.limit-len(b.marker(b.total)), 0)
+	headRoom := room - room/2
+	tailRoom := room / 2
+
+	head := b.head[:min(len(b.head), headRoom)]
+	tail := b.tail
+	if len(tail) > tailRoom {
+		tail = tail[len(tail)-tailRoom:]
+	}
+	head = trimIncompleteRuneEnd(head)
+	tail = trimIncompleteRuneStart(tail)
+
+	dropped := b.total - int64(len(head)) - int64(len(tail))
+	return string(head) + b.marker(dropped) + string(tail)
+}
+
+func (b *Buffer) marker(dropped int64) string {
+	if b.spill != nil && b.spillErr == nil && b.spillPath != "" {
+		return fmt.Sprintf("\n... [%d bytes truncated, full output in %s] ...\n", dropped, b.spillPath)
+	}
+	return fmt.Sprintf("\n... [%d bytes truncated] ...\n", dropped)
+}
+
+// headLimit is the number of leading bytes kept; the rest of the limit holds the tail
+func (b *Buffer) headLimit() int {
+	return b.limit - b.limit/2
+}
+
+// Truncate keeps the head and tail of s, with a marker in between, within limit bytes
+// Returns s unchanged and false if it already fits
+func Truncate(s string, limit int) (string, bool) {
+	if limit <= 0 || len(s) <= limit {
+		return s, false
+	}
+	buf := NewBuffer(limit)
+	buf.write([]byte(s))
+	return buf.String(), true
+}
+
+// trimIncompleteRuneEnd drops a multi-byte character cut off at the end of p
+func trimIncompleteRuneEnd(p []byte) []byte {
+	for i := len(p) - 1; i >= 0 && i >= len(p)-utf8.UTFMax; i-- {
+		if utf8.RuneStart(p[i]) {
+			if !utf8.FullRune(p[i:]) {
+				return p[:i]
+			}
+			return p
+		}
+	}
+	return p
+}
+
+// trimIncompleteRuneStart drops the continuation bytes of a character cut off at the start of p
+func trimIncompleteRuneStart(p []byte) []byte {
+	for i := 0; i < len(p) && i < utf8.UTFMax; i++ {
+		if utf8.RuneStart(p[i]) {
+			return p[i:]
+		}
+	}
+	if len(p) < utf8.UTFMax {
+		// Only continuation bytes are left
+		return p[len(p):]
+	}
+	return p
+}
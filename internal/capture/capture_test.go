@@ -0,0 +1,110 @@
+package capture_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rootly/edge-connector/internal/capture"
+)
+
+func TestBuffer_UnderLimit(t *testing.T) {
+	buf := capture.NewBuffer(100)
+	_, err := buf.Write([]byte("hello "))
+	require.NoError(t, err)
+	_, err = buf.Write([]byte("world"))
+	require.NoError(t, err)
+
+	assert.False(t, buf.Truncated())
+	assert.Equal(t, "hello world", buf.String())
+	assert.Equal(t, int64(11), buf.Len())
+}
+
+func TestBuffer_KeepsHeadAndTail(t *testing.T) {
+	buf := capture.NewBuffer(40)
+	_, err := buf.Write([]byte(strings.Repeat("abcdefghijklmnopqrstuvwxyz", 2)))
+	require.NoError(t, err)
+
+	assert.True(t, buf.Truncated())
+	assert.Equal(t, "abcde\n... [42 bytes truncated] ...\nvwxyz", buf.String())
+	assert.Len(t, buf.String(), 40, "The marker should count towards the limit")
+}
+
+func TestBuffer_ManySmallWrites(t *testing.T) {
+	buf := capture.NewBuffer(60)
+	for i := 0; i < 10000; i++ {
+		_, err := buf.Write([]byte("line\n"))
+		require.NoError(t, err)
+	}
+
+	assert.True(t, buf.Truncated())
+	assert.Equal(t, int64(50000), buf.Len())
+	out := buf.String()
+	assert.True(t, strings.HasPrefix(out, "line\nline\n"))
+	assert.True(t, strings.HasSuffix(out, "line\nline\n"))
+	assert.Contains(t, out, "[49973 bytes truncated]")
+	assert.LessOrEqual(t, len(out), 60)
+}
+
+func TestBuffer_UTF8Boundaries(t *testing.T) {
+	// Every character is 3 bytes, so an odd limit cuts characters on both sides
+	input := strings.Repeat("日本語", 20)
+
+	for limit := 1; limit <= 16; limit++ {
+		buf := capture.NewBuffer(limit)
+		_, err := buf.Write([]byte(input))
+		require.NoError(t, err)
+
+		out := buf.String()
+		assert.True(t, utf8.ValidString(out), "limit %d produced invalid UTF-8: %q", limit, out)
+	}
+}
+
+func TestBuffer_SpillTo(t *testing.T) {
+	var full bytes.Buffer
+	buf := capture.NewBuffer(79)
+	buf.SpillTo(&full, "/var/log/rec/run-stdout.log")
+
+	input := strings.Repeat("0123456789", 10)
+	_, err := buf.Write([]byte(input))
+	require.NoError(t, err)
+
+	assert.Equal(t, input, full.String(), "The spill writer should receive the full output")
+	assert.Equal(t, "01\n... [96 bytes truncated, full output in /var/log/rec/run-stdout.log] ...\n89", buf.String())
+	assert.NoError(t, buf.SpillErr())
+}
+
+func TestBuffer_NoLimit(t *testing.T) {
+	buf := capture.NewBuffer(0)
+	_, err := buf.Write([]byte(strings.Repeat("x", 1000)))
+	require.NoError(t, err)
+
+	assert.False(t, buf.Truncated())
+	assert.Len(t, buf.String(), 1000)
+}
+
+func TestTruncate(t *testing.T) {
+	out, truncated := capture.Truncate("short", 10)
+	assert.False(t, truncated)
+	assert.Equal(t, "short", out)
+
+	out, truncated = capture.Truncate(strings.Repeat("a", 50)+strings.Repeat("b", 50), 51)
+	assert.True(t, truncated)
+	assert.Equal(t, strings.Repeat("a", 10)+"\n... [80 bytes truncated] ...\n"+strings.Repeat("b", 10), out)
+
+	again, truncated := capture.Truncate(out, 51)
+	assert.False(t, truncated, "Truncated output already fits the limit")
+	assert.Equal(t, out, again)
+}
+
+func TestBuffer_LimitSmallerThanMarker(t *testing.T) {
+	buf := capture.NewBuffer(8)
+	_, err := buf.Write([]byte("abcdefghijklmnopqrstuvwxyz"))
+	require.NoError(t, err)
+
+	assert.Equal(t, "\n... [26 bytes truncated] ...\n", buf.String(), "Only the marker should be kept")
+}
@@ -1,5 +1,18 @@
 package config
 
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"math"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
 // Config represents the main configuration file structure
 type Config struct {
 	Logging  LoggingConfig  `yaml:"logging"`
@@ -9,6 +22,14 @@ type Config struct {
 	App      AppConfig      `yaml:"app"`
 	Metrics  MetricsConfig  `yaml:"metrics"`
 	Security SecurityConfig `yaml:"security"`
+	State    StateConfig    `yaml:"state"`
+	Outbox   OutboxConfig   `yaml:"outbox"`
+	Dedup    DedupConfig    `yaml:"dedup"`
+	Reload   ReloadConfig   `yaml:"reload"`
+	Output   OutputConfig   `yaml:"output"`
+	Progress ProgressConfig `yaml:"progress"`
+
+	HTTPTargets HTTPTargetsConfig `yaml:"http_targets"`
 }
 
 // AppConfig contains application metadata
@@ -25,12 +46,35 @@ type RootlyConfig struct {
 
 // PollerConfig contains polling engine configuration
 type PollerConfig struct {
+	Mode                  string `yaml:"mode"`          // How deliveries are received: poll or stream (default: poll)
 	RetryBackoff          string `yaml:"retry_backoff"` // exponential or linear
 	PollingWaitIntervalMs int    `yaml:"polling_wait_interval_ms"`
 	VisibilityTimeoutSec  int    `yaml:"visibility_timeout_sec"`
+	HeartbeatIntervalSec  int    `yaml:"heartbeat_interval_sec"` // How often claimed deliveries renew their lease (default: a third of visibility_timeout_sec)
 	MaxNumberOfMessages   int    `yaml:"max_number_of_messages"`
+	MaxPagesPerPoll       int    `yaml:"max_pages_per_poll"` // Pages fetched per poll cycle while the API returns a next cursor (default: 10)
 	MaxRetries            int    `yaml:"max_retries"`
 	RetryOnError          bool   `yaml:"retry_on_error"`
+
+	// Adaptive polling: the interval shrinks while fetches come back full and grows while idle
+	MinPollingIntervalMs int     `yaml:"min_polling_interval_ms"` // Shortest interval while deliveries are backed up (default: 1000)
+	MaxPollingIntervalMs int     `yaml:"max_polling_interval_ms"` // Longest interval while idle (default: 6 x polling_wait_interval_ms)
+	PollingJitter        float64 `yaml:"polling_jitter"`          // Random +/- fraction applied to every interval (0 disables, default: 0.1)
+
+	// Delivery stream (mode: stream)
+	Stream StreamConfig `yaml:"stream"`
+}
+
+// Delivery modes (poller.mode)
+const (
+	PollerModePoll   = "poll"   // Fetch deliveries every polling_wait_interval_ms
+	PollerModeStream = "stream" // Receive deliveries over a server-sent events stream, polling while it is unavailable
+)
+
+// StreamConfig contains settings for receiving deliveries over a stream (poller.mode: stream)
+type StreamConfig struct {
+	IdleTimeoutSec  int `yaml:"idle_timeout_sec"`  // Reconnect when nothing, not even a keepalive, arrives for this long (default: 60)
+	ReconnectMaxSec int `yaml:"reconnect_max_sec"` // Upper bound on the reconnect backoff (default: 300)
 }
 
 // PoolConfig contains worker pool configuration
@@ -40,6 +84,7 @@ type PoolConfig struct {
 	QueueSize          int `yaml:"queue_size"`
 	KeepAliveTimeMs    int `yaml:"keep_alive_time_ms"`
 	MonitoringPeriodMs int `yaml:"monitoring_period_ms"`
+	ShutdownTimeoutSec int `yaml:"shutdown_timeout_sec"` // How long in-flight actions may run after a shutdown signal (default: 30)
 }
 
 // SecurityConfig contains security and script execution settings
@@ -49,6 +94,84 @@ type SecurityConfig struct {
 	ScriptTimeout      int               `yaml:"script_timeout"`
 }
 
+// StateConfig contains settings for state persisted on local disk
+type StateConfig struct {
+	Dir string `yaml:"dir"` // Directory for durable connector state (default: /tmp/rec-state)
+}
+
+// OutboxConfig contains settings for the execution report outbox
+// Reports that fail to reach the Rootly API are stored on disk and replayed in the background
+type OutboxConfig struct {
+	ReplayIntervalMs int `yaml:"replay_interval_ms"` // How often pending reports are retried (default: 10000)
+	MaxBackoffSec    int `yaml:"max_backoff_sec"`    // Maximum delay between retries of a single report (default: 300)
+	MaxEntries       int `yaml:"max_entries"`        // Maximum pending reports kept on disk, oldest dropped first (default: 10000)
+	MaxAttempts      int `yaml:"max_attempts"`       // Replays of a report before it is moved to the dead letter file (default: 50)
+}
+
+// DedupConfig contains settings for skipping deliveries that were already seen
+// A delivery is a duplicate if its delivery ID or event ID was seen within the TTL
+type DedupConfig struct {
+	TTLSec  int  `yaml:"ttl_sec"` // How long delivery and event IDs are remembered; 0 disables deduplication (default: 3600)
+	Persist bool `yaml:"persist"` // Keep seen IDs in the state directory across restarts (default: false)
+}
+
+// ReloadConfig contains settings for hot reloading actions.yml
+// Actions are always reloaded on SIGHUP; the file watcher is optional
+type ReloadConfig struct {
+	WatchFile       bool `yaml:"watch_file"`        // Reload automatically when actions.yml changes on disk (default: false)
+	WatchIntervalMs int  `yaml:"watch_interval_ms"` // How often the watcher checks actions.yml for changes (default: 5000)
+}
+
+// OutputConfig contains limits for captured script output and HTTP response bodies
+// Output past the cap keeps its head and tail with a truncation marker in between
+type OutputConfig struct {
+	OverflowDir string `yaml:"overflow_dir"` // Keep the full output of truncated streams in this directory (default: disabled)
+	MaxBytes    int    `yaml:"max_bytes"`    // Cap per stream (stdout, stderr, response body) (default: 10000)
+}
+
+// ProgressConfig contains settings for live output updates of actions with progress: true
+// Output is batched per delivery and sent while the delivery is still running
+type ProgressConfig struct {
+	IntervalMs       int     `yaml:"interval_ms"`         // How often a running action's new output is sent (default: 5000)
+	MaxUpdatesPerSec float64 `yaml:"max_updates_per_sec"` // Cap on progress updates across all deliveries (0 disables, default: 5)
+}
+
+// HTTPTargetsConfig contains limits on requests sent by HTTP actions, per destination host
+// Limits at the top level apply to each host separately; entries under hosts override them
+type HTTPTargetsConfig struct {
+	Hosts            map[string]HTTPTargetConfig `yaml:"hosts"` // Per-host overrides, keyed by host name
+	HTTPTargetConfig `yaml:",inline"`
+}
+
+// HTTPTargetConfig contains the limits for one destination host
+type HTTPTargetConfig struct {
+	RateLimit      *RateLimit            `yaml:"rate_limit"`      // Requests allowed to the host (default: unlimited)
+	CircuitBreaker *CircuitBreakerConfig `yaml:"circuit_breaker"` // Fail fast while the host keeps failing (default: disabled)
+}
+
+// CircuitBreakerConfig contains settings for a circuit breaker on a destination host
+// Request errors, 429 and 5xx responses count as failures
+type CircuitBreakerConfig struct {
+	FailureThreshold int `yaml:"failure_threshold"` // Consecutive failures that open the breaker (required)
+	CooldownSec      int `yaml:"cooldown_sec"`      // How long an open breaker fails fast before a trial request (default: 30)
+}
+
+// For returns the limits for a host: its entry under hosts, falling back to the top-level limits
+func (c *HTTPTargetsConfig) For(host string) HTTPTargetConfig {
+	target := c.HTTPTargetConfig
+	override, ok := c.Hosts[strings.ToLower(host)]
+	if !ok {
+		return target
+	}
+	if override.RateLimit != nil {
+		target.RateLimit = override.RateLimit
+	}
+	if override.CircuitBreaker != nil {
+		target.CircuitBreaker = override.CircuitBreaker
+	}
+	return target
+}
+
 // LoggingConfig contains logging configuration
 type LoggingConfig struct {
 	Level  string `yaml:"level"`  // trace, debug, info, warn, error
@@ -74,10 +197,14 @@ type MetricsConfig struct {
 // ActionsConfig represents the actions configuration file structure
 // New simplified format with on/callable sections
 type ActionsConfig struct {
-	On       map[string]OnAction       `yaml:"on"`       // Automatic actions (event type → action)
+	On       map[string]OnAction       `yaml:"on"`       // Automatic actions (event type → action or pipeline of steps)
 	Callable map[string]CallableAction `yaml:"callable"` // Callable actions (slug → action)
 	Defaults ActionDefaults            `yaml:"defaults"` // Global defaults
 
+	// Automatic event types wildcard triggers are registered for, in addition to the built-in ones
+	// Lists event types Rootly added after this connector was released (e.g. "alert.resolved")
+	EventTypes []string `yaml:"event_types"`
+
 	// Internal use: converted to unified array format after parsing
 	Actions []Action `yaml:"-"`
 }
@@ -87,22 +214,43 @@ type ActionDefaults struct {
 	Timeout    int               `yaml:"timeout"`     // Default timeout (seconds)
 	SourceType string            `yaml:"source_type"` // Default source type (local/git)
 	Env        map[string]string `yaml:"env"`         // Default environment variables
+	InheritEnv []string          `yaml:"inherit_env"` // Connector environment variables passed to every script (names or patterns)
 }
 
 // OnAction represents an automatic action (no UI, triggered by events)
+// A list of actions (or a mapping with steps) under one event type forms a pipeline
 type OnAction struct {
-	Type       string            `yaml:"type"`        // "script" or "http" (default: script)
-	SourceType string            `yaml:"source_type"` // "local" or "git" (default: local)
-	Script     string            `yaml:"script"`      // Script path
-	HTTP       *HTTPAction       `yaml:"http"`        // HTTP configuration
-	GitOptions *GitOptions       `yaml:"git_options"` // Git options
-	Parameters map[string]string `yaml:"parameters"`  // Template mappings
-	Env        map[string]string `yaml:"env"`         // Environment variables
-	Flags      map[string]string `yaml:"flags"`       // Command-line flags
-	Args       []string          `yaml:"args"`        // Script arguments
-	Timeout    int               `yaml:"timeout"`     // Timeout override
-	Stdout     string            `yaml:"stdout"`      // Stdout redirect
-	Stderr     string            `yaml:"stderr"`      // Stderr redirect
+	ID         string            `yaml:"id"`               // Step identifier (pipeline steps only, default: step_<n>)
+	Type       string            `yaml:"type"`             // "script" or "http" (default: script)
+	SourceType string            `yaml:"source_type"`      // "local" or "git" (default: local)
+	Script     string            `yaml:"script"`           // Script path
+	HTTP       *HTTPAction       `yaml:"http"`             // HTTP configuration
+	GitOptions *GitOptions       `yaml:"git_options"`      // Git options
+	Parameters map[string]string `yaml:"parameters"`       // Template mappings
+	Env        map[string]string `yaml:"env"`              // Environment variables
+	Flags      map[string]string `yaml:"flags"`            // Command-line flags
+	Args       []string          `yaml:"args"`             // Script arguments
+	Timeout    int               `yaml:"timeout"`          // Timeout override
+	Stdout     string            `yaml:"stdout"`           // Stdout redirect
+	Stderr     string            `yaml:"stderr"`           // Stderr redirect
+	Input      string            `yaml:"input"`            // How the script receives its input document: env, stdin or file (default: env)
+	MaxOutput  int               `yaml:"max_output_bytes"` // Cap on captured output per stream (default: output.max_bytes)
+	Progress   bool              `yaml:"progress"`         // Send live output while the script runs
+	Retry      *RetryPolicy      `yaml:"retry"`            // Re-run the action when it fails
+	Idempotent bool              `yaml:"idempotent"`       // Safe to re-run if interrupted by a restart
+	When       string            `yaml:"when"`             // Liquid condition on the event payload (e.g. labels.severity == "critical")
+	InheritEnv []string          `yaml:"inherit_env"`      // Connector environment variables passed to the script (names or patterns like AWS_*)
+
+	// Concurrency and rate limits
+	Concurrency        int        `yaml:"concurrency"`          // Max concurrent runs (per mutex_key if set)
+	MutexKey           string     `yaml:"mutex_key"`            // Template for the key runs are limited by (e.g. "{{ parameters.service_name }}")
+	OnConcurrencyLimit string     `yaml:"on_concurrency_limit"` // queue, reject or drop (default: queue)
+	RateLimit          *RateLimit `yaml:"rate_limit"`           // Max deliveries per second; deliveries over it are reported as failed
+
+	// Pipelines
+	Steps           []OnAction `yaml:"steps"`             // Pipeline steps
+	Parallel        bool       `yaml:"parallel"`          // Run pipeline steps concurrently instead of in order
+	ContinueOnError bool       `yaml:"continue_on_error"` // Pipeline step failure does not stop or fail the pipeline
 }
 
 // CallableAction represents a user-triggered action (shows in UI)
@@ -123,7 +271,18 @@ type CallableAction struct {
 	Timeout              int                   `yaml:"timeout"`               // Timeout override
 	Stdout               string                `yaml:"stdout"`                // Stdout redirect
 	Stderr               string                `yaml:"stderr"`                // Stderr redirect
+	Input                string                `yaml:"input"`                 // How the script receives its input document: env, stdin or file (default: env)
+	MaxOutput            int                   `yaml:"max_output_bytes"`      // Cap on captured output per stream (default: output.max_bytes)
+	Progress             bool                  `yaml:"progress"`              // Send live output while the script runs
+	Retry                *RetryPolicy          `yaml:"retry"`                 // Re-run the action when it fails
+	Concurrency          int                   `yaml:"concurrency"`           // Max concurrent runs (per mutex_key if set)
+	MutexKey             string                `yaml:"mutex_key"`             // Template for the key runs are limited by (e.g. "{{ parameters.service_name }}")
+	OnConcurrencyLimit   string                `yaml:"on_concurrency_limit"`  // queue, reject or drop (default: queue)
+	RateLimit            *RateLimit            `yaml:"rate_limit"`            // Max deliveries per second; deliveries over it are reported as failed
 	Auth                 Authorization         `yaml:"authorization"`         // Authorization rules
+	Idempotent           bool                  `yaml:"idempotent"`            // Safe to re-run if interrupted by a restart
+	When                 string                `yaml:"when"`                  // Liquid condition on the event payload
+	InheritEnv           []string              `yaml:"inherit_env"`           // Connector environment variables passed to the script (names or patterns like AWS_*)
 }
 
 // ParameterDefinition represents a parameter definition for callable actions
@@ -136,6 +295,167 @@ type ParameterDefinition struct {
 	Options     []string    `yaml:"options,omitempty" json:"options,omitempty"`         // Valid options (required for "list" type, not allowed for other types)
 }
 
+// Policies for deliveries that arrive while an action is at its concurrency limit
+const (
+	ConcurrencyQueue  = "queue"  // Wait for a running delivery to finish
+	ConcurrencyReject = "reject" // Report the delivery as failed
+	ConcurrencyDrop   = "drop"   // Report the delivery as skipped (a duplicate of the running one)
+)
+
+// ConcurrencyLimit returns the max concurrent runs per mutex key (0 means unlimited)
+// A mutex_key without concurrency allows one run per key
+func (a *Action) ConcurrencyLimit() int {
+	if a.Concurrency == 0 && a.MutexKey != "" {
+		return 1
+	}
+	return a.Concurrency
+}
+
+// RateLimit is a token bucket limit: calls refill at per_sec up to burst at once
+type RateLimit struct {
+	PerSec float64 `yaml:"per_sec"` // Average calls allowed per second (required)
+	Burst  int     `yaml:"burst"`   // Calls allowed at once (default: per_sec rounded up)
+}
+
+// Script input modes
+// Parameters are always exposed as REC_PARAM_* variables; stdin and file also pass a JSON document
+// with the parameters, the full event and action metadata
+const (
+	InputEnv   = "env"
+	InputStdin = "stdin"
+	InputFile  = "file"
+)
+
+// Parameter types supported in parameter_definitions
+const (
+	ParameterTypeString  = "string"
+	ParameterTypeNumber  = "number"
+	ParameterTypeBoolean = "boolean"
+	ParameterTypeList    = "list"
+)
+
+// Coerce converts a value to the parameter's declared type
+// Numbers become float64, booleans bool, and strings and list selections string.
+// Numeric and boolean strings (e.g. "42", "true") are accepted since form values often arrive as text
+func (p ParameterDefinition) Coerce(value interface{}) (interface{}, error) {
+	switch p.Type {
+	case ParameterTypeNumber:
+		return coerceNumber(value)
+	case ParameterTypeBoolean:
+		return coerceBoolean(value)
+	default:
+		return coerceString(value)
+	}
+}
+
+// Validate coerces a value to the parameter's declared type and checks list values against the options
+func (p ParameterDefinition) Validate(value interface{}) (interface{}, error) {
+	coerced, err := p.Coerce(value)
+	if err != nil {
+		return nil, err
+	}
+	if p.Type == ParameterTypeList {
+		if option, ok := coerced.(string); !ok || !slices.Contains(p.Options, option) {
+			return nil, fmt.Errorf("must be one of %s, got %q", strings.Join(p.Options, ", "), coerced)
+		}
+	}
+	return coerced, nil
+}
+
+// ParameterTemplate returns the template that maps a parameter to its value in the action form
+func ParameterTemplate(name string) string {
+	return "{{ parameters." + name + " }}"
+}
+
+func coerceNumber(value interface{}) (interface{}, error) {
+	var number float64
+	switch v := value.(type) {
+	case float64:
+		number = v
+	case float32:
+		number = float64(v)
+	case int:
+		number = float64(v)
+	case int64:
+		number = float64(v)
+	case int32:
+		number = float64(v)
+	case uint64:
+		number = float64(v)
+	case json.Number:
+		parsed, err := v.Float64()
+		if err != nil {
+			return nil, fmt.Errorf("expected a number, got %q", v.String())
+		}
+		number = parsed
+	case string:
+		parsed, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		if err != nil {
+			return nil, fmt.Errorf("expected a number, got %q", v)
+		}
+		number = parsed
+	default:
+		return nil, fmt.Errorf("expected a number, got %s", describeValue(value))
+	}
+
+	if math.IsNaN(number) || math.IsInf(number, 0) {
+		return nil, fmt.Errorf("expected a finite number, got %v", number)
+	}
+	return number, nil
+}
+
+func coerceBoolean(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case bool:
+		return v, nil
+	case string:
+		parsed, err := strconv.ParseBool(strings.TrimSpace(v))
+		if err != nil {
+			return nil, fmt.Errorf("expected a boolean, got %q", v)
+		}
+		return parsed, nil
+	default:
+		return nil, fmt.Errorf("expected a boolean, got %s", describeValue(value))
+	}
+}
+
+func coerceString(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case int:
+		return strconv.Itoa(v), nil
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	case json.Number:
+		return v.String(), nil
+	default:
+		return nil, fmt.Errorf("expected a string, got %s", describeValue(value))
+	}
+}
+
+// describeValue names the JSON type of a value for error messages
+func describeValue(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "a boolean"
+	case string:
+		return "a string"
+	case []interface{}, []string:
+		return "a list"
+	case map[string]interface{}:
+		return "an object"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
 // Action represents a single action configuration
 type Action struct {
 	HTTP                 *HTTPAction           `yaml:"http,omitempty"`
@@ -143,6 +463,7 @@ type Action struct {
 	ParameterDefinitions []ParameterDefinition `yaml:"parameter_definitions,omitempty"` // For callable actions (UI metadata)
 	Parameters           map[string]string     `yaml:"parameters"`                      // Template mappings (execution time)
 	Env                  map[string]string     `yaml:"env"`                             // Environment variables
+	InheritEnv           []string              `yaml:"inherit_env,omitempty"`           // Connector environment variables passed to the script (names or path.Match patterns)
 	Flags                map[string]string     `yaml:"flags"`                           // Command-line flags (e.g., --verbose, --config=value)
 	Args                 []string              `yaml:"args"`                            // Script arguments
 	ID                   string                `yaml:"id"`                              // REQUIRED: Machine identifier for lookups (e.g., "send_webhook")
@@ -153,9 +474,27 @@ type Action struct {
 	Script               string                `yaml:"script"`                          // Path to script (local or relative to git repo)
 	Stdout               string                `yaml:"stdout"`
 	Stderr               string                `yaml:"stderr"`
+	Input                string                `yaml:"input,omitempty"`                // Script input mode: env, stdin or file (empty means env)
+	MaxOutput            int                   `yaml:"max_output_bytes,omitempty"`     // Cap on captured output per stream (0 means output.max_bytes)
+	Progress             bool                  `yaml:"progress,omitempty"`             // Stream stdout/stderr to Rootly while the script runs
+	Retry                *RetryPolicy          `yaml:"retry,omitempty"`                // Re-run policy for failed executions (nil means no retries)
+	Concurrency          int                   `yaml:"concurrency,omitempty"`          // Max concurrent runs per mutex key (0 means unlimited, or 1 with a mutex_key)
+	MutexKey             string                `yaml:"mutex_key,omitempty"`            // Liquid template rendered per event; runs with the same key share the limit
+	OnConcurrencyLimit   string                `yaml:"on_concurrency_limit,omitempty"` // What happens to a delivery over the limit: queue, reject or drop
+	RateLimit            *RateLimit            `yaml:"rate_limit,omitempty"`           // Token bucket limit on deliveries (nil means unlimited)
 	Timeout              int                   `yaml:"timeout"`
 	Trigger              TriggerConfig         `yaml:"trigger"`
 	Auth                 Authorization         `yaml:"authorization"`
+	Idempotent           bool                  `yaml:"idempotent"` // Re-run (instead of failing) deliveries interrupted by a restart
+	When                 string                `yaml:"when"`       // Liquid condition on the event payload; non-matching events fall through to the next action
+
+	// Event types a wildcard trigger is registered as (set by ConvertToActions, empty for exact triggers)
+	PatternEventTypes []string `yaml:"-"`
+
+	// Pipeline actions (type "pipeline") run their steps and report one aggregated result
+	Steps           []Action `yaml:"steps,omitempty"`
+	Parallel        bool     `yaml:"parallel,omitempty"`
+	ContinueOnError bool     `yaml:"continue_on_error,omitempty"` // Step only: failure does not stop or fail the pipeline
 }
 
 // HTTPAction represents HTTP action configuration
@@ -200,6 +539,75 @@ func (t TriggerConfig) GetEventTypes() []string {
 	return []string{}
 }
 
+// exactMatchSpecificity is the specificity of an exact event type match, which beats any pattern
+const exactMatchSpecificity = math.MaxInt
+
+// defaultEventTypes are the automatic event types Rootly delivers
+// Wildcard triggers are registered with the backend as the ones they match; event types added
+// later are listed in the actions file's event_types
+var defaultEventTypes = []string{
+	"alert.created", "alert.updated", "alert.deleted",
+	"incident.created", "incident.updated", "incident.deleted",
+}
+
+// KnownEventTypes returns the automatic event types wildcard triggers can match: the built-in
+// ones followed by those listed in event_types
+func (cfg *ActionsConfig) KnownEventTypes() []string {
+	eventTypes := slices.Clone(defaultEventTypes)
+	for _, eventType := range cfg.EventTypes {
+		if !slices.Contains(eventTypes, eventType) {
+			eventTypes = append(eventTypes, eventType)
+		}
+	}
+	return eventTypes
+}
+
+// IsEventTypePattern reports whether a trigger event type is a wildcard pattern (e.g. alert.* or *.created)
+func IsEventTypePattern(eventType string) bool {
+	return strings.Contains(eventType, "*")
+}
+
+// ExpandEventTypePattern returns the event types in known that a wildcard pattern matches
+func ExpandEventTypePattern(pattern string, known []string) []string {
+	var eventTypes []string
+	for _, eventType := range known {
+		if matchEventType(pattern, eventType) >= 0 {
+			eventTypes = append(eventTypes, eventType)
+		}
+	}
+	return eventTypes
+}
+
+// MatchEventType reports how specifically the trigger matches an event type, or -1 if it doesn't match
+// An exact event type beats any pattern; among patterns, the one with more literal characters wins
+func (t TriggerConfig) MatchEventType(eventType string) int {
+	best := -1
+	for _, pattern := range t.GetEventTypes() {
+		best = max(best, matchEventType(pattern, eventType))
+	}
+	return best
+}
+
+// matchEventType matches a single trigger event type or pattern against an event type
+func matchEventType(pattern, eventType string) int {
+	if pattern == eventType {
+		return exactMatchSpecificity
+	}
+	if !IsEventTypePattern(pattern) {
+		return -1
+	}
+
+	// Automatic patterns (e.g. *) never match callable events; those need a callable trigger
+	if !isCallableTriggerPattern(pattern) && isCallableTriggerPattern(eventType) {
+		return -1
+	}
+
+	if matched, err := path.Match(pattern, eventType); err != nil || !matched {
+		return -1
+	}
+	return len(pattern) - strings.Count(pattern, "*")
+}
+
 // Authorization represents authorization configuration
 type Authorization struct {
 	AllowedTeams         []string `yaml:"allowed_teams"`
@@ -207,27 +615,86 @@ type Authorization struct {
 	RequiresApproval     bool     `yaml:"requires_approval"`
 }
 
+// RetryPolicy re-runs a failed action before its result is reported
+type RetryPolicy struct {
+	Backoff      string `yaml:"backoff"`       // "exponential" or "linear" (default: exponential)
+	ExitCodes    []int  `yaml:"exit_codes"`    // Script exit codes that are retried (default: any non-zero)
+	HTTPStatuses []int  `yaml:"http_statuses"` // HTTP statuses that are retried (default: 500-599); request errors are always retried
+	MaxAttempts  int    `yaml:"max_attempts"`  // Total runs including the first (required)
+	DelayMs      int    `yaml:"delay_ms"`      // Base delay between attempts (default: 1000)
+	MaxDelayMs   int    `yaml:"max_delay_ms"`  // Cap on the delay between attempts (default: 60000)
+}
+
+// Attempts returns the number of times an action with this policy may run (1 without a policy)
+func (p *RetryPolicy) Attempts() int {
+	if p == nil || p.MaxAttempts < 1 {
+		return 1
+	}
+	return p.MaxAttempts
+}
+
+// maxWaitSec returns the longest total delay between attempts, in whole seconds
+func (p *RetryPolicy) maxWaitSec() int {
+	maxDelayMs := 60000
+	if p != nil && p.MaxDelayMs > 0 {
+		maxDelayMs = p.MaxDelayMs
+	}
+	return (p.Attempts() - 1) * ((maxDelayMs + 999) / 1000)
+}
+
 // ConvertToActions converts the new on/callable format to internal Action array
 func (cfg *ActionsConfig) ConvertToActions() {
 	actions := make([]Action, 0, len(cfg.On)+len(cfg.Callable))
 
+	// Convert in sorted order so the action list (and precedence between equally specific
+	// triggers) does not depend on map iteration order
+
 	// Convert "on" actions (automatic triggers)
-	for eventType, onAction := range cfg.On {
-		action := onActionToAction(eventType, onAction, &cfg.Defaults)
+	known := cfg.KnownEventTypes()
+	for _, eventType := range slices.Sorted(maps.Keys(cfg.On)) {
+		action := onActionToAction(eventType, cfg.On[eventType], &cfg.Defaults)
+		if IsEventTypePattern(eventType) {
+			action.PatternEventTypes = ExpandEventTypePattern(eventType, known)
+		}
 		actions = append(actions, action)
 	}
 
 	// Convert "callable" actions (user-triggered)
-	for slug, callableAction := range cfg.Callable {
-		action := callableActionToAction(slug, callableAction, &cfg.Defaults)
+	for _, slug := range slices.Sorted(maps.Keys(cfg.Callable)) {
+		action := callableActionToAction(slug, cfg.Callable[slug], &cfg.Defaults)
 		actions = append(actions, action)
 	}
 
 	cfg.Actions = actions
 }
 
+// WhenTemplate wraps a when: condition in a Liquid template that renders "true" if the condition holds
+func WhenTemplate(condition string) string {
+	return "{% if " + condition + " %}true{% endif %}"
+}
+
+// UnmarshalYAML accepts either a single action mapping or a list of pipeline steps
+func (on *OnAction) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.SequenceNode {
+		var steps []OnAction
+		if err := value.Decode(&steps); err != nil {
+			return err
+		}
+		*on = OnAction{Steps: steps}
+		return nil
+	}
+
+	// Decode through an alias type to avoid recursing into this method
+	type plain OnAction
+	return value.Decode((*plain)(on))
+}
+
 // onActionToAction converts an OnAction to the internal Action format
 func onActionToAction(eventType string, on OnAction, defaults *ActionDefaults) Action {
+	if len(on.Steps) > 0 {
+		return onPipelineToAction(eventType, on, defaults)
+	}
+
 	// Auto-detect type from configuration
 	actionType := on.Type
 	if actionType == "" {
@@ -239,7 +706,7 @@ func onActionToAction(eventType string, on OnAction, defaults *ActionDefaults) A
 	}
 
 	action := Action{
-		ID:          eventType, // Use event type as ID for on actions
+		ID:          eventType, // Use event type (or wildcard pattern) as ID for on actions
 		Name:        "",        // No name for automatic actions
 		Description: "",
 		Type:        actionType,
@@ -249,15 +716,72 @@ func onActionToAction(eventType string, on OnAction, defaults *ActionDefaults) A
 		GitOptions:  on.GitOptions,
 		Parameters:  on.Parameters,
 		Env:         mergeEnv(defaults.Env, on.Env),
+		InheritEnv:  mergeInheritEnv(defaults.InheritEnv, on.InheritEnv),
 		Flags:       on.Flags,
 		Args:        on.Args,
 		Timeout:     getTimeoutOrDefault(on.Timeout, defaults.Timeout, 30),
 		Stdout:      on.Stdout,
 		Stderr:      on.Stderr,
+		Input:       on.Input,
+		MaxOutput:   on.MaxOutput,
+		Progress:    on.Progress,
+		Retry:       on.Retry,
+
+		Concurrency:        on.Concurrency,
+		MutexKey:           on.MutexKey,
+		OnConcurrencyLimit: on.OnConcurrencyLimit,
+		RateLimit:          on.RateLimit,
+		Idempotent:         on.Idempotent,
+		When:               on.When,
+		Trigger: TriggerConfig{
+			EventType: eventType,
+		},
+	}
+	return action
+}
+
+// onPipelineToAction converts an OnAction with steps to a pipeline Action
+// Steps inherit the event type as trigger; the pipeline is idempotent only if every step is
+func onPipelineToAction(eventType string, on OnAction, defaults *ActionDefaults) Action {
+	action := Action{
+		ID:         eventType,
+		Type:       actionTypePipeline,
+		Parallel:   on.Parallel,
+		Idempotent: true,
+		When:       on.When,
+
+		Concurrency:        on.Concurrency,
+		MutexKey:           on.MutexKey,
+		OnConcurrencyLimit: on.OnConcurrencyLimit,
+		RateLimit:          on.RateLimit,
+		Steps:              make([]Action, 0, len(on.Steps)),
 		Trigger: TriggerConfig{
 			EventType: eventType,
 		},
 	}
+
+	for i, onStep := range on.Steps {
+		step := onActionToAction(eventType, onStep, defaults)
+		step.ID = getOrDefault(onStep.ID, fmt.Sprintf("step_%d", i+1))
+		step.ContinueOnError = onStep.ContinueOnError
+		action.Idempotent = action.Idempotent && step.Idempotent
+
+		// Sequential pipelines may run every step back to back; parallel ones are bounded by the slowest step
+		stepTimeout := step.Timeout*step.Retry.Attempts() + step.Retry.maxWaitSec()
+		if action.Parallel {
+			action.Timeout = max(action.Timeout, stepTimeout)
+		} else {
+			action.Timeout += stepTimeout
+		}
+
+		action.Steps = append(action.Steps, step)
+	}
+
+	// An explicit timeout on the pipeline overrides the one derived from its steps
+	if on.Timeout > 0 {
+		action.Timeout = on.Timeout
+	}
+
 	return action
 }
 
@@ -299,12 +823,23 @@ func callableActionToAction(slug string, callable CallableAction, defaults *Acti
 		ParameterDefinitions: callable.ParameterDefinitions,
 		Parameters:           parameters,
 		Env:                  mergeEnv(defaults.Env, callable.Env),
+		InheritEnv:           mergeInheritEnv(defaults.InheritEnv, callable.InheritEnv),
 		Flags:                callable.Flags,
 		Args:                 callable.Args,
 		Timeout:              getTimeoutOrDefault(callable.Timeout, defaults.Timeout, 30),
 		Stdout:               callable.Stdout,
 		Stderr:               callable.Stderr,
+		Input:                callable.Input,
+		MaxOutput:            callable.MaxOutput,
+		Progress:             callable.Progress,
+		Retry:                callable.Retry,
+		Concurrency:          callable.Concurrency,
+		MutexKey:             callable.MutexKey,
+		OnConcurrencyLimit:   callable.OnConcurrencyLimit,
+		RateLimit:            callable.RateLimit,
 		Auth:                 callable.Auth,
+		Idempotent:           callable.Idempotent,
+		When:                 callable.When,
 		Trigger: TriggerConfig{
 			EventType: eventType,
 		},
@@ -345,11 +880,19 @@ func mergeEnv(global, local map[string]string) map[string]string {
 	return result
 }
 
+// mergeInheritEnv combines the default and per-action inherit_env lists
+func mergeInheritEnv(global, local []string) []string {
+	if len(global) == 0 && len(local) == 0 {
+		return nil
+	}
+	return append(append(make([]string, 0, len(global)+len(local)), global...), local...)
+}
+
 func autoGenerateParameters(paramDefs []ParameterDefinition) map[string]string {
 	params := make(map[string]string)
 	for _, def := range paramDefs {
 		// Auto-map: parameter_name → "{{ parameters.parameter_name }}"
-		params[def.Name] = "{{ parameters." + def.Name + " }}"
+		params[def.Name] = ParameterTemplate(def.Name)
 	}
 	return params
 }
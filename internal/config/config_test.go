@@ -316,3 +316,124 @@ func TestConvertToActions_MixedOnAndCallable(t *testing.T) {
 	assert.True(t, hasAlertCreated)
 	assert.True(t, hasAction1)
 }
+
+func TestTriggerConfig_MatchEventType(t *testing.T) {
+	tests := []struct {
+		name      string
+		trigger   TriggerConfig
+		eventType string
+		matches   bool
+	}{
+		{"exact", TriggerConfig{EventType: "alert.created"}, "alert.created", true},
+		{"exact mismatch", TriggerConfig{EventType: "alert.created"}, "alert.updated", false},
+		{"prefix wildcard", TriggerConfig{EventType: "alert.*"}, "alert.resolved", true},
+		{"prefix wildcard other resource", TriggerConfig{EventType: "alert.*"}, "incident.created", false},
+		{"suffix wildcard", TriggerConfig{EventType: "*.created"}, "incident.created", true},
+		{"match all", TriggerConfig{EventType: "*"}, "incident.updated", true},
+		{"automatic pattern skips callable events", TriggerConfig{EventType: "*"}, "action.triggered", false},
+		{"automatic prefix skips callable events", TriggerConfig{EventType: "alert.*"}, "alert.action_triggered", false},
+		{"callable pattern", TriggerConfig{EventType: "*.action_triggered"}, "incident.action_triggered", true},
+		{"any of multiple", TriggerConfig{EventTypes: []string{"incident.created", "alert.*"}}, "alert.created", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.matches, tt.trigger.MatchEventType(tt.eventType) >= 0)
+		})
+	}
+}
+
+func TestTriggerConfig_MatchEventType_Specificity(t *testing.T) {
+	exact := TriggerConfig{EventType: "alert.created"}.MatchEventType("alert.created")
+	prefix := TriggerConfig{EventType: "alert.*"}.MatchEventType("alert.created")
+	all := TriggerConfig{EventType: "*"}.MatchEventType("alert.created")
+
+	assert.Greater(t, exact, prefix, "Exact match should beat a pattern")
+	assert.Greater(t, prefix, all, "More specific pattern should beat a broader one")
+	assert.GreaterOrEqual(t, all, 0)
+}
+
+func TestExpandEventTypePattern(t *testing.T) {
+	known := (&ActionsConfig{}).KnownEventTypes()
+	assert.Equal(t, []string{"alert.created", "alert.updated", "alert.deleted"}, ExpandEventTypePattern("alert.*", known))
+	assert.Equal(t, []string{"alert.created", "incident.created"}, ExpandEventTypePattern("*.created", known))
+	assert.Len(t, ExpandEventTypePattern("*", known), 6, "Callable event types are never expanded")
+	assert.Empty(t, ExpandEventTypePattern("deploy.*", known))
+}
+
+func TestKnownEventTypes_IncludesConfiguredEventTypes(t *testing.T) {
+	cfg := &ActionsConfig{EventTypes: []string{"alert.resolved", "alert.created"}}
+
+	known := cfg.KnownEventTypes()
+	assert.Len(t, known, 7, "Built-in event types are not listed twice")
+	assert.Equal(t, "alert.resolved", known[6])
+	assert.Equal(t, []string{"alert.created", "alert.updated", "alert.deleted", "alert.resolved"}, ExpandEventTypePattern("alert.*", known))
+}
+
+func TestConvertToActions_WildcardOnAction(t *testing.T) {
+	cfg := &ActionsConfig{
+		On: map[string]OnAction{
+			"*":       {HTTP: &HTTPAction{URL: "https://audit.example.com"}},
+			"alert.*": {HTTP: &HTTPAction{URL: "https://alerts.example.com"}},
+		},
+	}
+
+	cfg.ConvertToActions()
+
+	assert.Len(t, cfg.Actions, 2)
+	assert.Equal(t, "*", cfg.Actions[0].ID, "The pattern is the ID, so it cannot collide with a real event type")
+	assert.Equal(t, "*", cfg.Actions[0].Trigger.EventType)
+	assert.Equal(t, "alert.*", cfg.Actions[1].ID)
+	assert.Equal(t, "alert.*", cfg.Actions[1].Trigger.EventType)
+	assert.Len(t, cfg.Actions[0].PatternEventTypes, 6)
+	assert.Equal(t, []string{"alert.created", "alert.updated", "alert.deleted"}, cfg.Actions[1].PatternEventTypes)
+}
+
+func TestParameterDefinition_Coerce(t *testing.T) {
+	tests := []struct {
+		name      string
+		paramType string
+		value     interface{}
+		want      interface{}
+		wantErr   string
+	}{
+		{name: "number from float", paramType: ParameterTypeNumber, value: 2.5, want: 2.5},
+		{name: "number from int", paramType: ParameterTypeNumber, value: 3, want: float64(3)},
+		{name: "number from string", paramType: ParameterTypeNumber, value: " 42 ", want: float64(42)},
+		{name: "number from text", paramType: ParameterTypeNumber, value: "lots", wantErr: `expected a number, got "lots"`},
+		{name: "number from bool", paramType: ParameterTypeNumber, value: true, wantErr: "expected a number, got a boolean"},
+		{name: "number not finite", paramType: ParameterTypeNumber, value: "NaN", wantErr: "expected a finite number"},
+		{name: "boolean from bool", paramType: ParameterTypeBoolean, value: false, want: false},
+		{name: "boolean from string", paramType: ParameterTypeBoolean, value: "true", want: true},
+		{name: "boolean from list", paramType: ParameterTypeBoolean, value: []interface{}{true}, wantErr: "expected a boolean, got a list"},
+		{name: "string from number", paramType: ParameterTypeString, value: float64(7), want: "7"},
+		{name: "string from object", paramType: ParameterTypeString, value: map[string]interface{}{}, wantErr: "expected a string, got an object"},
+		{name: "list selection", paramType: ParameterTypeList, value: "eu", want: "eu"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParameterDefinition{Name: "param", Type: tt.paramType}.Coerce(tt.value)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParameterDefinition_Validate(t *testing.T) {
+	def := ParameterDefinition{Name: "region", Type: ParameterTypeList, Options: []string{"us", "eu"}}
+
+	value, err := def.Validate("eu")
+	assert.NoError(t, err)
+	assert.Equal(t, "eu", value)
+
+	_, err = def.Validate("ap")
+	assert.EqualError(t, err, `must be one of us, eu, got "ap"`)
+
+	_, err = ParameterDefinition{Name: "replicas", Type: ParameterTypeNumber}.Validate("3x")
+	assert.EqualError(t, err, `expected a number, got "3x"`)
+}
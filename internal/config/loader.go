@@ -3,13 +3,17 @@ package config
 import (
 	"fmt"
 	"os"
+	"strings"
 
 	"gopkg.in/yaml.v3"
+
+	"github.com/rootly/edge-connector/internal/backoff"
 )
 
 const (
 	defaultActionType       = "script"
 	defaultActionTypeHTTP   = "http"
+	actionTypePipeline      = "pipeline"
 	defaultActionSourceType = "local"
 	defaultHTTPMethod       = "POST"
 	defaultGitBranch        = "main"
@@ -27,6 +31,10 @@ func Load(path string) (*Config, error) {
 	if err := yaml.Unmarshal(data, &cfg); err != nil {
 		return nil, fmt.Errorf("failed to parse config: %w", err)
 	}
+	keys, err := parseConfigKeys(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse config: %w", err)
+	}
 
 	// Apply environment variable overrides
 	if apiURL := os.Getenv("REC_API_URL"); apiURL != "" {
@@ -50,7 +58,7 @@ func Load(path string) (*Config, error) {
 	}
 
 	// Apply defaults
-	applyDefaults(&cfg)
+	applyDefaults(&cfg, keys)
 
 	// Validate configuration
 	if err := Validate(&cfg); err != nil {
@@ -95,8 +103,39 @@ func LoadActions(path string) (*ActionsConfig, error) {
 	return &cfg, nil
 }
 
+// configKeys is the set of keys present in a config file, as dotted paths (e.g. "dedup.ttl_sec")
+// Settings where 0 is meaningful only get their default when the key is absent
+type configKeys map[string]bool
+
+// parseConfigKeys collects the mapping keys of a YAML document
+func parseConfigKeys(data []byte) (configKeys, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	keys := make(configKeys)
+	for _, node := range doc.Content {
+		keys.collect("", node)
+	}
+	return keys, nil
+}
+
+func (k configKeys) collect(prefix string, node *yaml.Node) {
+	if node.Kind != yaml.MappingNode {
+		return
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key := node.Content[i].Value
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		k[key] = true
+		k.collect(key, node.Content[i+1])
+	}
+}
+
 // applyDefaults sets default values for configuration
-func applyDefaults(cfg *Config) {
+func applyDefaults(cfg *Config, keys configKeys) {
 	// Rootly defaults
 	if cfg.Rootly.APIPath == "" {
 		cfg.Rootly.APIPath = "/v1"
@@ -109,9 +148,34 @@ func applyDefaults(cfg *Config) {
 	if cfg.Poller.VisibilityTimeoutSec == 0 {
 		cfg.Poller.VisibilityTimeoutSec = 30
 	}
+	if cfg.Poller.HeartbeatIntervalSec == 0 {
+		cfg.Poller.HeartbeatIntervalSec = max(cfg.Poller.VisibilityTimeoutSec/3, 1)
+	}
 	if cfg.Poller.MaxNumberOfMessages == 0 {
 		cfg.Poller.MaxNumberOfMessages = 10
 	}
+	if cfg.Poller.MaxPagesPerPoll == 0 {
+		cfg.Poller.MaxPagesPerPoll = 10
+	}
+	if cfg.Poller.MinPollingIntervalMs == 0 {
+		cfg.Poller.MinPollingIntervalMs = min(1000, cfg.Poller.PollingWaitIntervalMs)
+	}
+	if cfg.Poller.MaxPollingIntervalMs == 0 {
+		// Idle polling slows down to 30 seconds with the default interval
+		cfg.Poller.MaxPollingIntervalMs = 6 * cfg.Poller.PollingWaitIntervalMs
+	}
+	if cfg.Poller.PollingJitter == 0 && !keys["poller.polling_jitter"] {
+		cfg.Poller.PollingJitter = 0.1
+	}
+	if cfg.Poller.Mode == "" {
+		cfg.Poller.Mode = PollerModePoll
+	}
+	if cfg.Poller.Stream.IdleTimeoutSec == 0 {
+		cfg.Poller.Stream.IdleTimeoutSec = 60
+	}
+	if cfg.Poller.Stream.ReconnectMaxSec == 0 {
+		cfg.Poller.Stream.ReconnectMaxSec = 300
+	}
 	if cfg.Poller.RetryBackoff == "" {
 		cfg.Poller.RetryBackoff = "exponential"
 	}
@@ -135,12 +199,66 @@ func applyDefaults(cfg *Config) {
 	if cfg.Pool.MonitoringPeriodMs == 0 {
 		cfg.Pool.MonitoringPeriodMs = 30000
 	}
+	if cfg.Pool.ShutdownTimeoutSec == 0 {
+		cfg.Pool.ShutdownTimeoutSec = 30
+	}
 
 	// Security defaults
 	if cfg.Security.ScriptTimeout == 0 {
 		cfg.Security.ScriptTimeout = 300
 	}
 
+	// State defaults
+	if cfg.State.Dir == "" {
+		cfg.State.Dir = "/tmp/rec-state"
+	}
+
+	// Outbox defaults
+	if cfg.Outbox.ReplayIntervalMs == 0 {
+		cfg.Outbox.ReplayIntervalMs = 10000
+	}
+	if cfg.Outbox.MaxBackoffSec == 0 {
+		cfg.Outbox.MaxBackoffSec = 300
+	}
+	if cfg.Outbox.MaxEntries == 0 {
+		cfg.Outbox.MaxEntries = 10000
+	}
+	if cfg.Outbox.MaxAttempts == 0 {
+		cfg.Outbox.MaxAttempts = 50
+	}
+
+	// Dedup defaults (ttl_sec: 0 disables deduplication)
+	if cfg.Dedup.TTLSec == 0 && !keys["dedup.ttl_sec"] {
+		cfg.Dedup.TTLSec = 3600
+	}
+
+	// HTTP target defaults
+	applyCircuitBreakerDefaults(cfg.HTTPTargets.CircuitBreaker)
+	hosts := make(map[string]HTTPTargetConfig, len(cfg.HTTPTargets.Hosts))
+	for host, target := range cfg.HTTPTargets.Hosts {
+		applyCircuitBreakerDefaults(target.CircuitBreaker)
+		hosts[strings.ToLower(host)] = target // Matched against request hosts case-insensitively
+	}
+	cfg.HTTPTargets.Hosts = hosts
+
+	// Reload defaults
+	if cfg.Reload.WatchIntervalMs == 0 {
+		cfg.Reload.WatchIntervalMs = 5000
+	}
+
+	// Output defaults
+	if cfg.Output.MaxBytes == 0 {
+		cfg.Output.MaxBytes = 10000
+	}
+
+	// Progress defaults
+	if cfg.Progress.IntervalMs == 0 {
+		cfg.Progress.IntervalMs = 5000
+	}
+	if cfg.Progress.MaxUpdatesPerSec == 0 && !keys["progress.max_updates_per_sec"] {
+		cfg.Progress.MaxUpdatesPerSec = 5
+	}
+
 	// Logging defaults
 	if cfg.Logging.Level == "" {
 		cfg.Logging.Level = "info"
@@ -170,6 +288,13 @@ func applyDefaults(cfg *Config) {
 	}
 }
 
+// applyCircuitBreakerDefaults sets default values for a circuit breaker, if configured
+func applyCircuitBreakerDefaults(cb *CircuitBreakerConfig) {
+	if cb != nil && cb.CooldownSec == 0 {
+		cb.CooldownSec = 30
+	}
+}
+
 // applyActionDefaults sets default values for an action
 func applyActionDefaults(action *Action) {
 	if action.Type == "" {
@@ -184,6 +309,20 @@ func applyActionDefaults(action *Action) {
 	if action.HTTP != nil && action.HTTP.Method == "" {
 		action.HTTP.Method = defaultHTTPMethod
 	}
+	if action.ConcurrencyLimit() > 0 && action.OnConcurrencyLimit == "" {
+		action.OnConcurrencyLimit = ConcurrencyQueue
+	}
+	if action.Retry != nil {
+		if action.Retry.Backoff == "" {
+			action.Retry.Backoff = backoff.Exponential
+		}
+		if action.Retry.DelayMs == 0 {
+			action.Retry.DelayMs = 1000
+		}
+		if action.Retry.MaxDelayMs == 0 {
+			action.Retry.MaxDelayMs = 60000
+		}
+	}
 	if action.GitOptions != nil {
 		if action.GitOptions.Branch == "" {
 			action.GitOptions.Branch = defaultGitBranch
@@ -192,4 +331,7 @@ func applyActionDefaults(action *Action) {
 			action.GitOptions.PollIntervalSec = 300
 		}
 	}
+	for i := range action.Steps {
+		applyActionDefaults(&action.Steps[i])
+	}
 }
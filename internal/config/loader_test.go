@@ -3,6 +3,7 @@ package config_test
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -168,7 +169,15 @@ pool:
 	// Check defaults are applied
 	assert.Equal(t, 5000, cfg.Poller.PollingWaitIntervalMs, "Default polling interval")
 	assert.Equal(t, 30, cfg.Poller.VisibilityTimeoutSec, "Default visibility timeout")
+	assert.Equal(t, 10, cfg.Poller.HeartbeatIntervalSec, "Default heartbeat interval is a third of the visibility timeout")
 	assert.Equal(t, 10, cfg.Poller.MaxNumberOfMessages, "Default max messages")
+	assert.Equal(t, 10, cfg.Poller.MaxPagesPerPoll, "Default max pages per poll")
+	assert.Equal(t, 1000, cfg.Poller.MinPollingIntervalMs, "Default min polling interval")
+	assert.Equal(t, 30000, cfg.Poller.MaxPollingIntervalMs, "Default max polling interval is 6x the polling interval")
+	assert.InDelta(t, 0.1, cfg.Poller.PollingJitter, 0.0001, "Default polling jitter")
+	assert.Equal(t, config.PollerModePoll, cfg.Poller.Mode, "Default delivery mode")
+	assert.Equal(t, 60, cfg.Poller.Stream.IdleTimeoutSec, "Default stream idle timeout")
+	assert.Equal(t, 300, cfg.Poller.Stream.ReconnectMaxSec, "Default stream reconnect backoff cap")
 	assert.Equal(t, "exponential", cfg.Poller.RetryBackoff, "Default backoff strategy")
 	assert.Equal(t, 300, cfg.Security.ScriptTimeout, "Default script timeout")
 	assert.Equal(t, "info", cfg.Logging.Level, "Default log level")
@@ -176,6 +185,154 @@ pool:
 	assert.Equal(t, "stdout", cfg.Logging.Output, "Default log output")
 	assert.Equal(t, 9090, cfg.Metrics.Port, "Default metrics port")
 	assert.Equal(t, "/metrics", cfg.Metrics.Path, "Default metrics path")
+	assert.Equal(t, 30, cfg.Pool.ShutdownTimeoutSec, "Default shutdown timeout")
+	assert.Equal(t, "/tmp/rec-state", cfg.State.Dir, "Default state directory")
+	assert.Equal(t, 10000, cfg.Outbox.ReplayIntervalMs, "Default outbox replay interval")
+	assert.Equal(t, 300, cfg.Outbox.MaxBackoffSec, "Default outbox max backoff")
+	assert.Equal(t, 10000, cfg.Outbox.MaxEntries, "Default outbox max entries")
+	assert.Equal(t, 3600, cfg.Dedup.TTLSec, "Default dedup TTL")
+	assert.False(t, cfg.Dedup.Persist, "Dedup is in memory by default")
+	assert.False(t, cfg.Reload.WatchFile, "File watcher should be disabled by default")
+	assert.Equal(t, 5000, cfg.Reload.WatchIntervalMs, "Default reload watch interval")
+	assert.Equal(t, 10000, cfg.Output.MaxBytes, "Default output cap")
+	assert.Empty(t, cfg.Output.OverflowDir, "Overflow files should be disabled by default")
+	assert.Equal(t, 5000, cfg.Progress.IntervalMs, "Default progress interval")
+	assert.InDelta(t, 5.0, cfg.Progress.MaxUpdatesPerSec, 0, "Default progress rate limit")
+}
+
+func TestLoad_DedupDisabled(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yml")
+
+	configContent := `
+app:
+  name: "test"
+rootly:
+  api_url: "https://api.rootly.com"
+  api_key: "test-key"
+pool:
+  max_number_of_workers: 5
+  min_number_of_workers: 1
+dedup:
+  ttl_sec: 0
+`
+
+	err := os.WriteFile(configPath, []byte(configContent), 0644)
+	require.NoError(t, err)
+
+	cfg, err := config.Load(configPath)
+	require.NoError(t, err)
+	assert.Equal(t, 0, cfg.Dedup.TTLSec, "An explicit ttl_sec: 0 turns deduplication off")
+}
+
+func TestLoad_ExplicitZeroKeepsJitterAndProgressCapOff(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yml")
+
+	configContent := `
+app:
+  name: "test"
+rootly:
+  api_url: "https://api.rootly.com"
+  api_key: "test-key"
+pool:
+  max_number_of_workers: 5
+  min_number_of_workers: 1
+poller:
+  polling_jitter: 0
+progress:
+  max_updates_per_sec: 0
+`
+
+	err := os.WriteFile(configPath, []byte(configContent), 0644)
+	require.NoError(t, err)
+
+	cfg, err := config.Load(configPath)
+	require.NoError(t, err)
+	assert.Zero(t, cfg.Poller.PollingJitter, "An explicit polling_jitter: 0 turns jitter off")
+	assert.Zero(t, cfg.Progress.MaxUpdatesPerSec, "An explicit max_updates_per_sec: 0 turns the cap off")
+}
+
+func TestLoad_MaxPollingIntervalDefaultsToMultipleOfInterval(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	for _, tt := range []struct {
+		name   string
+		poller string
+		want   int
+	}{
+		{name: "custom interval", poller: "polling_wait_interval_ms: 2000", want: 12000},
+		{name: "explicit max", poller: "max_polling_interval_ms: 5000", want: 5000},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			configPath := filepath.Join(tmpDir, "config.yml")
+			configContent := `
+app:
+  name: "test"
+rootly:
+  api_url: "https://api.rootly.com"
+  api_key: "test-key"
+pool:
+  max_number_of_workers: 5
+  min_number_of_workers: 1
+poller:
+  ` + tt.poller + "\n"
+
+			err := os.WriteFile(configPath, []byte(configContent), 0644)
+			require.NoError(t, err)
+
+			cfg, err := config.Load(configPath)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, cfg.Poller.MaxPollingIntervalMs)
+		})
+	}
+}
+
+func TestLoad_HTTPTargets(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yml")
+
+	configContent := `
+app:
+  name: "test"
+rootly:
+  api_url: "https://api.rootly.com"
+  api_key: "test-key"
+pool:
+  max_number_of_workers: 5
+  min_number_of_workers: 1
+http_targets:
+  rate_limit:
+    per_sec: 10
+  circuit_breaker:
+    failure_threshold: 5
+  hosts:
+    Hooks.Example.com:
+      rate_limit:
+        per_sec: 2
+        burst: 5
+      circuit_breaker:
+        failure_threshold: 3
+        cooldown_sec: 120
+`
+
+	require.NoError(t, os.WriteFile(configPath, []byte(configContent), 0644))
+
+	cfg, err := config.Load(configPath)
+	require.NoError(t, err)
+
+	defaults := cfg.HTTPTargets.For("api.example.com")
+	require.NotNil(t, defaults.RateLimit)
+	assert.InDelta(t, 10.0, defaults.RateLimit.PerSec, 0)
+	require.NotNil(t, defaults.CircuitBreaker)
+	assert.Equal(t, 5, defaults.CircuitBreaker.FailureThreshold)
+	assert.Equal(t, 30, defaults.CircuitBreaker.CooldownSec, "Default cooldown")
+
+	host := cfg.HTTPTargets.For("HOOKS.example.com")
+	assert.InDelta(t, 2.0, host.RateLimit.PerSec, 0, "Host entries override the defaults, matched case-insensitively")
+	assert.Equal(t, 5, host.RateLimit.Burst)
+	assert.Equal(t, 3, host.CircuitBreaker.FailureThreshold)
+	assert.Equal(t, 120, host.CircuitBreaker.CooldownSec)
 }
 
 func TestLoad_InvalidYAML(t *testing.T) {
@@ -363,3 +520,742 @@ on: {}
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "at least one action")
 }
+
+func TestLoadActions_PipelineListForm(t *testing.T) {
+	tmpDir := t.TempDir()
+	actionsPath := filepath.Join(tmpDir, "actions.yml")
+
+	scriptPath := filepath.Join(tmpDir, "diagnostics.sh")
+	err := os.WriteFile(scriptPath, []byte("#!/bin/bash\necho diag"), 0755)
+	require.NoError(t, err)
+
+	actionsContent := `
+on:
+  alert.created:
+    - id: collect_diagnostics
+      script: ` + scriptPath + `
+      timeout: 60
+      idempotent: true
+      continue_on_error: true
+    - http:
+        url: "https://hooks.example.com/alert"
+      timeout: 10
+`
+
+	err = os.WriteFile(actionsPath, []byte(actionsContent), 0644)
+	require.NoError(t, err)
+
+	actions, err := config.LoadActions(actionsPath)
+	require.NoError(t, err)
+	require.Len(t, actions.Actions, 1)
+
+	pipeline := actions.Actions[0]
+	assert.Equal(t, "alert.created", pipeline.ID)
+	assert.Equal(t, "pipeline", pipeline.Type)
+	assert.False(t, pipeline.Parallel)
+	assert.Equal(t, 70, pipeline.Timeout, "Sequential pipeline timeout is the sum of step timeouts")
+	assert.False(t, pipeline.Idempotent, "Pipeline is idempotent only if every step is")
+	require.Len(t, pipeline.Steps, 2)
+
+	assert.Equal(t, "collect_diagnostics", pipeline.Steps[0].ID)
+	assert.Equal(t, "script", pipeline.Steps[0].Type)
+	assert.True(t, pipeline.Steps[0].ContinueOnError)
+
+	assert.Equal(t, "step_2", pipeline.Steps[1].ID)
+	assert.Equal(t, "http", pipeline.Steps[1].Type)
+	assert.Equal(t, "POST", pipeline.Steps[1].HTTP.Method, "Defaults apply to pipeline steps")
+	assert.False(t, pipeline.Steps[1].ContinueOnError)
+}
+
+func TestLoadActions_PipelineParallel(t *testing.T) {
+	tmpDir := t.TempDir()
+	actionsPath := filepath.Join(tmpDir, "actions.yml")
+
+	actionsContent := `
+on:
+  incident.created:
+    parallel: true
+    steps:
+      - id: notify_slack
+        http:
+          url: "https://hooks.example.com/slack"
+        timeout: 10
+      - id: notify_pager
+        http:
+          url: "https://hooks.example.com/pager"
+        timeout: 20
+`
+
+	err := os.WriteFile(actionsPath, []byte(actionsContent), 0644)
+	require.NoError(t, err)
+
+	actions, err := config.LoadActions(actionsPath)
+	require.NoError(t, err)
+	require.Len(t, actions.Actions, 1)
+
+	pipeline := actions.Actions[0]
+	assert.True(t, pipeline.Parallel)
+	assert.Equal(t, 20, pipeline.Timeout, "Parallel pipeline timeout is the slowest step's timeout")
+	require.Len(t, pipeline.Steps, 2)
+
+	// A timeout set on the pipeline itself wins
+	explicit := strings.Replace(actionsContent, "    parallel: true\n", "    parallel: true\n    timeout: 15\n", 1)
+	require.NoError(t, os.WriteFile(actionsPath, []byte(explicit), 0644))
+	actions, err = config.LoadActions(actionsPath)
+	require.NoError(t, err)
+	require.Len(t, actions.Actions, 1)
+	assert.Equal(t, 15, actions.Actions[0].Timeout)
+}
+
+func TestLoadActions_PipelineInvalidStep(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected string
+	}{
+		{
+			name: "duplicate step ids",
+			content: `
+on:
+  alert.created:
+    - id: notify
+      http:
+        url: "https://hooks.example.com/a"
+    - id: notify
+      http:
+        url: "https://hooks.example.com/b"
+`,
+			expected: "duplicate step id: notify",
+		},
+		{
+			name: "step missing script",
+			content: `
+on:
+  alert.created:
+    - id: diagnostics
+      type: script
+`,
+			expected: "steps[0] (diagnostics): script is required",
+		},
+		{
+			name: "nested pipeline",
+			content: `
+on:
+  alert.created:
+    - id: outer
+      steps:
+        - http:
+            url: "https://hooks.example.com/a"
+`,
+			expected: "pipelines cannot be nested",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actionsPath := filepath.Join(t.TempDir(), "actions.yml")
+			require.NoError(t, os.WriteFile(actionsPath, []byte(tt.content), 0644))
+
+			_, err := config.LoadActions(actionsPath)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.expected)
+		})
+	}
+}
+
+func TestLoadActions_WhenCondition(t *testing.T) {
+	tmpDir := t.TempDir()
+	actionsPath := filepath.Join(tmpDir, "actions.yml")
+
+	actionsContent := `
+on:
+  alert.created:
+    when: labels.severity == "critical"
+    http:
+      url: "https://hooks.example.com/critical"
+
+callable:
+  restart_payments:
+    name: Restart Payments
+    when: services contains "payments"
+    http:
+      url: "https://hooks.example.com/restart"
+`
+
+	err := os.WriteFile(actionsPath, []byte(actionsContent), 0644)
+	require.NoError(t, err)
+
+	actions, err := config.LoadActions(actionsPath)
+	require.NoError(t, err)
+	require.Len(t, actions.Actions, 2)
+
+	conditions := make(map[string]string)
+	for _, action := range actions.Actions {
+		conditions[action.ID] = action.When
+	}
+	assert.Equal(t, `labels.severity == "critical"`, conditions["alert.created"])
+	assert.Equal(t, `services contains "payments"`, conditions["restart_payments"])
+}
+
+func TestLoadActions_WhenConditionInvalid(t *testing.T) {
+	tmpDir := t.TempDir()
+	actionsPath := filepath.Join(tmpDir, "actions.yml")
+
+	actionsContent := `
+on:
+  alert.created:
+    when: labels.severity ==
+    http:
+      url: "https://hooks.example.com/critical"
+`
+
+	err := os.WriteFile(actionsPath, []byte(actionsContent), 0644)
+	require.NoError(t, err)
+
+	_, err = config.LoadActions(actionsPath)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "when is invalid")
+}
+
+func TestLoadActions_ScriptInput(t *testing.T) {
+	tmpDir := t.TempDir()
+	actionsPath := filepath.Join(tmpDir, "actions.yml")
+	scriptPath := filepath.Join(tmpDir, "restart.sh")
+	require.NoError(t, os.WriteFile(scriptPath, []byte("#!/bin/bash\n"), 0755))
+
+	actionsContent := `
+on:
+  alert.created:
+    script: ` + scriptPath + `
+    input: stdin
+
+callable:
+  restart_service:
+    name: Restart Service
+    script: ` + scriptPath + `
+    input: file
+`
+
+	err := os.WriteFile(actionsPath, []byte(actionsContent), 0644)
+	require.NoError(t, err)
+
+	actions, err := config.LoadActions(actionsPath)
+	require.NoError(t, err)
+
+	inputs := make(map[string]string)
+	for _, action := range actions.Actions {
+		inputs[action.ID] = action.Input
+	}
+	assert.Equal(t, config.InputStdin, inputs["alert.created"])
+	assert.Equal(t, config.InputFile, inputs["restart_service"])
+
+	// Unknown modes are rejected
+	invalid := strings.Replace(actionsContent, "input: stdin", "input: socket", 1)
+	require.NoError(t, os.WriteFile(actionsPath, []byte(invalid), 0644))
+	_, err = config.LoadActions(actionsPath)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "input must be one of: env, stdin, file")
+}
+
+func TestLoadActions_InheritEnv(t *testing.T) {
+	tmpDir := t.TempDir()
+	actionsPath := filepath.Join(tmpDir, "actions.yml")
+	scriptPath := filepath.Join(tmpDir, "deploy.sh")
+	require.NoError(t, os.WriteFile(scriptPath, []byte("#!/bin/bash\n"), 0755))
+
+	actionsContent := `
+defaults:
+  inherit_env: [HTTPS_PROXY]
+
+on:
+  alert.created:
+    script: ` + scriptPath + `
+
+callable:
+  deploy:
+    name: Deploy
+    script: ` + scriptPath + `
+    inherit_env: [KUBECONFIG, "AWS_*"]
+`
+
+	err := os.WriteFile(actionsPath, []byte(actionsContent), 0644)
+	require.NoError(t, err)
+
+	actions, err := config.LoadActions(actionsPath)
+	require.NoError(t, err)
+
+	inherit := make(map[string][]string)
+	for _, action := range actions.Actions {
+		inherit[action.ID] = action.InheritEnv
+	}
+	assert.Equal(t, []string{"HTTPS_PROXY"}, inherit["alert.created"])
+	assert.Equal(t, []string{"HTTPS_PROXY", "KUBECONFIG", "AWS_*"}, inherit["deploy"])
+
+	// Malformed patterns are rejected
+	invalid := strings.Replace(actionsContent, `"AWS_*"`, `"AWS_["`, 1)
+	require.NoError(t, os.WriteFile(actionsPath, []byte(invalid), 0644))
+	_, err = config.LoadActions(actionsPath)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `inherit_env entry "AWS_[" must be a variable name or pattern`)
+}
+
+func TestLoadActions_ConnectorEnvInTemplate(t *testing.T) {
+	tmpDir := t.TempDir()
+	actionsPath := filepath.Join(tmpDir, "actions.yml")
+
+	actionsContent := `
+on:
+  alert.created:
+    http:
+      url: "https://hooks.example.com/alerts"
+      headers:
+        Authorization: "Bearer {{ env.REC_API_KEY }}"
+`
+
+	err := os.WriteFile(actionsPath, []byte(actionsContent), 0644)
+	require.NoError(t, err)
+
+	_, err = config.LoadActions(actionsPath)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "templates must not read connector environment variable env.REC_API_KEY")
+}
+
+func TestLoadActions_MaxOutputBytes(t *testing.T) {
+	tmpDir := t.TempDir()
+	actionsPath := filepath.Join(tmpDir, "actions.yml")
+	scriptPath := filepath.Join(tmpDir, "dump.sh")
+	require.NoError(t, os.WriteFile(scriptPath, []byte("#!/bin/bash\n"), 0755))
+
+	actionsContent := `
+callable:
+  dump_logs:
+    name: Dump Logs
+    script: ` + scriptPath + `
+    max_output_bytes: 50000
+`
+
+	err := os.WriteFile(actionsPath, []byte(actionsContent), 0644)
+	require.NoError(t, err)
+
+	actions, err := config.LoadActions(actionsPath)
+	require.NoError(t, err)
+	require.Len(t, actions.Actions, 1)
+	assert.Equal(t, 50000, actions.Actions[0].MaxOutput)
+
+	invalid := strings.Replace(actionsContent, "50000", "-1", 1)
+	require.NoError(t, os.WriteFile(actionsPath, []byte(invalid), 0644))
+	_, err = config.LoadActions(actionsPath)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "max_output_bytes must not be negative")
+}
+
+func TestLoadActions_Progress(t *testing.T) {
+	tmpDir := t.TempDir()
+	actionsPath := filepath.Join(tmpDir, "actions.yml")
+	scriptPath := filepath.Join(tmpDir, "failover.sh")
+	require.NoError(t, os.WriteFile(scriptPath, []byte("#!/bin/bash\n"), 0755))
+
+	actionsContent := `
+callable:
+  failover_db:
+    name: Failover Database
+    script: ` + scriptPath + `
+    progress: true
+`
+
+	err := os.WriteFile(actionsPath, []byte(actionsContent), 0644)
+	require.NoError(t, err)
+
+	actions, err := config.LoadActions(actionsPath)
+	require.NoError(t, err)
+	require.Len(t, actions.Actions, 1)
+	assert.True(t, actions.Actions[0].Progress)
+
+	// Only scripts stream output
+	invalid := `
+callable:
+  notify:
+    name: Notify
+    type: http
+    http:
+      url: https://example.com/hook
+    progress: true
+`
+	require.NoError(t, os.WriteFile(actionsPath, []byte(invalid), 0644))
+	_, err = config.LoadActions(actionsPath)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "progress is only supported for script actions")
+}
+
+func TestLoadActions_Retry(t *testing.T) {
+	tmpDir := t.TempDir()
+	actionsPath := filepath.Join(tmpDir, "actions.yml")
+	scriptPath := filepath.Join(tmpDir, "restart.sh")
+	require.NoError(t, os.WriteFile(scriptPath, []byte("#!/bin/bash\n"), 0755))
+
+	actionsContent := `
+on:
+  alert.created:
+    script: ` + scriptPath + `
+    retry:
+      max_attempts: 3
+      exit_codes: [75]
+
+callable:
+  notify:
+    name: Notify
+    type: http
+    http:
+      url: https://example.com/hook
+    retry:
+      max_attempts: 5
+      backoff: linear
+      delay_ms: 500
+      http_statuses: [429, 503]
+`
+
+	err := os.WriteFile(actionsPath, []byte(actionsContent), 0644)
+	require.NoError(t, err)
+
+	actions, err := config.LoadActions(actionsPath)
+	require.NoError(t, err)
+
+	policies := make(map[string]*config.RetryPolicy)
+	for _, action := range actions.Actions {
+		policies[action.ID] = action.Retry
+	}
+
+	script := policies["alert.created"]
+	require.NotNil(t, script)
+	assert.Equal(t, 3, script.MaxAttempts)
+	assert.Equal(t, "exponential", script.Backoff, "Default backoff")
+	assert.Equal(t, 1000, script.DelayMs, "Default delay")
+	assert.Equal(t, 60000, script.MaxDelayMs, "Default max delay")
+	assert.Equal(t, []int{75}, script.ExitCodes)
+
+	webhook := policies["notify"]
+	require.NotNil(t, webhook)
+	assert.Equal(t, "linear", webhook.Backoff)
+	assert.Equal(t, 500, webhook.DelayMs)
+	assert.Equal(t, []int{429, 503}, webhook.HTTPStatuses)
+}
+
+func TestLoadActions_RetryInvalid(t *testing.T) {
+	tmpDir := t.TempDir()
+	actionsPath := filepath.Join(tmpDir, "actions.yml")
+	scriptPath := filepath.Join(tmpDir, "restart.sh")
+	require.NoError(t, os.WriteFile(scriptPath, []byte("#!/bin/bash\n"), 0755))
+
+	tests := []struct {
+		name  string
+		retry string
+		err   string
+	}{
+		{"missing max_attempts", "backoff: linear", "retry: max_attempts must be at least 1"},
+		{"unknown backoff", "max_attempts: 2\n      backoff: random", "retry: backoff must be 'exponential' or 'linear'"},
+		{"negative delay", "max_attempts: 2\n      delay_ms: -1", "retry: delay_ms and max_delay_ms must not be negative"},
+		{"http statuses on script", "max_attempts: 2\n      http_statuses: [503]", "retry: http_statuses is only supported for http actions"},
+		{"zero exit code", "max_attempts: 2\n      exit_codes: [0]", "retry: exit_codes must not include 0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actionsContent := `
+on:
+  alert.created:
+    script: ` + scriptPath + `
+    retry:
+      ` + tt.retry + `
+`
+			require.NoError(t, os.WriteFile(actionsPath, []byte(actionsContent), 0644))
+
+			_, err := config.LoadActions(actionsPath)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.err)
+		})
+	}
+}
+
+func TestLoadActions_Concurrency(t *testing.T) {
+	tmpDir := t.TempDir()
+	actionsPath := filepath.Join(tmpDir, "actions.yml")
+	scriptPath := filepath.Join(tmpDir, "restart.sh")
+	require.NoError(t, os.WriteFile(scriptPath, []byte("#!/bin/bash\n"), 0755))
+
+	actionsContent := `
+on:
+  alert.created:
+    script: ` + scriptPath + `
+    concurrency: 2
+
+callable:
+  restart_service:
+    name: Restart Service
+    script: ` + scriptPath + `
+    mutex_key: "{{ parameters.service_name }}"
+    on_concurrency_limit: reject
+`
+
+	require.NoError(t, os.WriteFile(actionsPath, []byte(actionsContent), 0644))
+
+	actions, err := config.LoadActions(actionsPath)
+	require.NoError(t, err)
+
+	byID := make(map[string]config.Action)
+	for _, action := range actions.Actions {
+		byID[action.ID] = action
+	}
+
+	automatic := byID["alert.created"]
+	assert.Equal(t, 2, automatic.ConcurrencyLimit())
+	assert.Empty(t, automatic.MutexKey)
+	assert.Equal(t, config.ConcurrencyQueue, automatic.OnConcurrencyLimit, "Default policy")
+
+	callable := byID["restart_service"]
+	assert.Equal(t, 1, callable.ConcurrencyLimit(), "mutex_key alone allows one delivery per key")
+	assert.Equal(t, "{{ parameters.service_name }}", callable.MutexKey)
+	assert.Equal(t, config.ConcurrencyReject, callable.OnConcurrencyLimit)
+}
+
+func TestLoadActions_ConcurrencyInvalid(t *testing.T) {
+	tmpDir := t.TempDir()
+	actionsPath := filepath.Join(tmpDir, "actions.yml")
+	scriptPath := filepath.Join(tmpDir, "restart.sh")
+	require.NoError(t, os.WriteFile(scriptPath, []byte("#!/bin/bash\n"), 0755))
+
+	tests := []struct {
+		name        string
+		concurrency string
+		err         string
+	}{
+		{"negative concurrency", "concurrency: -1", "concurrency must not be negative"},
+		{"invalid mutex key", `mutex_key: "{% if service %}"`, "mutex_key is invalid"},
+		{"unknown policy", "concurrency: 1\n    on_concurrency_limit: wait", "on_concurrency_limit must be one of: queue, reject, drop"},
+		{"policy without limit", "on_concurrency_limit: drop", "on_concurrency_limit requires concurrency or mutex_key"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actionsContent := `
+on:
+  alert.created:
+    script: ` + scriptPath + `
+    ` + tt.concurrency + `
+`
+			require.NoError(t, os.WriteFile(actionsPath, []byte(actionsContent), 0644))
+
+			_, err := config.LoadActions(actionsPath)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.err)
+		})
+	}
+}
+
+func TestLoadActions_RateLimit(t *testing.T) {
+	tmpDir := t.TempDir()
+	actionsPath := filepath.Join(tmpDir, "actions.yml")
+
+	actionsContent := `
+on:
+  alert.created:
+    http:
+      url: https://hooks.example.com/alerts
+    rate_limit:
+      per_sec: 0.5
+      burst: 10
+
+callable:
+  notify:
+    name: Notify
+    http:
+      url: https://hooks.example.com/notify
+    rate_limit:
+      per_sec: 1
+`
+
+	require.NoError(t, os.WriteFile(actionsPath, []byte(actionsContent), 0644))
+
+	actions, err := config.LoadActions(actionsPath)
+	require.NoError(t, err)
+
+	limits := make(map[string]*config.RateLimit)
+	for _, action := range actions.Actions {
+		limits[action.ID] = action.RateLimit
+	}
+	assert.Equal(t, &config.RateLimit{PerSec: 0.5, Burst: 10}, limits["alert.created"])
+	assert.Equal(t, &config.RateLimit{PerSec: 1}, limits["notify"])
+}
+
+func TestLoadActions_RateLimitInvalid(t *testing.T) {
+	tmpDir := t.TempDir()
+	actionsPath := filepath.Join(tmpDir, "actions.yml")
+
+	tests := []struct {
+		name    string
+		content string
+		err     string
+	}{
+		{
+			name:    "missing per_sec",
+			content: "on:\n  alert.created:\n    http:\n      url: https://hooks.example.com\n    rate_limit:\n      burst: 5\n",
+			err:     "rate_limit.per_sec must be greater than 0",
+		},
+		{
+			name:    "negative burst",
+			content: "on:\n  alert.created:\n    http:\n      url: https://hooks.example.com\n    rate_limit:\n      per_sec: 1\n      burst: -1\n",
+			err:     "rate_limit.burst must not be negative",
+		},
+		{
+			name: "pipeline step",
+			content: "on:\n  alert.created:\n    - http:\n        url: https://hooks.example.com\n      rate_limit:\n        per_sec: 1\n" +
+				"    - http:\n        url: https://hooks.example.com/2\n",
+			err: "rate_limit is only supported on the pipeline",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.NoError(t, os.WriteFile(actionsPath, []byte(tt.content), 0644))
+
+			_, err := config.LoadActions(actionsPath)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.err)
+		})
+	}
+}
+
+func TestLoadActions_WildcardTriggers(t *testing.T) {
+	tmpDir := t.TempDir()
+	actionsPath := filepath.Join(tmpDir, "actions.yml")
+
+	actionsContent := `
+on:
+  "*":
+    http:
+      url: "https://audit.example.com/log"
+  alert.*:
+    http:
+      url: "https://hooks.example.com/alerts"
+  alert.all:
+    http:
+      url: "https://hooks.example.com/custom"
+`
+
+	err := os.WriteFile(actionsPath, []byte(actionsContent), 0644)
+	require.NoError(t, err)
+
+	actions, err := config.LoadActions(actionsPath)
+	require.NoError(t, err)
+	require.Len(t, actions.Actions, 3, "alert.* and a custom alert.all event type are distinct actions")
+	assert.Equal(t, "*", actions.Actions[0].ID)
+	assert.Equal(t, "alert.*", actions.Actions[1].ID)
+	assert.Equal(t, "alert.all", actions.Actions[2].ID)
+}
+
+func TestLoadActions_WildcardTriggerWithConfiguredEventTypes(t *testing.T) {
+	actionsPath := filepath.Join(t.TempDir(), "actions.yml")
+	content := `
+event_types:
+  - deploy.finished
+  - alert.resolved
+on:
+  deploy.*:
+    http:
+      url: "https://hooks.example.com/deploys"
+  alert.*:
+    http:
+      url: "https://hooks.example.com/alerts"
+`
+	require.NoError(t, os.WriteFile(actionsPath, []byte(content), 0644))
+
+	actions, err := config.LoadActions(actionsPath)
+	require.NoError(t, err)
+	require.Len(t, actions.Actions, 2)
+	assert.Equal(t, []string{"alert.created", "alert.updated", "alert.deleted", "alert.resolved"}, actions.Actions[0].PatternEventTypes)
+	assert.Equal(t, []string{"deploy.finished"}, actions.Actions[1].PatternEventTypes)
+}
+
+func TestLoadActions_WildcardTriggerInvalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected string
+	}{
+		{
+			name: "unsupported glob syntax",
+			content: `
+on:
+  alert.[cu]*:
+    http:
+      url: "https://hooks.example.com/alerts"
+`,
+			expected: "only * wildcards are supported",
+		},
+		{
+			name: "callable pattern in on section",
+			content: `
+on:
+  "*.action_triggered":
+    http:
+      url: "https://hooks.example.com/alerts"
+`,
+			expected: "automatic actions cannot use callable trigger patterns",
+		},
+		{
+			name: "automatic pattern in callable section",
+			content: `
+callable:
+  restart_service:
+    name: Restart Service
+    trigger: "alert.*"
+    http:
+      url: "https://hooks.example.com/restart"
+`,
+			expected: "callable actions must use callable trigger patterns",
+		},
+		{
+			name: "callable wildcard trigger",
+			content: `
+callable:
+  restart_service:
+    name: Restart Service
+    trigger: "*.action_triggered"
+    http:
+      url: "https://hooks.example.com/restart"
+`,
+			expected: "callable actions cannot use wildcard triggers",
+		},
+		{
+			name: "pattern matching no known event type",
+			content: `
+on:
+  deploy.*:
+    http:
+      url: "https://hooks.example.com/deploys"
+`,
+			expected: `trigger "deploy.*" matches no known event type`,
+		},
+		{
+			name: "pattern in event_types",
+			content: `
+event_types: ["deploy.*"]
+on:
+  alert.created:
+    http:
+      url: "https://hooks.example.com/alerts"
+`,
+			expected: `event_types: "deploy.*" must be a concrete event type`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actionsPath := filepath.Join(t.TempDir(), "actions.yml")
+			require.NoError(t, os.WriteFile(actionsPath, []byte(tt.content), 0644))
+
+			_, err := config.LoadActions(actionsPath)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.expected)
+		})
+	}
+}
@@ -3,15 +3,21 @@ package config
 import (
 	_ "embed"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/url"
 	"os"
+	"path"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"strings"
 
 	"github.com/gosimple/slug"
+	"github.com/osteele/liquid"
 	"github.com/xeipuuv/gojsonschema"
+
+	"github.com/rootly/edge-connector/internal/backoff"
 )
 
 //go:embed parameters_schema.json
@@ -39,12 +45,38 @@ func Validate(cfg *Config) error {
 	if cfg.Poller.PollingWaitIntervalMs < 1000 {
 		return fmt.Errorf("poller.polling_wait_interval_ms must be at least 1000")
 	}
+	if cfg.Poller.MinPollingIntervalMs < 1000 || cfg.Poller.MinPollingIntervalMs > cfg.Poller.PollingWaitIntervalMs {
+		return fmt.Errorf("poller.min_polling_interval_ms must be between 1000 and poller.polling_wait_interval_ms")
+	}
+	if cfg.Poller.MaxPollingIntervalMs < cfg.Poller.PollingWaitIntervalMs {
+		return fmt.Errorf("poller.max_polling_interval_ms must not be less than poller.polling_wait_interval_ms")
+	}
+	if cfg.Poller.PollingJitter < 0 || cfg.Poller.PollingJitter > 0.5 {
+		return fmt.Errorf("poller.polling_jitter must be between 0 and 0.5")
+	}
 	if cfg.Poller.VisibilityTimeoutSec < 1 {
 		return fmt.Errorf("poller.visibility_timeout_sec must be at least 1")
 	}
+	if cfg.Poller.HeartbeatIntervalSec < 1 || cfg.Poller.HeartbeatIntervalSec > cfg.Poller.VisibilityTimeoutSec {
+		return fmt.Errorf("poller.heartbeat_interval_sec must be between 1 and poller.visibility_timeout_sec")
+	}
 	if cfg.Poller.MaxNumberOfMessages < 1 || cfg.Poller.MaxNumberOfMessages > 100 {
 		return fmt.Errorf("poller.max_number_of_messages must be between 1 and 100")
 	}
+	if cfg.Poller.MaxPagesPerPoll < 1 {
+		return fmt.Errorf("poller.max_pages_per_poll must be at least 1")
+	}
+	if cfg.Poller.Mode != PollerModePoll && cfg.Poller.Mode != PollerModeStream {
+		return fmt.Errorf("poller.mode must be 'poll' or 'stream'")
+	}
+	if cfg.Poller.Mode == PollerModeStream {
+		if cfg.Poller.Stream.IdleTimeoutSec < 1 {
+			return fmt.Errorf("poller.stream.idle_timeout_sec must be at least 1")
+		}
+		if cfg.Poller.Stream.ReconnectMaxSec < 1 {
+			return fmt.Errorf("poller.stream.reconnect_max_sec must be at least 1")
+		}
+	}
 	if cfg.Poller.RetryBackoff != "exponential" && cfg.Poller.RetryBackoff != "linear" {
 		return fmt.Errorf("poller.retry_backoff must be 'exponential' or 'linear'")
 	}
@@ -68,6 +100,34 @@ func Validate(cfg *Config) error {
 		return fmt.Errorf("security.script_timeout must be at least 1")
 	}
 
+	// Validate Dedup config
+	if cfg.Dedup.TTLSec < 0 {
+		return fmt.Errorf("dedup.ttl_sec must not be negative")
+	}
+
+	// Validate HTTP targets config
+	if err := validateHTTPTarget("http_targets", cfg.HTTPTargets.HTTPTargetConfig); err != nil {
+		return err
+	}
+	for host, target := range cfg.HTTPTargets.Hosts {
+		if err := validateHTTPTarget("http_targets.hosts."+host, target); err != nil {
+			return err
+		}
+	}
+
+	// Validate Output config
+	if cfg.Output.MaxBytes < 0 {
+		return fmt.Errorf("output.max_bytes must not be negative")
+	}
+
+	// Validate Progress config
+	if cfg.Progress.IntervalMs < 0 {
+		return fmt.Errorf("progress.interval_ms must not be negative")
+	}
+	if cfg.Progress.MaxUpdatesPerSec < 0 {
+		return fmt.Errorf("progress.max_updates_per_sec must not be negative")
+	}
+
 	// Validate Logging config
 	validLevels := []string{"trace", "debug", "info", "warn", "error", "fatal", "panic"}
 	if !contains(validLevels, cfg.Logging.Level) {
@@ -117,8 +177,22 @@ func ValidateActions(cfg *ActionsConfig) error {
 // ValidateActionsConfig validates the on/callable sections before conversion
 // This ensures trigger patterns match their section (callable vs automatic)
 func ValidateActionsConfig(cfg *ActionsConfig) error {
+	// Validate extra event types for wildcard triggers
+	for _, eventType := range cfg.EventTypes {
+		if eventType == "" || IsEventTypePattern(eventType) || strings.ContainsAny(eventType, `?[]\`) {
+			return fmt.Errorf("event_types: %q must be a concrete event type", eventType)
+		}
+		if isCallableTriggerPattern(eventType) {
+			return fmt.Errorf("event_types: %q is a callable event type; wildcard triggers only match automatic event types", eventType)
+		}
+	}
+
 	// Validate "on" section (automatic actions)
+	known := cfg.KnownEventTypes()
 	for eventType := range cfg.On {
+		if err := validateEventTypePattern(eventType, known); err != nil {
+			return fmt.Errorf("on.%s: %w", eventType, err)
+		}
 		if isCallableTriggerPattern(eventType) {
 			return fmt.Errorf("on.%s: automatic actions cannot use callable trigger patterns (action.triggered or *.action_triggered)", eventType)
 		}
@@ -136,6 +210,9 @@ func ValidateActionsConfig(cfg *ActionsConfig) error {
 		if !isCallableTriggerPattern(trigger) {
 			return fmt.Errorf("callable.%s: callable actions must use callable trigger patterns (action.triggered or *.action_triggered), got: %s", slug, trigger)
 		}
+		if IsEventTypePattern(trigger) {
+			return fmt.Errorf("callable.%s: %w", slug, errCallablePattern)
+		}
 
 		// Callable actions must have a name for UI display
 		if callableAction.Name == "" {
@@ -152,6 +229,31 @@ func isCallableTriggerPattern(trigger string) bool {
 	return trigger == "action.triggered" || strings.HasSuffix(trigger, ".action_triggered")
 }
 
+// errCallablePattern is returned for a callable action with a wildcard trigger
+// A callable action is registered once, under its slug, so it needs a single concrete trigger
+var errCallablePattern = errors.New("callable actions cannot use wildcard triggers; use action.triggered, alert.action_triggered or incident.action_triggered")
+
+// validateEventTypePattern checks that a trigger event type only uses the supported * wildcard
+// and that a pattern matches at least one known event type it can be registered for
+func validateEventTypePattern(eventType string, known []string) error {
+	if err := validateEventTypeSyntax(eventType); err != nil {
+		return err
+	}
+	if IsEventTypePattern(eventType) && !isCallableTriggerPattern(eventType) && len(ExpandEventTypePattern(eventType, known)) == 0 {
+		return fmt.Errorf("trigger %q matches no known event type (%s); wildcard triggers are registered only for these, so list other event types under event_types",
+			eventType, strings.Join(known, ", "))
+	}
+	return nil
+}
+
+// validateEventTypeSyntax checks that a trigger event type only uses the supported * wildcard
+func validateEventTypeSyntax(eventType string) error {
+	if strings.ContainsAny(eventType, `?[]\`) {
+		return fmt.Errorf("trigger %q: only * wildcards are supported in event type patterns", eventType)
+	}
+	return nil
+}
+
 // validateAction validates a single action
 func validateAction(action *Action) error {
 	// ID is required (machine identifier)
@@ -160,13 +262,39 @@ func validateAction(action *Action) error {
 	}
 
 	// ID must be valid: lowercase alphanumeric with underscores/hyphens/dots
-	// Allows: restart_server, send-webhook, clear_cache_v2, alert.created (for on: actions)
-	if !regexp.MustCompile(`^[a-z0-9][a-z0-9_.-]*[a-z0-9]$|^[a-z0-9]$`).MatchString(action.ID) {
+	// Allows: restart_server, send-webhook, clear_cache_v2, alert.created and alert.* (for on: actions)
+	isOnPattern := IsEventTypePattern(action.ID) && action.ID == action.Trigger.EventType
+	if !isOnPattern && !regexp.MustCompile(`^[a-z0-9][a-z0-9_.-]*[a-z0-9]$|^[a-z0-9]$`).MatchString(action.ID) {
 		return fmt.Errorf("id must be lowercase alphanumeric with underscores/hyphens/dots: %s", action.ID)
 	}
 
 	// Name is optional (human-readable display name)
 
+	// Validate when condition syntax up front so a typo fails at load time, not per event
+	if action.When != "" {
+		if _, err := liquid.NewEngine().ParseString(WhenTemplate(action.When)); err != nil {
+			return fmt.Errorf("when is invalid: %w", err)
+		}
+	}
+
+	if name := connectorEnvReference(action); name != "" {
+		return fmt.Errorf("templates must not read connector environment variable env.%s", name)
+	}
+
+	if err := validateConcurrency(action); err != nil {
+		return err
+	}
+	if action.RateLimit != nil {
+		if err := validateRateLimit("rate_limit", action.RateLimit); err != nil {
+			return err
+		}
+	}
+
+	// Pipelines carry no script/http config of their own; each step is validated instead
+	if action.Type == actionTypePipeline {
+		return validatePipeline(action)
+	}
+
 	// Validate type
 	if action.Type != "script" && action.Type != "http" {
 		return fmt.Errorf("type must be 'script' or 'http'")
@@ -180,8 +308,15 @@ func validateAction(action *Action) error {
 
 	// Validate trigger compatibility with action classification
 	// Actions are classified as callable (user-initiated) or automatic (event-triggered) based on presence of Name field
+	// Patterns follow the same rules: *.action_triggered is callable, alert.* or * is automatic
 	for _, eventType := range eventTypes {
+		if err := validateEventTypeSyntax(eventType); err != nil {
+			return err
+		}
 		if action.Name != "" {
+			if IsEventTypePattern(eventType) {
+				return errCallablePattern
+			}
 			// Action has Name field = callable intent
 			// Must use callable trigger patterns: action.triggered, alert.action_triggered, incident.action_triggered
 			if !isCallableTriggerPattern(eventType) {
@@ -205,6 +340,14 @@ func validateAction(action *Action) error {
 		if action.Script == "" {
 			return fmt.Errorf("script is required for script actions")
 		}
+		if action.Input != "" && action.Input != InputEnv && action.Input != InputStdin && action.Input != InputFile {
+			return fmt.Errorf("input must be one of: %s, %s, %s", InputEnv, InputStdin, InputFile)
+		}
+		for _, pattern := range action.InheritEnv {
+			if _, err := path.Match(pattern, ""); pattern == "" || err != nil {
+				return fmt.Errorf("inherit_env entry %q must be a variable name or pattern (e.g. AWS_*)", pattern)
+			}
+		}
 		if action.SourceType == "local" {
 			// Check if script file exists
 			if !filepath.IsAbs(action.Script) {
@@ -218,6 +361,12 @@ func validateAction(action *Action) error {
 
 	// Validate HTTP action
 	if action.Type == "http" {
+		if action.Input != "" {
+			return fmt.Errorf("input is only supported for script actions")
+		}
+		if action.Progress {
+			return fmt.Errorf("progress is only supported for script actions")
+		}
 		if action.HTTP == nil {
 			return fmt.Errorf("http configuration is required for http actions")
 		}
@@ -251,6 +400,16 @@ func validateAction(action *Action) error {
 		return fmt.Errorf("timeout must be at least 1")
 	}
 
+	if action.MaxOutput < 0 {
+		return fmt.Errorf("max_output_bytes must not be negative")
+	}
+
+	if action.Retry != nil {
+		if err := validateRetryPolicy(action.Retry, action.Type); err != nil {
+			return fmt.Errorf("retry: %w", err)
+		}
+	}
+
 	// Validate parameter definitions
 	if err := validateParameterDefinitions(action.ParameterDefinitions); err != nil {
 		return fmt.Errorf("parameter_definitions: %w", err)
@@ -259,6 +418,118 @@ func validateAction(action *Action) error {
 	return nil
 }
 
+// validateRetryPolicy validates the retry block of a script or HTTP action
+func validateRetryPolicy(retry *RetryPolicy, actionType string) error {
+	if retry.MaxAttempts < 1 {
+		return fmt.Errorf("max_attempts must be at least 1")
+	}
+	if retry.Backoff != backoff.Exponential && retry.Backoff != backoff.Linear {
+		return fmt.Errorf("backoff must be '%s' or '%s'", backoff.Exponential, backoff.Linear)
+	}
+	if retry.DelayMs < 0 || retry.MaxDelayMs < 0 {
+		return fmt.Errorf("delay_ms and max_delay_ms must not be negative")
+	}
+
+	if len(retry.ExitCodes) > 0 && actionType != defaultActionType {
+		return fmt.Errorf("exit_codes is only supported for script actions")
+	}
+	if slices.Contains(retry.ExitCodes, 0) {
+		return fmt.Errorf("exit_codes must not include 0")
+	}
+
+	if len(retry.HTTPStatuses) > 0 && actionType != defaultActionTypeHTTP {
+		return fmt.Errorf("http_statuses is only supported for http actions")
+	}
+	for _, status := range retry.HTTPStatuses {
+		if status < 100 || status > 599 {
+			return fmt.Errorf("http_statuses must be between 100 and 599, got %d", status)
+		}
+	}
+	return nil
+}
+
+// validateConcurrency validates the concurrency limit, mutex key template and limit policy
+func validateConcurrency(action *Action) error {
+	if action.Concurrency < 0 {
+		return fmt.Errorf("concurrency must not be negative")
+	}
+	if action.MutexKey != "" {
+		if _, err := liquid.NewEngine().ParseString(action.MutexKey); err != nil {
+			return fmt.Errorf("mutex_key is invalid: %w", err)
+		}
+	}
+	if action.OnConcurrencyLimit == "" {
+		return nil
+	}
+	if action.OnConcurrencyLimit != ConcurrencyQueue && action.OnConcurrencyLimit != ConcurrencyReject && action.OnConcurrencyLimit != ConcurrencyDrop {
+		return fmt.Errorf("on_concurrency_limit must be one of: %s, %s, %s", ConcurrencyQueue, ConcurrencyReject, ConcurrencyDrop)
+	}
+	if action.ConcurrencyLimit() == 0 {
+		return fmt.Errorf("on_concurrency_limit requires concurrency or mutex_key")
+	}
+	return nil
+}
+
+// validateRateLimit validates a token bucket limit; prefix names its location in the config
+func validateRateLimit(prefix string, limit *RateLimit) error {
+	if limit.PerSec <= 0 {
+		return fmt.Errorf("%s.per_sec must be greater than 0", prefix)
+	}
+	if limit.Burst < 0 {
+		return fmt.Errorf("%s.burst must not be negative", prefix)
+	}
+	return nil
+}
+
+// validateHTTPTarget validates the rate limit and circuit breaker for a destination host
+func validateHTTPTarget(prefix string, target HTTPTargetConfig) error {
+	if target.RateLimit != nil {
+		if err := validateRateLimit(prefix+".rate_limit", target.RateLimit); err != nil {
+			return err
+		}
+	}
+	if cb := target.CircuitBreaker; cb != nil {
+		if cb.FailureThreshold < 1 {
+			return fmt.Errorf("%s.circuit_breaker.failure_threshold must be at least 1", prefix)
+		}
+		if cb.CooldownSec < 0 {
+			return fmt.Errorf("%s.circuit_breaker.cooldown_sec must not be negative", prefix)
+		}
+	}
+	return nil
+}
+
+// validatePipeline validates a pipeline action and each of its steps
+func validatePipeline(action *Action) error {
+	if len(action.Steps) == 0 {
+		return fmt.Errorf("pipeline must have at least one step")
+	}
+
+	stepIDs := make(map[string]bool)
+	for i := range action.Steps {
+		step := &action.Steps[i]
+		if stepIDs[step.ID] {
+			return fmt.Errorf("duplicate step id: %s", step.ID)
+		}
+		stepIDs[step.ID] = true
+
+		if step.Type == actionTypePipeline {
+			return fmt.Errorf("steps[%d] (%s): pipelines cannot be nested", i, step.ID)
+		}
+		if step.Concurrency != 0 || step.MutexKey != "" || step.OnConcurrencyLimit != "" {
+			return fmt.Errorf("steps[%d] (%s): concurrency, mutex_key and on_concurrency_limit are only supported on the pipeline", i, step.ID)
+		}
+		if step.RateLimit != nil {
+			return fmt.Errorf("steps[%d] (%s): rate_limit is only supported on the pipeline", i, step.ID)
+		}
+		if err := validateAction(step); err != nil {
+			return fmt.Errorf("steps[%d] (%s): %w", i, step.ID, err)
+		}
+	}
+
+	return nil
+}
+
 // validateParameterDefinitions validates parameter definitions against the backend's JSON Schema
 // This ensures compatibility with the backend's validation rules:
 // - All parameters must have name (non-empty) and type
@@ -303,13 +574,20 @@ func validateParameterDefinitions(params []ParameterDefinition) error {
 }
 
 // validateParameterDefaults validates that default values are valid for their parameter type
+// For number and boolean types the default must coerce to that type.
 // For list type:
 // - default value must be one of the options
 // - options must not contain duplicates
 func validateParameterDefaults(params []ParameterDefinition) error {
 	for i, param := range params {
+		if param.Default != nil && param.Type != ParameterTypeList {
+			if _, err := param.Coerce(param.Default); err != nil {
+				return fmt.Errorf("[%d] (name=%s): invalid default value: %w", i, param.Name, err)
+			}
+		}
+
 		// For list type, validate options and defaults
-		if param.Type == "list" {
+		if param.Type == ParameterTypeList {
 			// Validate no duplicate options
 			seen := make(map[string]bool)
 			var duplicates []string
@@ -398,3 +676,30 @@ func NormalizeActionName(name string) string {
 
 	return normalized
 }
+
+// connectorEnvPattern matches env.REC_* references; connector variables such as REC_API_KEY are never exposed to templates
+var connectorEnvPattern = regexp.MustCompile(`\benv\.(REC_[A-Z0-9_]*)`)
+
+// connectorEnvReference returns the first connector variable read by one of the action's templates, or ""
+func connectorEnvReference(action *Action) string {
+	templates := []string{action.MutexKey}
+	for _, value := range action.Parameters {
+		templates = append(templates, value)
+	}
+	if action.HTTP != nil {
+		templates = append(templates, action.HTTP.URL, action.HTTP.Body)
+		for _, value := range action.HTTP.Headers {
+			templates = append(templates, value)
+		}
+		for _, value := range action.HTTP.Params {
+			templates = append(templates, value)
+		}
+	}
+
+	for _, tmpl := range templates {
+		if match := connectorEnvPattern.FindStringSubmatch(tmpl); match != nil {
+			return match[1]
+		}
+	}
+	return ""
+}
@@ -195,9 +195,15 @@ func validConfig() *config.Config {
 			APIKey: "test-key",
 		},
 		Poller: config.PollerConfig{
+			Mode:                  config.PollerModePoll,
 			PollingWaitIntervalMs: 5000,
+			MinPollingIntervalMs:  1000,
+			MaxPollingIntervalMs:  30000,
+			PollingJitter:         0.1,
 			VisibilityTimeoutSec:  30,
+			HeartbeatIntervalSec:  10,
 			MaxNumberOfMessages:   10,
+			MaxPagesPerPoll:       10,
 			RetryBackoff:          "exponential",
 		},
 		Pool: config.PoolConfig{
@@ -220,6 +226,65 @@ func validConfig() *config.Config {
 	}
 }
 
+func TestValidate_DedupTTLNegative(t *testing.T) {
+	cfg := validConfig()
+	cfg.Dedup.TTLSec = -1
+
+	err := config.Validate(cfg)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "dedup.ttl_sec must not be negative")
+}
+
+func TestValidate_HTTPTargets(t *testing.T) {
+	tests := []struct {
+		name   string
+		target config.HTTPTargetConfig
+		host   string
+		err    string
+	}{
+		{
+			name:   "zero rate",
+			target: config.HTTPTargetConfig{RateLimit: &config.RateLimit{}},
+			err:    "http_targets.rate_limit.per_sec must be greater than 0",
+		},
+		{
+			name:   "missing failure threshold",
+			target: config.HTTPTargetConfig{CircuitBreaker: &config.CircuitBreakerConfig{CooldownSec: 30}},
+			err:    "http_targets.circuit_breaker.failure_threshold must be at least 1",
+		},
+		{
+			name:   "negative cooldown on host",
+			target: config.HTTPTargetConfig{CircuitBreaker: &config.CircuitBreakerConfig{FailureThreshold: 1, CooldownSec: -1}},
+			host:   "hooks.example.com",
+			err:    "http_targets.hosts.hooks.example.com.circuit_breaker.cooldown_sec must not be negative",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig()
+			if tt.host != "" {
+				cfg.HTTPTargets.Hosts = map[string]config.HTTPTargetConfig{tt.host: tt.target}
+			} else {
+				cfg.HTTPTargets.HTTPTargetConfig = tt.target
+			}
+
+			err := config.Validate(cfg)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.err)
+		})
+	}
+}
+
+func TestValidate_OutputMaxBytesNegative(t *testing.T) {
+	cfg := validConfig()
+	cfg.Output.MaxBytes = -1
+
+	err := config.Validate(cfg)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "output.max_bytes must not be negative")
+}
+
 func TestValidate_MissingAppName(t *testing.T) {
 	cfg := validConfig()
 	cfg.App.Name = "" // Make invalid
@@ -265,6 +330,27 @@ func TestValidate_PollingIntervalTooLow(t *testing.T) {
 	assert.Contains(t, err.Error(), "polling_wait_interval_ms must be at least 1000")
 }
 
+func TestValidate_AdaptivePollingIntervals(t *testing.T) {
+	cfg := validConfig()
+	cfg.Poller.MinPollingIntervalMs = 6000 // Above polling_wait_interval_ms
+
+	err := config.Validate(cfg)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "poller.min_polling_interval_ms must be between 1000 and poller.polling_wait_interval_ms")
+
+	cfg = validConfig()
+	cfg.Poller.MaxPollingIntervalMs = 4000
+	err = config.Validate(cfg)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "poller.max_polling_interval_ms must not be less than poller.polling_wait_interval_ms")
+
+	cfg = validConfig()
+	cfg.Poller.PollingJitter = 0.8
+	err = config.Validate(cfg)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "poller.polling_jitter must be between 0 and 0.5")
+}
+
 func TestValidate_VisibilityTimeoutTooLow(t *testing.T) {
 	cfg := validConfig()
 	cfg.Poller.VisibilityTimeoutSec = 0 // Too low
@@ -274,6 +360,21 @@ func TestValidate_VisibilityTimeoutTooLow(t *testing.T) {
 	assert.Contains(t, err.Error(), "visibility_timeout_sec must be at least 1")
 }
 
+func TestValidate_HeartbeatInterval(t *testing.T) {
+	cfg := validConfig()
+	cfg.Poller.HeartbeatIntervalSec = 0
+
+	err := config.Validate(cfg)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "poller.heartbeat_interval_sec must be between 1 and poller.visibility_timeout_sec")
+
+	cfg.Poller.HeartbeatIntervalSec = 31 // Longer than the lease it renews
+	require.Error(t, config.Validate(cfg))
+
+	cfg.Poller.HeartbeatIntervalSec = 30
+	require.NoError(t, config.Validate(cfg))
+}
+
 func TestValidate_MaxMessagesTooLow(t *testing.T) {
 	cfg := validConfig()
 	cfg.Poller.MaxNumberOfMessages = 0 // Too low
@@ -292,6 +393,33 @@ func TestValidate_MaxMessagesTooHigh(t *testing.T) {
 	assert.Contains(t, err.Error(), "max_number_of_messages must be between 1 and 100")
 }
 
+func TestValidate_MaxPagesPerPollTooLow(t *testing.T) {
+	cfg := validConfig()
+	cfg.Poller.MaxPagesPerPoll = 0
+
+	err := config.Validate(cfg)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "poller.max_pages_per_poll must be at least 1")
+}
+
+func TestValidate_PollerMode(t *testing.T) {
+	cfg := validConfig()
+	cfg.Poller.Mode = "push"
+
+	err := config.Validate(cfg)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "poller.mode must be 'poll' or 'stream'")
+
+	// Stream settings are only checked in stream mode
+	cfg.Poller.Mode = config.PollerModeStream
+	err = config.Validate(cfg)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "poller.stream.idle_timeout_sec must be at least 1")
+
+	cfg.Poller.Stream = config.StreamConfig{IdleTimeoutSec: 60, ReconnectMaxSec: 300}
+	require.NoError(t, config.Validate(cfg))
+}
+
 func TestValidate_InvalidRetryBackoff(t *testing.T) {
 	cfg := validConfig()
 	cfg.Poller.RetryBackoff = "invalid" // Invalid value
@@ -1222,6 +1350,32 @@ func TestParameterValidation_NumberTypeWithDefault(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestParameterValidation_NumberTypeInvalidDefault(t *testing.T) {
+	action := &config.Action{
+		ID:   "test",
+		Type: "http",
+		HTTP: &config.HTTPAction{
+			URL:    "https://example.com",
+			Method: "POST",
+		},
+		Trigger: config.TriggerConfig{
+			EventType: "alert.created",
+		},
+		ParameterDefinitions: []config.ParameterDefinition{
+			{
+				Name:    "count",
+				Type:    "number",
+				Default: "many",
+			},
+		},
+		Timeout: 10,
+	}
+
+	err := config.ValidateActions(&config.ActionsConfig{Actions: []config.Action{*action}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `invalid default value: expected a number, got "many"`)
+}
+
 func TestParameterValidation_BooleanTypeWithDefault(t *testing.T) {
 	action := &config.Action{
 		ID:   "test",
@@ -0,0 +1,281 @@
+package dedup
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/rootly/edge-connector/internal/api"
+	"github.com/rootly/edge-connector/internal/config"
+)
+
+// fileName is the name of the seen-ID file inside the state directory
+const fileName = "dedup.jsonl"
+
+// compactThreshold is the number of appended records after which the file is rewritten
+// with only the IDs still within the TTL
+const compactThreshold = 1000
+
+// Reasons a delivery is recognized as a duplicate
+const (
+	ReasonDeliveryID = "delivery_id" // The same delivery was handed out again (e.g. after the visibility timeout)
+	ReasonEventID    = "event_id"    // A new delivery carries an event that was already delivered
+)
+
+// Duplicate describes the earlier delivery a duplicate matched
+type Duplicate struct {
+	SeenAt     time.Time
+	Reason     string
+	DeliveryID string // Delivery that was seen first
+	EventID    string
+}
+
+// record is a seen delivery, and a single line in the file
+type record struct {
+	SeenAt     time.Time `json:"seen_at"`
+	DeliveryID string    `json:"delivery_id"`
+	EventID    string    `json:"event_id,omitempty"`
+}
+
+// seenEvent tracks the deliveries of one event ID
+// The event is remembered until its latest delivery expires
+type seenEvent struct {
+	first  record
+	lastID string
+}
+
+// Store remembers recently claimed delivery and event IDs for a fixed TTL
+// With persistence enabled, the IDs are also kept in the state directory so they survive a restart
+type Store struct {
+	file       *os.File
+	deliveries map[string]record
+	events     map[string]seenEvent
+	now        func() time.Time
+	path       string
+	order      []record // Oldest first; all records share the TTL so they expire in this order
+	ttl        time.Duration
+	mu         sync.Mutex
+	appended   int
+}
+
+// New creates a store with the configured TTL
+// If persistence is enabled, IDs still within the TTL are loaded from the given state directory
+func New(dir string, cfg *config.DedupConfig) (*Store, error) {
+	s := &Store{
+		deliveries: make(map[string]record),
+		events:     make(map[string]seenEvent),
+		now:        time.Now,
+		ttl:        time.Duration(cfg.TTLSec) * time.Second,
+	}
+
+	if !cfg.Persist {
+		return s, nil
+	}
+
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create state directory: %w", err)
+	}
+	s.path = filepath.Join(dir, fileName)
+
+	if err := s.load(); err != nil {
+		return nil, err
+	}
+
+	// Start from a compact file holding only IDs still within the TTL
+	if err := s.compactLocked(); err != nil {
+		return nil, err
+	}
+
+	if len(s.order) > 0 {
+		log.WithFields(log.Fields{
+			"count": len(s.order),
+			"path":  s.path,
+		}).Debug("Loaded recently seen deliveries")
+	}
+
+	return s, nil
+}
+
+// Close closes the store's file
+func (s *Store) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.file == nil {
+		return nil
+	}
+	err := s.file.Close()
+	s.file = nil
+	return err
+}
+
+// Check returns the earlier delivery an event duplicates, or nil if neither its delivery ID
+// nor its event ID was seen within the TTL
+func (s *Store) Check(event api.Event) *Duplicate {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.expireLocked(s.now())
+
+	if rec, ok := s.deliveries[event.ID]; ok {
+		return rec.duplicate(ReasonDeliveryID)
+	}
+	if event.EventID != "" {
+		if seen, ok := s.events[event.EventID]; ok {
+			return seen.first.duplicate(ReasonEventID)
+		}
+	}
+	return nil
+}
+
+// Record remembers a claimed delivery and its event ID
+func (s *Store) Record(event api.Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.deliveries[event.ID]; ok {
+		return
+	}
+
+	rec := record{SeenAt: s.now(), DeliveryID: event.ID, EventID: event.EventID}
+	s.add(rec)
+	s.appendLocked(rec)
+
+	if s.appended >= compactThreshold {
+		if err := s.compactLocked(); err != nil {
+			log.WithError(err).Error("Failed to compact dedup file")
+		}
+	}
+}
+
+// duplicate describes r as the earlier delivery matched for reason
+func (r record) duplicate(reason string) *Duplicate {
+	return &Duplicate{SeenAt: r.SeenAt, Reason: reason, DeliveryID: r.DeliveryID, EventID: r.EventID}
+}
+
+// add indexes a record, keeping the first delivery seen for its event ID
+func (s *Store) add(rec record) {
+	s.deliveries[rec.DeliveryID] = rec
+	if rec.EventID != "" {
+		seen, ok := s.events[rec.EventID]
+		if !ok {
+			seen.first = rec
+		}
+		seen.lastID = rec.DeliveryID
+		s.events[rec.EventID] = seen
+	}
+	s.order = append(s.order, rec)
+}
+
+// expireLocked forgets records older than the TTL
+// Caller must hold s.mu
+func (s *Store) expireLocked(now time.Time) {
+	for len(s.order) > 0 && now.Sub(s.order[0].SeenAt) >= s.ttl {
+		rec := s.order[0]
+		s.order = s.order[1:]
+
+		delete(s.deliveries, rec.DeliveryID)
+		if seen, ok := s.events[rec.EventID]; ok && seen.lastID == rec.DeliveryID {
+			delete(s.events, rec.EventID)
+		}
+	}
+}
+
+// load reads the file, skipping lines that cannot be parsed and records past the TTL
+func (s *Store) load() error {
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to read dedup file: %w", err)
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var rec record
+		if err := json.Unmarshal(line, &rec); err != nil {
+			// A torn final line is expected if the process died mid-write
+			log.WithError(err).WithField("path", s.path).Warn("Skipping corrupt dedup record")
+			continue
+		}
+		if _, ok := s.deliveries[rec.DeliveryID]; ok {
+			continue
+		}
+		s.add(rec)
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to parse dedup file: %w", err)
+	}
+
+	s.expireLocked(s.now())
+	return nil
+}
+
+// appendLocked writes a record to the file
+// Caller must hold s.mu
+func (s *Store) appendLocked(rec record) {
+	if s.file == nil {
+		return
+	}
+
+	line, err := json.Marshal(rec)
+	if err != nil {
+		log.WithError(err).WithField("delivery_id", rec.DeliveryID).Error("Failed to encode dedup record")
+		return
+	}
+	line = append(line, '\n')
+
+	if _, err := s.file.Write(line); err != nil {
+		log.WithError(err).WithField("delivery_id", rec.DeliveryID).Error("Failed to write dedup record")
+		return
+	}
+	s.appended++
+}
+
+// compactLocked rewrites the file with only the records within the TTL and reopens it for appending
+// Caller must hold s.mu (or be the only goroutine with access)
+func (s *Store) compactLocked() error {
+	s.expireLocked(s.now())
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	for _, rec := range s.order {
+		if err := encoder.Encode(rec); err != nil {
+			return fmt.Errorf("failed to encode dedup record: %w", err)
+		}
+	}
+
+	tmpPath := s.path + ".tmp"
+	if err := os.WriteFile(tmpPath, buf.Bytes(), 0o600); err != nil {
+		return fmt.Errorf("failed to write dedup file: %w", err)
+	}
+
+	if err := os.Rename(tmpPath, s.path); err != nil {
+		return fmt.Errorf("failed to replace dedup file: %w", err)
+	}
+
+	file, err := os.OpenFile(s.path, os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open dedup file: %w", err)
+	}
+	if s.file != nil {
+		if err := s.file.Close(); err != nil {
+			log.WithError(err).Warn("Failed to close previous dedup file")
+		}
+	}
+	s.file = file
+	s.appended = 0
+
+	return nil
+}
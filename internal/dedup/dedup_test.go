@@ -0,0 +1,132 @@
+package dedup
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rootly/edge-connector/internal/api"
+	"github.com/rootly/edge-connector/internal/config"
+)
+
+// fakeClock returns a settable time for a store
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+func newTestStore(t *testing.T, dir string, cfg *config.DedupConfig, clock *fakeClock) *Store {
+	t.Helper()
+	s, err := New(dir, cfg)
+	require.NoError(t, err)
+	s.now = clock.Now
+	t.Cleanup(func() { assert.NoError(t, s.Close()) })
+	return s
+}
+
+func TestStore_Check(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)}
+	s := newTestStore(t, t.TempDir(), &config.DedupConfig{TTLSec: 60}, clock)
+
+	first := api.Event{ID: "delivery-1", EventID: "event-1"}
+	assert.Nil(t, s.Check(first), "Unseen delivery is not a duplicate")
+	s.Record(first)
+
+	redelivered := s.Check(first)
+	require.NotNil(t, redelivered)
+	assert.Equal(t, ReasonDeliveryID, redelivered.Reason)
+	assert.Equal(t, "delivery-1", redelivered.DeliveryID)
+	assert.Equal(t, clock.now, redelivered.SeenAt)
+
+	sameEvent := s.Check(api.Event{ID: "delivery-2", EventID: "event-1"})
+	require.NotNil(t, sameEvent)
+	assert.Equal(t, ReasonEventID, sameEvent.Reason)
+	assert.Equal(t, "delivery-1", sameEvent.DeliveryID, "Duplicates point at the first delivery of the event")
+
+	assert.Nil(t, s.Check(api.Event{ID: "delivery-3", EventID: "event-3"}))
+	assert.Nil(t, s.Check(api.Event{ID: "delivery-4"}), "Deliveries without an event ID only match by delivery ID")
+}
+
+func TestStore_Expires(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)}
+	s := newTestStore(t, t.TempDir(), &config.DedupConfig{TTLSec: 60}, clock)
+
+	s.Record(api.Event{ID: "delivery-1", EventID: "event-1"})
+	clock.now = clock.now.Add(30 * time.Second)
+	s.Record(api.Event{ID: "delivery-2", EventID: "event-1"})
+
+	clock.now = clock.now.Add(31 * time.Second)
+	assert.Nil(t, s.Check(api.Event{ID: "delivery-1"}), "Delivery ID is forgotten after the TTL")
+
+	// The event is still remembered through the later delivery
+	duplicate := s.Check(api.Event{ID: "delivery-3", EventID: "event-1"})
+	require.NotNil(t, duplicate)
+	assert.Equal(t, "delivery-1", duplicate.DeliveryID)
+	assert.Equal(t, ReasonDeliveryID, s.Check(api.Event{ID: "delivery-2"}).Reason)
+
+	clock.now = clock.now.Add(30 * time.Second)
+	assert.Nil(t, s.Check(api.Event{ID: "delivery-3", EventID: "event-1"}))
+	assert.Empty(t, s.order)
+	assert.Empty(t, s.deliveries)
+}
+
+func TestStore_InMemoryByDefault(t *testing.T) {
+	dir := t.TempDir()
+	clock := &fakeClock{now: time.Now()}
+	s := newTestStore(t, dir, &config.DedupConfig{TTLSec: 60}, clock)
+	s.Record(api.Event{ID: "delivery-1", EventID: "event-1"})
+
+	_, err := os.Stat(filepath.Join(dir, fileName))
+	assert.True(t, os.IsNotExist(err), "Nothing is written without persist")
+}
+
+func TestStore_Persist(t *testing.T) {
+	dir := t.TempDir()
+	cfg := &config.DedupConfig{TTLSec: 60, Persist: true}
+
+	s, err := New(dir, cfg)
+	require.NoError(t, err)
+	s.Record(api.Event{ID: "delivery-1", EventID: "event-1"})
+	s.Record(api.Event{ID: "delivery-2", EventID: "event-2"})
+	require.NoError(t, s.Close())
+
+	// Simulate restart
+	reopened, err := New(dir, cfg)
+	require.NoError(t, err)
+	defer reopened.Close()
+
+	duplicate := reopened.Check(api.Event{ID: "delivery-1"})
+	require.NotNil(t, duplicate)
+	assert.Equal(t, ReasonDeliveryID, duplicate.Reason)
+	assert.Equal(t, ReasonEventID, reopened.Check(api.Event{ID: "delivery-3", EventID: "event-2"}).Reason)
+}
+
+func TestStore_PersistDropsExpiredAndCorruptRecords(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, fileName)
+
+	recent := time.Now().Add(-time.Minute).UTC().Format(time.RFC3339Nano)
+	expired := time.Now().Add(-2 * time.Hour).UTC().Format(time.RFC3339Nano)
+	content := `{"seen_at":"` + expired + `","delivery_id":"delivery-old","event_id":"event-old"}
+{"seen_at":"` + recent + `","delivery_id":"delivery-1","event_id":"event-1"}
+{"seen_at":"` + recent + `","deliv`
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+	s, err := New(dir, &config.DedupConfig{TTLSec: 3600, Persist: true})
+	require.NoError(t, err)
+	defer s.Close()
+
+	assert.Nil(t, s.Check(api.Event{ID: "delivery-old", EventID: "event-old"}))
+	assert.NotNil(t, s.Check(api.Event{ID: "delivery-1"}))
+
+	// The file is compacted on open
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "delivery-old")
+	assert.Contains(t, string(data), "delivery-1")
+}
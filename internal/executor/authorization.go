@@ -0,0 +1,129 @@
+package executor
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rootly/edge-connector/internal/api"
+	"github.com/rootly/edge-connector/internal/config"
+)
+
+// Event payload keys used for authorization checks
+const (
+	fieldTriggeredBy   = "triggered_by"
+	fieldTeams         = "teams"
+	fieldIncidentRoles = "incident_roles"
+	fieldIncidentRole  = "incident_role"
+	fieldApprovedBy    = "approved_by"
+	fieldApproved      = "approved"
+)
+
+// authorize checks the action's authorization rules against the triggering user in the event payload
+// Rules are read from data.triggered_by:
+// - allowed_teams: user must belong to at least one team in data.triggered_by.teams
+// - requires_incident_role: user must hold at least one role in data.triggered_by.incident_roles
+// - requires_approval: event must carry data.approved_by (or data.approved: true)
+// Returns nil when the action has no authorization rules
+func authorize(action *config.Action, event api.Event) error {
+	auth := action.Auth
+	if len(auth.AllowedTeams) == 0 && len(auth.RequiresIncidentRole) == 0 && !auth.RequiresApproval {
+		return nil
+	}
+
+	user, ok := event.Data[fieldTriggeredBy].(map[string]interface{})
+	if !ok || len(user) == 0 {
+		return fmt.Errorf("authorization denied for action '%s': event has no triggered_by user", action.ID)
+	}
+	userLabel := describeUser(user)
+
+	if len(auth.AllowedTeams) > 0 {
+		teams := collectIdentifiers(user[fieldTeams])
+		if !containsAny(teams, auth.AllowedTeams) {
+			return fmt.Errorf("authorization denied for action '%s': user %s is not a member of allowed teams %v",
+				action.ID, userLabel, auth.AllowedTeams)
+		}
+	}
+
+	if len(auth.RequiresIncidentRole) > 0 {
+		roles := collectIdentifiers(user[fieldIncidentRoles])
+		roles = append(roles, collectIdentifiers(user[fieldIncidentRole])...)
+		if !containsAny(roles, auth.RequiresIncidentRole) {
+			return fmt.Errorf("authorization denied for action '%s': user %s does not hold a required incident role %v",
+				action.ID, userLabel, auth.RequiresIncidentRole)
+		}
+	}
+
+	if auth.RequiresApproval && !isApproved(event.Data) {
+		return fmt.Errorf("authorization denied for action '%s': action requires approval but event is not approved", action.ID)
+	}
+
+	return nil
+}
+
+// describeUser returns a short, log-friendly identifier for the triggering user
+func describeUser(user map[string]interface{}) string {
+	for _, key := range []string{"email", fieldName, "id"} {
+		if value, ok := user[key]; ok && value != nil {
+			if str := fmt.Sprintf("%v", value); str != "" {
+				return str
+			}
+		}
+	}
+	return "unknown"
+}
+
+// collectIdentifiers extracts comparable identifiers from a payload value
+// Accepts a single string, a list of strings, or a list of objects carrying slug/name/id fields
+func collectIdentifiers(value interface{}) []string {
+	switch v := value.(type) {
+	case string:
+		if v == "" {
+			return nil
+		}
+		return []string{v}
+	case map[string]interface{}:
+		ids := []string{}
+		for _, key := range []string{fieldSlug, fieldName, "id"} {
+			if raw, ok := v[key]; ok && raw != nil {
+				ids = append(ids, fmt.Sprintf("%v", raw))
+			}
+		}
+		return ids
+	case []interface{}:
+		ids := []string{}
+		for _, item := range v {
+			ids = append(ids, collectIdentifiers(item)...)
+		}
+		return ids
+	case []string:
+		return v
+	default:
+		return nil
+	}
+}
+
+// containsAny reports whether any value matches an allowed entry (case-insensitive)
+func containsAny(values, allowed []string) bool {
+	for _, value := range values {
+		for _, candidate := range allowed {
+			if strings.EqualFold(value, candidate) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isApproved reports whether the event payload records an approval
+func isApproved(data map[string]interface{}) bool {
+	if approved, ok := data[fieldApproved].(bool); ok && approved {
+		return true
+	}
+	switch approver := data[fieldApprovedBy].(type) {
+	case map[string]interface{}:
+		return len(approver) > 0
+	case string:
+		return approver != ""
+	}
+	return false
+}
@@ -0,0 +1,176 @@
+package executor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rootly/edge-connector/internal/api"
+	"github.com/rootly/edge-connector/internal/config"
+	"github.com/rootly/edge-connector/internal/reporter"
+)
+
+func TestAuthorize_NoRules(t *testing.T) {
+	action := &config.Action{ID: "open_action"}
+	event := api.Event{Data: map[string]interface{}{}}
+
+	assert.NoError(t, authorize(action, event))
+}
+
+func TestAuthorize_MissingTriggeredBy(t *testing.T) {
+	action := &config.Action{
+		ID:   "restart_service",
+		Auth: config.Authorization{AllowedTeams: []string{"sre"}},
+	}
+	event := api.Event{Data: map[string]interface{}{}}
+
+	err := authorize(action, event)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no triggered_by user")
+}
+
+func TestAuthorize_AllowedTeams(t *testing.T) {
+	action := &config.Action{
+		ID:   "restart_service",
+		Auth: config.Authorization{AllowedTeams: []string{"sre", "engineering"}},
+	}
+
+	tests := []struct {
+		name    string
+		teams   interface{}
+		allowed bool
+	}{
+		{"string list match", []interface{}{"marketing", "sre"}, true},
+		{"object list match by slug", []interface{}{map[string]interface{}{"id": 1, "name": "Engineering", "slug": "engineering"}}, true},
+		{"case-insensitive match", []interface{}{"SRE"}, true},
+		{"single string match", "sre", true},
+		{"no matching team", []interface{}{"marketing"}, false},
+		{"no teams", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event := api.Event{
+				Data: map[string]interface{}{
+					"triggered_by": map[string]interface{}{
+						"email": "jane@example.com",
+						"teams": tt.teams,
+					},
+				},
+			}
+
+			err := authorize(action, event)
+			if tt.allowed {
+				assert.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), "jane@example.com")
+				assert.Contains(t, err.Error(), "allowed teams")
+			}
+		})
+	}
+}
+
+func TestAuthorize_RequiresIncidentRole(t *testing.T) {
+	action := &config.Action{
+		ID:   "failover_db",
+		Auth: config.Authorization{RequiresIncidentRole: []string{"commander"}},
+	}
+
+	// Role list
+	event := api.Event{
+		Data: map[string]interface{}{
+			"triggered_by": map[string]interface{}{
+				"id":             50,
+				"incident_roles": []interface{}{map[string]interface{}{"name": "Commander", "slug": "commander"}},
+			},
+		},
+	}
+	assert.NoError(t, authorize(action, event))
+
+	// Single role field
+	event.Data["triggered_by"] = map[string]interface{}{"id": 50, "incident_role": "commander"}
+	assert.NoError(t, authorize(action, event))
+
+	// Wrong role
+	event.Data["triggered_by"] = map[string]interface{}{"id": 50, "incident_roles": []interface{}{"scribe"}}
+	err := authorize(action, event)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "required incident role")
+}
+
+func TestAuthorize_RequiresApproval(t *testing.T) {
+	action := &config.Action{
+		ID:   "drop_table",
+		Auth: config.Authorization{RequiresApproval: true},
+	}
+	user := map[string]interface{}{"email": "jane@example.com"}
+
+	event := api.Event{Data: map[string]interface{}{"triggered_by": user}}
+	err := authorize(action, event)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "requires approval")
+
+	event.Data["approved_by"] = map[string]interface{}{"email": "lead@example.com"}
+	assert.NoError(t, authorize(action, event))
+
+	event = api.Event{Data: map[string]interface{}{"triggered_by": user, "approved": true}}
+	assert.NoError(t, authorize(action, event))
+}
+
+func TestExecute_AuthorizationDenied(t *testing.T) {
+	var reportedResult reporter.ScriptResult
+	var reportedActionName string
+	reportCount := 0
+
+	mockRep := &mockReporter{
+		reportFunc: func(ctx context.Context, deliveryID, actionName, actionUUID string, result reporter.ScriptResult) error {
+			reportCount++
+			reportedActionName = actionName
+			reportedResult = result
+			return nil
+		},
+	}
+
+	// scriptRunner is nil: reaching execution would panic, proving the action never runs
+	executor := New([]config.Action{
+		{
+			ID:     "restart_service",
+			Name:   "Restart Service",
+			Type:   "script",
+			Script: "/opt/scripts/restart.sh",
+			Trigger: config.TriggerConfig{
+				EventType: "action.triggered",
+			},
+			Auth: config.Authorization{AllowedTeams: []string{"sre"}},
+		},
+	}, nil, nil, mockRep)
+
+	event := api.Event{
+		ID:   "delivery-auth",
+		Type: "action.triggered",
+		Action: &api.ActionMetadata{
+			ID:   "action-uuid",
+			Slug: "restart_service",
+		},
+		Data: map[string]interface{}{
+			"triggered_by": map[string]interface{}{
+				"email": "intern@example.com",
+				"teams": []interface{}{"marketing"},
+			},
+		},
+	}
+
+	assert.NotPanics(t, func() {
+		executor.Execute(context.Background(), event)
+	})
+
+	assert.Equal(t, 1, reportCount)
+	assert.Equal(t, "restart_service", reportedActionName)
+	assert.Equal(t, 1, reportedResult.ExitCode)
+	require.Error(t, reportedResult.Error)
+	assert.Contains(t, reportedResult.Error.Error(), "authorization denied")
+	assert.Contains(t, reportedResult.Stderr, "authorization denied")
+}
@@ -0,0 +1,121 @@
+package executor
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/rootly/edge-connector/internal/capture"
+	"github.com/rootly/edge-connector/internal/config"
+)
+
+// DefaultMaxOutputBytes is the per-stream cap used when no limit is configured
+const DefaultMaxOutputBytes = 10000
+
+// unsafeFileChars matches characters not allowed in overflow file names
+var unsafeFileChars = regexp.MustCompile(`[^A-Za-z0-9._-]`)
+
+// OutputLimits caps how much of each output stream (stdout, stderr, HTTP response body) is kept in memory
+type OutputLimits struct {
+	OverflowDir string // Full output of truncated streams is kept here when set
+	MaxBytes    int    // Per-stream cap; the head and tail are kept (0 uses DefaultMaxOutputBytes)
+}
+
+// maxBytes returns the cap for an action; the action's max_output_bytes overrides the global one
+func (l OutputLimits) maxBytes(action *config.Action) int {
+	if action.MaxOutput > 0 {
+		return action.MaxOutput
+	}
+	if l.MaxBytes > 0 {
+		return l.MaxBytes
+	}
+	return DefaultMaxOutputBytes
+}
+
+// newCapture creates the capture buffer for one output stream of a run
+// If an overflow directory is configured the full stream is also written to a file there;
+// the returned finish func closes that file and removes it unless the stream was truncated
+func (l OutputLimits) newCapture(action *config.Action, runID, stream string) (*capture.Buffer, func()) {
+	buf := capture.NewBuffer(l.maxBytes(action))
+	if l.OverflowDir == "" {
+		return buf, func() {}
+	}
+
+	if err := os.MkdirAll(l.OverflowDir, 0o750); err != nil {
+		log.WithError(err).WithField("dir", l.OverflowDir).Warn("Failed to create output overflow directory")
+		return buf, func() {}
+	}
+	name := fmt.Sprintf("%s-%s-%s.log",
+		unsafeFileChars.ReplaceAllString(runID, "_"), time.Now().UTC().Format("20060102T150405.000"), stream)
+	path := filepath.Join(l.OverflowDir, name)
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		log.WithError(err).WithField("path", path).Warn("Failed to create output overflow file")
+		return buf, func() {}
+	}
+	buf.SpillTo(file, path)
+
+	return buf, func() {
+		if err := file.Close(); err != nil {
+			log.WithError(err).WithField("path", path).Warn("Failed to close output overflow file")
+		}
+		if spillErr := buf.SpillErr(); spillErr != nil {
+			log.WithError(spillErr).WithField("path", path).Warn("Failed to write full output to overflow file")
+		}
+		if !buf.Truncated() {
+			removeScriptFile(path)
+			return
+		}
+		log.WithFields(log.Fields{
+			"path":        path,
+			"total_bytes": buf.Len(),
+		}).Info("Output truncated, full output kept in overflow file")
+	}
+}
+
+// fileTee copies a stream to a file as well; a failing file never interrupts the stream
+type fileTee struct {
+	w    io.Writer
+	file *os.File
+	err  error
+}
+
+func (t *fileTee) Write(p []byte) (int, error) {
+	if t.err == nil {
+		_, t.err = t.file.Write(p)
+	}
+	return t.w.Write(p)
+}
+
+// teeToFile also writes a stream to the file an action's stdout/stderr setting names
+// The returned finish func closes the file and logs a failed write
+func teeToFile(w io.Writer, path, stream string) (io.Writer, func()) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		log.WithError(err).WithField("path", path).Warnf("Failed to write %s to file", stream)
+		return w, func() {}
+	}
+
+	tee := &fileTee{w: w, file: file}
+	return tee, func() {
+		if err := file.Close(); err != nil && tee.err == nil {
+			tee.err = err
+		}
+		if tee.err != nil {
+			log.WithError(tee.err).WithField("path", path).Warnf("Failed to write %s to file", stream)
+		}
+	}
+}
+
+// captureRunID names overflow files after the delivery, falling back to the action
+func captureRunID(action *config.Action, deliveryID string) string {
+	if deliveryID != "" {
+		return deliveryID
+	}
+	return action.ID
+}
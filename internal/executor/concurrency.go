@@ -0,0 +1,197 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/rootly/edge-connector/internal/api"
+	"github.com/rootly/edge-connector/internal/config"
+	"github.com/rootly/edge-connector/internal/metrics"
+	"github.com/rootly/edge-connector/internal/reporter"
+)
+
+// Outcomes recorded for deliveries that hit a concurrency limit
+const (
+	concurrencyOutcomeQueued   = "queued"
+	concurrencyOutcomeRejected = "rejected"
+	concurrencyOutcomeDropped  = "dropped"
+)
+
+// concurrencyLimiter bounds how many deliveries run at once per action and mutex key
+type concurrencyLimiter struct {
+	slots map[string]*concurrencySlots
+	mu    sync.Mutex
+}
+
+// concurrencySlots is the semaphore for one key
+type concurrencySlots struct {
+	sem  chan struct{}
+	refs int // Holders and waiters; the entry is dropped when it reaches zero
+}
+
+func newConcurrencyLimiter() *concurrencyLimiter {
+	return &concurrencyLimiter{slots: make(map[string]*concurrencySlots)}
+}
+
+// tryAcquire takes a slot for key without waiting
+// Returns the release func, or nil if all slots are taken
+func (l *concurrencyLimiter) tryAcquire(key string, limit int) func() {
+	slots := l.ref(key, limit)
+	select {
+	case slots.sem <- struct{}{}:
+		return l.releaseFunc(key, slots)
+	default:
+		l.unref(key)
+		return nil
+	}
+}
+
+// acquire waits for a slot for key until ctx is done
+func (l *concurrencyLimiter) acquire(ctx context.Context, key string, limit int) (func(), error) {
+	slots := l.ref(key, limit)
+	select {
+	case slots.sem <- struct{}{}:
+		return l.releaseFunc(key, slots), nil
+	case <-ctx.Done():
+		l.unref(key)
+		return nil, context.Cause(ctx)
+	}
+}
+
+// ref returns the slots for key, creating them with the given limit if no delivery holds or waits for them
+// A changed limit (after a reload) applies once the deliveries using the old one are done
+func (l *concurrencyLimiter) ref(key string, limit int) *concurrencySlots {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	slots, ok := l.slots[key]
+	if !ok {
+		slots = &concurrencySlots{sem: make(chan struct{}, limit)}
+		l.slots[key] = slots
+	}
+	slots.refs++
+	return slots
+}
+
+func (l *concurrencyLimiter) unref(key string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	slots := l.slots[key]
+	slots.refs--
+	if slots.refs == 0 {
+		delete(l.slots, key)
+	}
+}
+
+func (l *concurrencyLimiter) releaseFunc(key string, slots *concurrencySlots) func() {
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			<-slots.sem
+			l.unref(key)
+		})
+	}
+}
+
+// acquireConcurrencySlot applies the action's concurrency limit to a delivery
+// Returns the func releasing the slot, or a result to report instead of running the action
+// when the delivery was rejected, dropped, or canceled while waiting
+func (e *Executor) acquireConcurrencySlot(ctx context.Context, action *config.Action, event api.Event) (func(), *reporter.ScriptResult) {
+	limit := action.ConcurrencyLimit()
+	if limit == 0 {
+		return func() {}, nil
+	}
+
+	mutexKey := e.renderMutexKey(action, event)
+	key := action.ID + "\x00" + mutexKey
+
+	if release := e.concurrency.tryAcquire(key, limit); release != nil {
+		return release, nil
+	}
+
+	logger := log.WithFields(log.Fields{
+		"action_id":   action.ID,
+		"delivery_id": event.ID,
+		"mutex_key":   mutexKey,
+		"concurrency": limit,
+		"policy":      action.OnConcurrencyLimit,
+	})
+	running := fmt.Sprintf("%d %s already running", limit, pluralize(limit, "delivery", "deliveries"))
+	if mutexKey != "" {
+		running += fmt.Sprintf(" for mutex key %q", mutexKey)
+	}
+
+	switch action.OnConcurrencyLimit {
+	case config.ConcurrencyReject:
+		metrics.RecordConcurrencyLimited(action.ID, concurrencyOutcomeRejected)
+		logger.Warn("Action at concurrency limit, rejecting delivery")
+		err := fmt.Errorf("concurrency limit reached: %s", running)
+		return nil, &reporter.ScriptResult{ExitCode: 1, Error: err, Stderr: err.Error()}
+
+	case config.ConcurrencyDrop:
+		metrics.RecordConcurrencyLimited(action.ID, concurrencyOutcomeDropped)
+		logger.Info("Action at concurrency limit, dropping delivery as duplicate")
+		return nil, &reporter.ScriptResult{SkipReason: "duplicate, " + running}
+	}
+
+	// Queue: wait for a running delivery to finish (the worker stays busy meanwhile)
+	metrics.RecordConcurrencyLimited(action.ID, concurrencyOutcomeQueued)
+	metrics.AddActionsWaiting(action.ID, 1)
+	logger.Info("Action at concurrency limit, waiting for a running delivery to finish")
+
+	start := time.Now()
+	release, err := e.concurrency.acquire(ctx, key, limit)
+	metrics.AddActionsWaiting(action.ID, -1)
+	metrics.RecordConcurrencyWait(action.ID, time.Since(start))
+	if err != nil {
+		logger.WithError(err).Warn("Canceled while waiting for a concurrency slot")
+		return nil, &reporter.ScriptResult{
+			ExitCode: -1,
+			Error:    fmt.Errorf("canceled while waiting for a concurrency slot: %w", err),
+		}
+	}
+	logger.WithField("waited", time.Since(start)).Debug("Acquired concurrency slot")
+	return release, nil
+}
+
+// renderMutexKey renders the action's mutex_key, with the resolved parameters as parameters
+// A key that renders empty is logged, since every such delivery shares one action-wide slot
+func (e *Executor) renderMutexKey(action *config.Action, event api.Event) string {
+	if action.MutexKey == "" {
+		return ""
+	}
+
+	data := make(map[string]interface{}, len(event.Data)+1)
+	maps.Copy(data, event.Data)
+	// Invalid parameters fail the delivery once it runs; until then the raw form values are used
+	if params, err := e.prepareParameters(action, event); err == nil {
+		data[formParametersKey] = params
+	}
+	keyEvent := event
+	keyEvent.Data = data
+
+	mutexKey := e.substituteTemplate(action.MutexKey, keyEvent)
+	if strings.TrimSpace(mutexKey) == "" {
+		log.WithFields(log.Fields{
+			"action_id":   action.ID,
+			"delivery_id": event.ID,
+			"mutex_key":   action.MutexKey,
+		}).Warn("Mutex key rendered empty, limiting the delivery with the action-wide slot")
+	}
+	return mutexKey
+}
+
+// pluralize picks the singular or plural form for n
+func pluralize(n int, singular, plural string) string {
+	if n == 1 {
+		return singular
+	}
+	return plural
+}
@@ -0,0 +1,232 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	logtest "github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rootly/edge-connector/internal/api"
+	"github.com/rootly/edge-connector/internal/config"
+	"github.com/rootly/edge-connector/internal/reporter"
+)
+
+func TestConcurrencyLimiter(t *testing.T) {
+	limiter := newConcurrencyLimiter()
+
+	first := limiter.tryAcquire("restart", 2)
+	second := limiter.tryAcquire("restart", 2)
+	require.NotNil(t, first)
+	require.NotNil(t, second)
+	assert.Nil(t, limiter.tryAcquire("restart", 2), "Limit should be enforced")
+
+	other := limiter.tryAcquire("migrate", 2)
+	require.NotNil(t, other, "Keys should be limited independently")
+
+	first()
+	first() // Releasing twice must not free a second slot
+	third := limiter.tryAcquire("restart", 2)
+	require.NotNil(t, third)
+	assert.Nil(t, limiter.tryAcquire("restart", 2))
+
+	second()
+	third()
+	other()
+	assert.Empty(t, limiter.slots, "Unused keys should be dropped")
+}
+
+func TestConcurrencyLimiter_AcquireWaits(t *testing.T) {
+	limiter := newConcurrencyLimiter()
+	release := limiter.tryAcquire("restart", 1)
+	require.NotNil(t, release)
+
+	acquired := make(chan func())
+	go func() {
+		next, err := limiter.acquire(context.Background(), "restart", 1)
+		assert.NoError(t, err)
+		acquired <- next
+	}()
+
+	select {
+	case <-acquired:
+		t.Fatal("acquire should wait while the slot is taken")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	release()
+	select {
+	case next := <-acquired:
+		next()
+	case <-time.After(time.Second):
+		t.Fatal("acquire should get the slot once it is released")
+	}
+
+	// Canceled waits give up with the cancellation cause
+	release = limiter.tryAcquire("restart", 1)
+	ctx, cancel := context.WithCancelCause(context.Background())
+	cause := errors.New("shutting down")
+	cancel(cause)
+	_, err := limiter.acquire(ctx, "restart", 1)
+	assert.ErrorIs(t, err, cause)
+	release()
+	assert.Empty(t, limiter.slots)
+}
+
+// blockingServer holds every request until unblock is called and signals when one arrives
+func blockingServer(t *testing.T) (server *httptest.Server, started <-chan struct{}, unblock func()) {
+	startedCh := make(chan struct{}, 10)
+	unblockCh := make(chan struct{})
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		startedCh <- struct{}{}
+		<-unblockCh
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+
+	var once sync.Once
+	return server, startedCh, func() { once.Do(func() { close(unblockCh) }) }
+}
+
+// runWhileBlocked starts a delivery that holds its slot, sends secondEvent while it runs, then lets both finish
+// Returns the reported results keyed by delivery ID
+func runWhileBlocked(t *testing.T, policy string, secondEvent api.Event) map[string]reporter.ScriptResult {
+	server, started, unblock := blockingServer(t)
+
+	var mu sync.Mutex
+	results := make(map[string]reporter.ScriptResult)
+	mockRep := &mockReporter{
+		reportFunc: func(ctx context.Context, deliveryID, actionName, actionUUID string, result reporter.ScriptResult) error {
+			mu.Lock()
+			defer mu.Unlock()
+			results[deliveryID] = result
+			return nil
+		},
+	}
+
+	action := config.Action{
+		ID:                 "restart_service",
+		Name:               "restart_service",
+		Type:               "http",
+		Timeout:            5,
+		HTTP:               &config.HTTPAction{URL: server.URL, Method: "POST"},
+		MutexKey:           "{{ service }}",
+		OnConcurrencyLimit: policy,
+		Trigger:            config.TriggerConfig{EventType: "alert.created"},
+	}
+	executor := New([]config.Action{action}, NewScriptRunner(nil, nil), NewHTTPExecutor(), mockRep)
+
+	first := api.Event{ID: "delivery-1", Type: "alert.created", Data: map[string]interface{}{"service": "api"}}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		executor.Execute(context.Background(), first)
+	}()
+	<-started
+
+	secondDone := make(chan struct{})
+	go func() {
+		defer close(secondDone)
+		executor.Execute(context.Background(), secondEvent)
+	}()
+
+	// Give the second delivery time to hit the limit before the first one finishes
+	select {
+	case <-secondDone:
+	case <-time.After(100 * time.Millisecond):
+	}
+	unblock()
+	<-done
+	<-secondDone
+
+	mu.Lock()
+	defer mu.Unlock()
+	return results
+}
+
+func TestExecute_ConcurrencyQueue(t *testing.T) {
+	second := api.Event{ID: "delivery-2", Type: "alert.created", Data: map[string]interface{}{"service": "api"}}
+	results := runWhileBlocked(t, config.ConcurrencyQueue, second)
+
+	require.Len(t, results, 2)
+	assert.False(t, results["delivery-1"].Failed())
+	assert.False(t, results["delivery-2"].Failed(), "Queued delivery should run once the slot is free")
+}
+
+func TestExecute_ConcurrencyReject(t *testing.T) {
+	second := api.Event{ID: "delivery-2", Type: "alert.created", Data: map[string]interface{}{"service": "api"}}
+	results := runWhileBlocked(t, config.ConcurrencyReject, second)
+
+	require.Len(t, results, 2)
+	assert.False(t, results["delivery-1"].Failed())
+	rejected := results["delivery-2"]
+	assert.True(t, rejected.Failed())
+	require.Error(t, rejected.Error)
+	assert.Equal(t, `concurrency limit reached: 1 delivery already running for mutex key "api"`, rejected.Error.Error())
+}
+
+func TestExecute_ConcurrencyDrop(t *testing.T) {
+	second := api.Event{ID: "delivery-2", Type: "alert.created", Data: map[string]interface{}{"service": "api"}}
+	results := runWhileBlocked(t, config.ConcurrencyDrop, second)
+
+	require.Len(t, results, 2)
+	dropped := results["delivery-2"]
+	assert.False(t, dropped.Failed())
+	assert.Contains(t, dropped.SkipReason, "duplicate", "Dropped deliveries are reported as skipped duplicates")
+}
+
+func TestExecute_ConcurrencyDifferentMutexKeys(t *testing.T) {
+	// A different key is not limited, so the second delivery runs (and blocks) alongside the first
+	second := api.Event{ID: "delivery-2", Type: "alert.created", Data: map[string]interface{}{"service": "db"}}
+	results := runWhileBlocked(t, config.ConcurrencyReject, second)
+
+	require.Len(t, results, 2)
+	assert.False(t, results["delivery-1"].Failed())
+	assert.False(t, results["delivery-2"].Failed())
+}
+
+func TestRenderMutexKey_UsesResolvedParameters(t *testing.T) {
+	executor := New(nil, NewScriptRunner(nil, nil), NewHTTPExecutor(), &mockReporter{})
+	action := &config.Action{
+		ID:         "restart_service",
+		MutexKey:   "{{ parameters.service_name }}",
+		Parameters: map[string]string{"service_name": "{{ alert.labels.service }}"},
+		Trigger:    config.TriggerConfig{EventType: "alert.created"},
+	}
+	event := api.Event{
+		ID:   "delivery-1",
+		Type: "alert.created",
+		Data: map[string]interface{}{
+			"alert": map[string]interface{}{"labels": map[string]interface{}{"service": "api"}},
+		},
+	}
+
+	assert.Equal(t, "api", executor.renderMutexKey(action, event),
+		"Parameters templated over the event should be resolved before rendering the key")
+	assert.NotContains(t, event.Data, "parameters", "The event itself should not be modified")
+}
+
+func TestRenderMutexKey_WarnsWhenEmpty(t *testing.T) {
+	hook := logtest.NewGlobal()
+	defer hook.Reset()
+
+	executor := New(nil, NewScriptRunner(nil, nil), NewHTTPExecutor(), &mockReporter{})
+	action := &config.Action{
+		ID:       "restart_service",
+		MutexKey: "{{ parameters.service_name }}",
+		Trigger:  config.TriggerConfig{EventType: "alert.created"},
+	}
+	event := api.Event{ID: "delivery-1", Type: "alert.created", Data: map[string]interface{}{}}
+
+	assert.Empty(t, executor.renderMutexKey(action, event))
+	require.NotNil(t, hook.LastEntry())
+	assert.Equal(t, log.WarnLevel, hook.LastEntry().Level)
+	assert.Equal(t, "delivery-1", hook.LastEntry().Data["delivery_id"])
+}
@@ -0,0 +1,64 @@
+package executor
+
+import (
+	"os"
+	"path"
+	"regexp"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// connectorEnvPrefix marks variables the connector itself reads (REC_API_KEY, REC_API_URL, REC_LOG_LEVEL, ...)
+// Inherited ones are never passed to scripts, even when inherit_env matches them
+const connectorEnvPrefix = "REC_"
+
+// templateEnvPattern finds {{ env.NAME }} references in a template
+var templateEnvPattern = regexp.MustCompile(`\{\{\s*env\.([A-Z_][A-Z0-9_]*)\s*[}|]`)
+
+// baseEnvVars are passed from the connector's environment to every script
+var baseEnvVars = []string{"PATH", "HOME", "USER", "LOGNAME", "SHELL", "TMPDIR", "TZ", "LANG", "LANGUAGE", "LC_*"}
+
+// inheritedEnv returns the part of the connector's environment a script may see:
+// the base variables plus those matching the action's inherit_env names or patterns
+func inheritedEnv(inherit []string) []string {
+	environ := os.Environ()
+	env := make([]string, 0, len(baseEnvVars)+len(inherit))
+	for _, entry := range environ {
+		name, _, ok := strings.Cut(entry, "=")
+		if !ok || strings.HasPrefix(name, connectorEnvPrefix) {
+			continue
+		}
+		if matchesEnvName(baseEnvVars, name) || matchesEnvName(inherit, name) {
+			env = append(env, entry)
+		}
+	}
+	return env
+}
+
+// matchesEnvName reports whether name equals or matches (path.Match) one of the patterns
+func matchesEnvName(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if matched, err := path.Match(pattern, name); err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
+
+// templateEnv returns the connector environment variables a template references as {{ env.NAME }}
+// Connector variables are refused so a template cannot copy REC_API_KEY into a parameter or request
+func templateEnv(tmplStr string) map[string]string {
+	envVars := make(map[string]string)
+	for _, match := range templateEnvPattern.FindAllStringSubmatch(tmplStr, -1) {
+		name := match[1]
+		if strings.HasPrefix(name, connectorEnvPrefix) {
+			log.WithField("variable", name).Warn("Templates cannot read connector environment variables, leaving it empty")
+			continue
+		}
+		if value := os.Getenv(name); value != "" {
+			envVars[name] = value
+		}
+	}
+	return envVars
+}
@@ -3,9 +3,10 @@ package executor
 import (
 	"context"
 	"fmt"
-	"os"
-	"regexp"
+	"slices"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/osteele/liquid"
@@ -28,6 +29,7 @@ const (
 	fieldStatusCode      = "status_code"
 	actionTypeHTTP       = "http"
 	actionTypeScript     = "script"
+	actionTypePipeline   = "pipeline"
 	eventActionTriggered = "action.triggered"
 	methodPOST           = "POST"
 	interpreterPython3   = "python3"
@@ -38,12 +40,23 @@ type Reporter interface {
 	Report(ctx context.Context, deliveryID, actionName, actionUUID string, result reporter.ScriptResult) error
 }
 
+// Journal records deliveries in flight so they can be recovered after a crash
+type Journal interface {
+	Started(event api.Event, action *config.Action)
+	Finished(deliveryID string)
+}
+
 // Executor coordinates action execution
 type Executor struct {
 	scriptRunner *ScriptRunner
 	httpExecutor *HTTPExecutor
 	reporter     Reporter
-	actions      []config.Action
+	journal      Journal
+	concurrency  *concurrencyLimiter
+	rateLimits   *actionRateLimiter
+	lease        *leaseHeartbeat
+	actions      []config.Action // Replaced as a whole on reload, never mutated in place (guarded by mu)
+	mu           sync.RWMutex
 }
 
 // New creates a new executor
@@ -53,13 +66,49 @@ func New(actions []config.Action, scriptRunner *ScriptRunner, httpExecutor *HTTP
 		scriptRunner: scriptRunner,
 		httpExecutor: httpExecutor,
 		reporter:     rep,
+		concurrency:  newConcurrencyLimiter(),
+		rateLimits:   newActionRateLimiter(),
 	}
 }
 
+// SetActions atomically replaces the configured actions (used by hot reload)
+// Executions already in progress keep using the action they matched
+func (e *Executor) SetActions(actions []config.Action) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.actions = actions
+}
+
+// Actions returns the currently configured actions
+func (e *Executor) Actions() []config.Action {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.actions
+}
+
+// SetJournal sets the journal used to track deliveries in flight
+func (e *Executor) SetJournal(journal Journal) {
+	e.journal = journal
+}
+
 // Execute processes an event and executes matching actions
 func (e *Executor) Execute(ctx context.Context, event api.Event) {
+	// Every path below ends by reporting a result, so the delivery no longer needs recovery
+	if e.journal != nil {
+		defer e.journal.Finished(event.ID)
+	}
+	// The lease is usually held since the delivery was claimed
+	defer e.ReleaseLease(event.ID)
+
+	// Results must still be reported when execution is canceled by shutdown
+	reportCtx := context.WithoutCancel(ctx)
+
 	// Find matching action for this event
-	action := e.findMatchingAction(event)
+	action, skippedBy := e.selectAction(event)
+	if action == nil && len(skippedBy) > 0 {
+		e.reportSkipped(reportCtx, event, skippedBy)
+		return
+	}
 	if action == nil {
 		logFields := log.Fields{
 			"delivery_id": event.ID,
@@ -100,12 +149,66 @@ func (e *Executor) Execute(ctx context.Context, event api.Event) {
 		if event.Action != nil {
 			actionUUID = event.Action.ID
 		}
-		if err := e.reporter.Report(ctx, event.ID, reportedActionName, actionUUID, result); err != nil {
+		if err := e.reporter.Report(reportCtx, event.ID, reportedActionName, actionUUID, result); err != nil {
 			log.WithError(err).Error("Failed to report no-action failure")
 		}
 		return
 	}
 
+	// Enforce authorization rules before running anything
+	if err := authorize(action, event); err != nil {
+		log.WithFields(log.Fields{
+			fieldActionName: action.Name,
+			"action_id":     action.ID,
+			"delivery_id":   event.ID,
+			"event_id":      event.EventID,
+		}).WithError(err).Warn("Action authorization failed")
+
+		result := reporter.ScriptResult{
+			ExitCode:   1,
+			DurationMs: 0,
+			Error:      err,
+			Stderr:     err.Error(),
+		}
+		actionUUID := ""
+		if event.Action != nil {
+			actionUUID = event.Action.ID
+		}
+		if err := e.reporter.Report(reportCtx, event.ID, action.ID, actionUUID, result); err != nil {
+			log.WithError(err).Error("Failed to report authorization failure")
+		}
+		return
+	}
+
+	// Fail deliveries over the action's rate limit so they are reported rather than dropped
+	if limited := e.checkRateLimit(action, event); limited != nil {
+		actionUUID := ""
+		if event.Action != nil {
+			actionUUID = event.Action.ID
+		}
+		if err := e.reporter.Report(reportCtx, event.ID, action.ID, actionUUID, *limited); err != nil {
+			log.WithError(err).Error("Failed to report rate limited delivery")
+		}
+		return
+	}
+
+	// Keep the delivery leased while it waits for a slot and while the action runs
+	e.HoldLease(event)
+
+	// Enforce the action's concurrency limit (queue, reject or drop)
+	release, limited := e.acquireConcurrencySlot(ctx, action, event)
+	if limited != nil {
+		actionUUID := ""
+		if event.Action != nil {
+			actionUUID = event.Action.ID
+		}
+		if err := e.reporter.Report(reportCtx, event.ID, action.ID, actionUUID, *limited); err != nil {
+			log.WithError(err).Error("Failed to report concurrency limited delivery")
+		}
+		return
+	}
+	defer release()
+
 	log.WithFields(log.Fields{
 		fieldActionName: action.Name,
 		"action_type":   action.Type,
@@ -114,6 +217,10 @@ func (e *Executor) Execute(ctx context.Context, event api.Event) {
 		"event_type":    event.Type,
 	}).Info("Executing action for event")
 
+	if e.journal != nil {
+		e.journal.Started(event, action)
+	}
+
 	// Track events currently running
 	if metrics.EventsRunning != nil {
 		metrics.EventsRunning.Inc()
@@ -123,21 +230,21 @@ func (e *Executor) Execute(ctx context.Context, event api.Event) {
 	start := time.Now()
 	var result reporter.ScriptResult
 
-	// Prepare parameters with template substitution (for both script and HTTP actions)
-	params := e.prepareParameters(action, event)
-
 	// Execute based on action type
-	if action.Type == actionTypeHTTP {
-		result = e.httpExecutor.Execute(ctx, action, event, params)
+	if action.Type == actionTypePipeline {
+		result = e.runPipeline(ctx, action, event)
 	} else {
-		// Script action
-		result = e.scriptRunner.Run(ctx, action, params)
+		result = e.runAction(ctx, action, event)
 	}
 
+	// Let the next delivery waiting for a slot start while this result is reported
+	e.ReleaseLease(event.ID)
+	release()
+
 	// Record execution metrics
 	duration := time.Since(start)
 	status := "completed"
-	if result.Error != nil || result.ExitCode != 0 {
+	if result.Failed() {
 		status = "failed"
 	}
 	metrics.RecordActionExecution(action.Name, action.Type, status, duration)
@@ -149,36 +256,149 @@ func (e *Executor) Execute(ctx context.Context, event api.Event) {
 	if event.Action != nil {
 		actionUUID = event.Action.ID
 	}
-	if err := e.reporter.Report(ctx, event.ID, action.ID, actionUUID, result); err != nil {
+	if err := e.reporter.Report(reportCtx, event.ID, action.ID, actionUUID, result); err != nil {
 		log.WithError(err).Error("Failed to report execution result")
 	}
 }
 
-// findMatchingAction finds the first action that matches the event
-func (e *Executor) findMatchingAction(event api.Event) *config.Action {
-	for i := range e.actions {
-		if e.matchesAction(event, &e.actions[i]) {
-			return &e.actions[i]
+// runAction runs a single script or HTTP action, retrying it if the action has a retry policy
+func (e *Executor) runAction(ctx context.Context, action *config.Action, event api.Event) reporter.ScriptResult {
+	// Prepare parameters with template substitution (for both script and HTTP actions)
+	params, err := e.prepareParameters(action, event)
+	if err != nil {
+		log.WithError(err).WithFields(log.Fields{
+			"action_id":   action.ID,
+			"delivery_id": event.ID,
+		}).Warn("Invalid action parameters, not running action")
+		return invalidParametersResult(err)
+	}
+
+	run := func() reporter.ScriptResult {
+		if action.Type == actionTypeHTTP {
+			return e.httpExecutor.Execute(ctx, action, event, params)
 		}
+		// Script action
+		return e.scriptRunner.Run(ctx, action, event, params)
 	}
-	return nil
+
+	if action.Retry != nil {
+		return runWithRetry(ctx, action, event, run)
+	}
+	return run()
 }
 
-// matchesAction checks if an event matches an action's trigger conditions
-func (e *Executor) matchesAction(event api.Event, action *config.Action) bool {
-	// Get all event types from trigger (handles both single and multiple)
-	eventTypes := action.Trigger.GetEventTypes()
-
-	// Check if any of the configured event types match
-	matched := false
-	for _, eventType := range eventTypes {
-		if eventType == event.Type {
-			matched = true
-			break
+// ReportNotStarted reports a delivery that was claimed but never executed (e.g. still queued at shutdown)
+func (e *Executor) ReportNotStarted(ctx context.Context, event api.Event, reason error) {
+	if e.journal != nil {
+		defer e.journal.Finished(event.ID)
+	}
+	defer e.ReleaseLease(event.ID)
+
+	actionName := "none"
+	if action := e.findMatchingAction(event); action != nil {
+		actionName = action.ID
+	}
+	actionUUID := ""
+	if event.Action != nil {
+		actionUUID = event.Action.ID
+	}
+
+	log.WithFields(log.Fields{
+		fieldActionName: actionName,
+		"delivery_id":   event.ID,
+		"event_id":      event.EventID,
+	}).WithError(reason).Warn("Reporting delivery that was never executed")
+
+	result := reporter.ScriptResult{
+		ExitCode: 1,
+		Error:    reason,
+		Stderr:   reason.Error(),
+	}
+	if err := e.reporter.Report(ctx, event.ID, actionName, actionUUID, result); err != nil {
+		log.WithError(err).Error("Failed to report unexecuted delivery")
+	}
+}
+
+// reportSkipped reports a delivery whose candidate actions all had a when condition that did not match
+func (e *Executor) reportSkipped(ctx context.Context, event api.Event, skippedBy []string) {
+	log.WithFields(log.Fields{
+		"delivery_id": event.ID,
+		"event_id":    event.EventID,
+		"event_type":  event.Type,
+		"actions":     skippedBy,
+	}).Info("Event did not match any when condition, skipping")
+
+	result := reporter.ScriptResult{
+		SkipReason: "when condition not met for " + strings.Join(skippedBy, ", "),
+	}
+	actionUUID := ""
+	if event.Action != nil {
+		actionUUID = event.Action.ID
+	}
+	if err := e.reporter.Report(ctx, event.ID, skippedBy[0], actionUUID, result); err != nil {
+		log.WithError(err).Error("Failed to report skipped delivery")
+	}
+}
+
+// findMatchingAction finds the first action that matches the event, including its when condition
+func (e *Executor) findMatchingAction(event api.Event) *config.Action {
+	action, _ := e.selectAction(event)
+	return action
+}
+
+// selectAction finds the most specific action whose trigger and when condition match the event
+// Exact event types are tried before wildcard patterns, and more specific patterns before broader ones.
+// Actions whose trigger matches but whose when condition does not are skipped in favor of the next
+// candidate; their IDs are returned so a delivery nothing ran for can be reported as skipped
+func (e *Executor) selectAction(event api.Event) (*config.Action, []string) {
+	var candidates []*config.Action
+
+	actions := e.Actions()
+	for i := range actions {
+		if e.matchesAction(event, &actions[i]) {
+			candidates = append(candidates, &actions[i])
 		}
 	}
+	sort.SliceStable(candidates, func(a, b int) bool {
+		return candidates[a].Trigger.MatchEventType(event.Type) > candidates[b].Trigger.MatchEventType(event.Type)
+	})
+
+	var skippedBy []string
+	for _, action := range candidates {
+		if e.whenMatches(action, event) {
+			return action, nil
+		}
+		skippedBy = append(skippedBy, action.ID)
+	}
+
+	return nil, skippedBy
+}
+
+// whenMatches evaluates an action's when condition against the event
+// Actions without a condition always match; conditions that fail to evaluate never match
+func (e *Executor) whenMatches(action *config.Action, event api.Event) bool {
+	if action.When == "" {
+		return true
+	}
+
+	tmplStr := config.WhenTemplate(action.When)
+	engine := liquid.NewEngine()
+	result, err := engine.ParseAndRenderString(tmplStr, e.prepareTemplateContext(tmplStr, event))
+	if err != nil {
+		log.WithError(err).WithFields(log.Fields{
+			"action_id": action.ID,
+			"when":      action.When,
+		}).Warn("Failed to evaluate when condition, treating it as not matched")
+		return false
+	}
 
-	if !matched {
+	return result == "true"
+}
+
+// matchesAction checks if an event matches an action's trigger conditions
+func (e *Executor) matchesAction(event api.Event, action *config.Action) bool {
+	// Check if any of the configured event types (or wildcard patterns) match
+	if action.Trigger.MatchEventType(event.Type) < 0 {
 		return false
 	}
 
@@ -189,9 +409,13 @@ func (e *Executor) matchesAction(event api.Event, action *config.Action) bool {
 	// Matches against trigger.action_name (or defaults to action.ID if not specified)
 
 	// Get expected action name from trigger (defaults to action.ID if not specified)
+	// Wildcard triggers are registered once per event type they match, under that event type
 	expectedActionName := action.Trigger.ActionName
 	if expectedActionName == "" {
 		expectedActionName = action.ID
+		if !slices.Contains(action.Trigger.GetEventTypes(), event.Type) {
+			expectedActionName = event.Type
+		}
 	}
 
 	// Strategy 1: Check Action metadata object (preferred)
@@ -227,44 +451,6 @@ func (e *Executor) matchesAction(event api.Event, action *config.Action) bool {
 	return true
 }
 
-// prepareParameters prepares parameters for script execution with template substitution
-// User-provided values from event.data take precedence over config-defined templates
-func (e *Executor) prepareParameters(action *config.Action, event api.Event) map[string]string {
-	params := make(map[string]string)
-
-	// First, apply template substitution for all configured parameters
-	for key, template := range action.Parameters {
-		value := e.substituteTemplate(template, event)
-		params[key] = value
-	}
-
-	// Then, override with direct user-provided values from event.data
-	// This allows user input to take precedence over config defaults
-	if event.Data != nil {
-		for key, value := range event.Data {
-			// Only override if the key exists in our parameters OR parameter_definitions
-			if _, existsInParams := action.Parameters[key]; existsInParams {
-				// Convert value to string
-				if strValue, ok := value.(string); ok {
-					params[key] = strValue
-				}
-			} else {
-				// Check if it's in parameter_definitions (for callable actions)
-				for _, paramDef := range action.ParameterDefinitions {
-					if paramDef.Name == key {
-						if strValue, ok := value.(string); ok {
-							params[key] = strValue
-						}
-						break
-					}
-				}
-			}
-		}
-	}
-
-	return params
-}
-
 // substituteTemplate performs template substitution using Liquid template engine
 // Supports:
 // - Simple fields: {{ field }}
@@ -313,20 +499,8 @@ func (e *Executor) prepareTemplateContext(tmplStr string, event api.Event) map[s
 	// Add special "event" namespace for backward compatibility
 	context["event"] = event.Data
 
-	// Add environment variables under "env" namespace
-	// Extract env vars used in templates (scan for env.* pattern)
-	envVars := make(map[string]string)
-	re := regexp.MustCompile(`\{\{\s*env\.([A-Z_][A-Z0-9_]*)\s*[}|]`)
-	matches := re.FindAllStringSubmatch(tmplStr, -1)
-	for _, match := range matches {
-		if len(match) > 1 {
-			envVarName := match[1]
-			if value := os.Getenv(envVarName); value != "" {
-				envVars[envVarName] = value
-			}
-		}
-	}
-	if len(envVars) > 0 {
+	// Add environment variables used in the template under "env" namespace
+	if envVars := templateEnv(tmplStr); len(envVars) > 0 {
 		context["env"] = envVars
 	}
 
@@ -3,8 +3,10 @@ package executor
 import (
 	"context"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"os"
 	"path/filepath"
 	"runtime"
@@ -230,7 +232,8 @@ func TestPrepareParameters_UserInputPrecedence(t *testing.T) {
 		},
 	}
 
-	params := executor.prepareParameters(action, event)
+	params, err := executor.prepareParameters(action, event)
+	require.NoError(t, err)
 
 	// User input should win
 	assert.Equal(t, "eu-west-1", params["region"], "User input should override hardcoded value")
@@ -254,7 +257,8 @@ func TestPrepareParameters_HardcodedWhenNoUserInput(t *testing.T) {
 		},
 	}
 
-	params := executor.prepareParameters(action, event)
+	params, err := executor.prepareParameters(action, event)
+	require.NoError(t, err)
 
 	// Hardcoded values should be used when user provides nothing
 	assert.Equal(t, "us-east-1", params["region"])
@@ -282,7 +286,8 @@ func TestPrepareParameters_MixedUserAndHardcoded(t *testing.T) {
 		},
 	}
 
-	params := executor.prepareParameters(action, event)
+	params, err := executor.prepareParameters(action, event)
+	require.NoError(t, err)
 
 	// Should have both user-provided and hardcoded
 	assert.Equal(t, "api", params["service_name"])
@@ -313,12 +318,13 @@ func TestPrepareParameters_NonStringValues(t *testing.T) {
 		},
 	}
 
-	params := executor.prepareParameters(action, event)
+	params, err := executor.prepareParameters(action, event)
+	require.NoError(t, err)
 
-	// Non-string values should be skipped (only strings are supported)
-	// Only the template substitution result matters
-	assert.Contains(t, params, "count")
-	assert.Contains(t, params, "enabled")
+	// Non-string values are coerced to their declared types
+	assert.Equal(t, float64(123), params["count"])
+	assert.Equal(t, true, params["enabled"])
+	assert.NotContains(t, params, "name", "Undeclared event data should not become a parameter")
 }
 
 func TestPrepareParameters_EmptyData(t *testing.T) {
@@ -336,7 +342,8 @@ func TestPrepareParameters_EmptyData(t *testing.T) {
 		Data: map[string]interface{}{}, // Empty data
 	}
 
-	params := executor.prepareParameters(action, event)
+	params, err := executor.prepareParameters(action, event)
+	require.NoError(t, err)
 
 	// Hardcoded values should be preserved
 	assert.Equal(t, "hardcoded", params["default_value"])
@@ -360,7 +367,8 @@ func TestPrepareParameters_NilData(t *testing.T) {
 
 	// Should not panic
 	assert.NotPanics(t, func() {
-		params := executor.prepareParameters(action, event)
+		params, err := executor.prepareParameters(action, event)
+		require.NoError(t, err)
 		assert.Equal(t, "value", params["default"])
 	})
 }
@@ -384,13 +392,212 @@ func TestPrepareParameters_ParameterDefinitionsOnly(t *testing.T) {
 		},
 	}
 
-	params := executor.prepareParameters(action, event)
+	params, err := executor.prepareParameters(action, event)
+	require.NoError(t, err)
 
 	// User-provided values should be included via parameter_definitions
 	assert.Equal(t, "from_user", params["user_input"])
 	assert.Equal(t, "test description", params["description"])
 }
 
+func TestPrepareParameters_FormValuesKeepTypes(t *testing.T) {
+	executor := &Executor{}
+
+	action := &config.Action{
+		Name: "scale_service",
+		ParameterDefinitions: []config.ParameterDefinition{
+			{Name: "replicas", Type: "number"},
+			{Name: "dry_run", Type: "boolean"},
+			{Name: "region", Type: "list", Options: []string{"us", "eu"}},
+		},
+		Parameters: map[string]string{
+			"replicas": config.ParameterTemplate("replicas"),
+			"dry_run":  config.ParameterTemplate("dry_run"),
+			"region":   config.ParameterTemplate("region"),
+		},
+	}
+
+	event := api.Event{
+		Data: map[string]interface{}{
+			"parameters": map[string]interface{}{
+				"replicas": float64(3),
+				"dry_run":  "true", // Form values may arrive as text
+				"region":   "eu",
+			},
+		},
+	}
+
+	params, err := executor.prepareParameters(action, event)
+	require.NoError(t, err)
+
+	assert.Equal(t, float64(3), params["replicas"])
+	assert.Equal(t, true, params["dry_run"])
+	assert.Equal(t, "eu", params["region"])
+}
+
+func TestPrepareParameters_DefaultsAndRequired(t *testing.T) {
+	executor := &Executor{}
+
+	action := &config.Action{
+		Name: "scale_service",
+		ParameterDefinitions: []config.ParameterDefinition{
+			{Name: "replicas", Type: "number", Default: 2},
+			{Name: "service", Type: "string", Required: true},
+			{Name: "note", Type: "string"},
+		},
+		Parameters: map[string]string{
+			"replicas": config.ParameterTemplate("replicas"),
+			"service":  config.ParameterTemplate("service"),
+			"note":     config.ParameterTemplate("note"),
+		},
+	}
+
+	event := api.Event{
+		Data: map[string]interface{}{
+			"parameters": map[string]interface{}{"service": "api"},
+		},
+	}
+
+	params, err := executor.prepareParameters(action, event)
+	require.NoError(t, err)
+
+	assert.Equal(t, float64(2), params["replicas"], "Default should be applied and coerced")
+	assert.Equal(t, "api", params["service"])
+	assert.NotContains(t, params, "note", "Optional parameters without a value should be omitted")
+
+	// Blank required field
+	event.Data["parameters"] = map[string]interface{}{"service": ""}
+	_, err = executor.prepareParameters(action, event)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "service: is required")
+}
+
+func TestPrepareParameters_InvalidTypes(t *testing.T) {
+	executor := &Executor{}
+
+	action := &config.Action{
+		Name: "scale_service",
+		ParameterDefinitions: []config.ParameterDefinition{
+			{Name: "replicas", Type: "number"},
+			{Name: "dry_run", Type: "boolean"},
+		},
+	}
+
+	event := api.Event{
+		Data: map[string]interface{}{
+			"replicas": "three",
+			"dry_run":  []interface{}{"yes"},
+		},
+	}
+
+	_, err := executor.prepareParameters(action, event)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `replicas: expected a number, got "three"`)
+	assert.Contains(t, err.Error(), "dry_run: expected a boolean, got a list")
+}
+
+func TestPrepareParameters_ListValueNotInOptions(t *testing.T) {
+	executor := &Executor{}
+
+	action := &config.Action{
+		Name: "restart_service",
+		ParameterDefinitions: []config.ParameterDefinition{
+			{Name: "service", Type: "list", Options: []string{"api", "worker"}, Required: true},
+			{Name: "replicas", Type: "number", Required: true},
+		},
+		Parameters: map[string]string{
+			"service":  config.ParameterTemplate("service"),
+			"replicas": config.ParameterTemplate("replicas"),
+		},
+	}
+
+	event := api.Event{
+		Data: map[string]interface{}{
+			"parameters": map[string]interface{}{"service": "database"},
+		},
+	}
+
+	_, err := executor.prepareParameters(action, event)
+
+	var validationErr *parameterValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, []string{
+		`service: must be one of api, worker, got "database"`,
+		"replicas: is required",
+	}, validationErr.fieldMessages(), "Every rejected field should be reported")
+
+	// Allowed option passes
+	event.Data["parameters"] = map[string]interface{}{"service": "worker", "replicas": "2"}
+	params, err := executor.prepareParameters(action, event)
+	require.NoError(t, err)
+	assert.Equal(t, "worker", params["service"])
+	assert.Equal(t, float64(2), params["replicas"])
+}
+
+func TestPrepareParameters_CustomTemplateCoerced(t *testing.T) {
+	executor := &Executor{}
+
+	action := &config.Action{
+		Name: "scale_service",
+		ParameterDefinitions: []config.ParameterDefinition{
+			{Name: "replicas", Type: "number"},
+		},
+		Parameters: map[string]string{
+			"replicas": "{{ scaling.replicas }}",
+		},
+	}
+
+	event := api.Event{
+		Data: map[string]interface{}{
+			"scaling": map[string]interface{}{"replicas": 5},
+		},
+	}
+
+	params, err := executor.prepareParameters(action, event)
+	require.NoError(t, err)
+	assert.Equal(t, float64(5), params["replicas"])
+}
+
+func TestExecute_InvalidParametersReportsFailure(t *testing.T) {
+	var reportedResult reporter.ScriptResult
+	mockRep := &mockReporter{
+		reportFunc: func(ctx context.Context, deliveryID, actionName, actionUUID string, result reporter.ScriptResult) error {
+			reportedResult = result
+			return nil
+		},
+	}
+
+	action := config.Action{
+		ID:   "scale_service",
+		Type: "http",
+		HTTP: &config.HTTPAction{URL: "http://127.0.0.1:0", Method: "POST"},
+		ParameterDefinitions: []config.ParameterDefinition{
+			{Name: "replicas", Type: "number", Required: true},
+		},
+		Trigger: config.TriggerConfig{EventType: "alert.created"},
+	}
+
+	executor := New([]config.Action{action}, nil, NewHTTPExecutor(), mockRep)
+	executor.Execute(context.Background(), api.Event{
+		ID:   "delivery-1",
+		Type: "alert.created",
+		Data: map[string]interface{}{},
+	})
+
+	require.Error(t, reportedResult.Error)
+	assert.Equal(t, "invalid parameters: replicas: is required", reportedResult.Error.Error())
+	assert.Equal(t, 1, reportedResult.ExitCode)
+	assert.Equal(t, "replicas: is required\n", reportedResult.Stderr)
+}
+
+func TestParameterString(t *testing.T) {
+	assert.Equal(t, "text", parameterString("text"))
+	assert.Equal(t, "3", parameterString(float64(3)))
+	assert.Equal(t, "2.5", parameterString(2.5))
+	assert.Equal(t, "false", parameterString(false))
+	assert.Equal(t, `["a","b"]`, parameterString([]interface{}{"a", "b"}))
+}
+
 func TestExecute_NoMatchingAction(t *testing.T) {
 	// Mock reporter to capture the failure report
 	reportCalled := false
@@ -677,6 +884,151 @@ func TestFindMatchingAction(t *testing.T) {
 	assert.Nil(t, action)
 }
 
+func TestWhenMatches(t *testing.T) {
+	executor := &Executor{}
+	event := api.Event{
+		Type: "alert.created",
+		Data: map[string]interface{}{
+			"labels":   map[string]interface{}{"severity": "critical"},
+			"services": []interface{}{"api", "payments"},
+		},
+	}
+
+	tests := []struct {
+		when     string
+		expected bool
+	}{
+		{"", true},
+		{`labels.severity == "critical"`, true},
+		{`labels.severity == "low"`, false},
+		{`services contains "payments"`, true},
+		{`services contains "search"`, false},
+		{`labels.severity == "critical" and services contains "api"`, true},
+		{`missing.field == "x"`, false},
+	}
+
+	for _, tt := range tests {
+		action := &config.Action{ID: "test", When: tt.when}
+		assert.Equal(t, tt.expected, executor.whenMatches(action, event), "when: %s", tt.when)
+	}
+}
+
+func TestSelectAction_FallsThroughWhenConditionFails(t *testing.T) {
+	executor := &Executor{
+		actions: []config.Action{
+			{ID: "page_oncall", When: `labels.severity == "critical"`, Trigger: config.TriggerConfig{EventType: "alert.created"}},
+			{ID: "log_alert", Trigger: config.TriggerConfig{EventType: "alert.created"}},
+		},
+	}
+
+	critical := api.Event{Type: "alert.created", Data: map[string]interface{}{"labels": map[string]interface{}{"severity": "critical"}}}
+	action, skippedBy := executor.selectAction(critical)
+	require.NotNil(t, action)
+	assert.Equal(t, "page_oncall", action.ID)
+	assert.Empty(t, skippedBy)
+
+	low := api.Event{Type: "alert.created", Data: map[string]interface{}{"labels": map[string]interface{}{"severity": "low"}}}
+	action, skippedBy = executor.selectAction(low)
+	require.NotNil(t, action)
+	assert.Equal(t, "log_alert", action.ID, "Non-matching when should fall through to the next candidate")
+	assert.Empty(t, skippedBy)
+}
+
+func TestSelectAction_ExactMatchBeatsWildcard(t *testing.T) {
+	executor := &Executor{
+		actions: []config.Action{
+			{ID: "*", Trigger: config.TriggerConfig{EventType: "*"}},
+			{ID: "alert.*", Trigger: config.TriggerConfig{EventType: "alert.*"}},
+			{ID: "alert.created", Trigger: config.TriggerConfig{EventType: "alert.created"}},
+		},
+	}
+
+	tests := []struct {
+		eventType string
+		expected  string
+	}{
+		{"alert.created", "alert.created"},
+		{"alert.updated", "alert.*"},
+		{"incident.created", "*"},
+	}
+
+	for _, tt := range tests {
+		action, _ := executor.selectAction(api.Event{Type: tt.eventType})
+		require.NotNil(t, action, tt.eventType)
+		assert.Equal(t, tt.expected, action.ID, tt.eventType)
+
+		// Deliveries carry the slug the pattern was registered under, which is the event type
+		action, _ = executor.selectAction(api.Event{Type: tt.eventType, Action: &api.ActionMetadata{Slug: tt.eventType}})
+		require.NotNil(t, action, tt.eventType)
+		assert.Equal(t, tt.expected, action.ID, tt.eventType)
+	}
+
+	// Automatic wildcards never pick up callable events
+	action, _ := executor.selectAction(api.Event{Type: "action.triggered", Action: &api.ActionMetadata{Slug: "*"}})
+	assert.Nil(t, action)
+}
+
+func TestSelectAction_WildcardFallbackAfterWhen(t *testing.T) {
+	executor := &Executor{
+		actions: []config.Action{
+			{ID: "*", Trigger: config.TriggerConfig{EventType: "*"}},
+			{ID: "alert.created", When: `labels.severity == "critical"`, Trigger: config.TriggerConfig{EventType: "alert.created"}},
+		},
+	}
+
+	action, _ := executor.selectAction(api.Event{Type: "alert.created", Data: map[string]interface{}{}})
+	require.NotNil(t, action)
+	assert.Equal(t, "*", action.ID, "Wildcard action should catch events the exact action's when filtered out")
+}
+
+func TestExecute_WhenConditionNotMetReportsSkipped(t *testing.T) {
+	var reportedResult reporter.ScriptResult
+	var reportedActionName string
+	mockRep := &mockReporter{
+		reportFunc: func(ctx context.Context, deliveryID, actionName, actionUUID string, result reporter.ScriptResult) error {
+			reportedResult = result
+			reportedActionName = actionName
+			return nil
+		},
+	}
+
+	executor := New([]config.Action{
+		{
+			ID:      "alert.created",
+			Type:    "http",
+			When:    `labels.severity == "critical"`,
+			HTTP:    &config.HTTPAction{URL: "http://127.0.0.1:1/unreachable", Method: "POST"},
+			Trigger: config.TriggerConfig{EventType: "alert.created"},
+		},
+	}, nil, NewHTTPExecutor(), mockRep)
+
+	executor.Execute(context.Background(), api.Event{
+		ID:   "delivery-1",
+		Type: "alert.created",
+		Data: map[string]interface{}{"labels": map[string]interface{}{"severity": "low"}},
+	})
+
+	assert.False(t, reportedResult.Failed())
+	assert.Equal(t, "alert.created", reportedActionName)
+	assert.Contains(t, reportedResult.SkipReason, "when condition not met")
+}
+
+func TestSetActions(t *testing.T) {
+	executor := New([]config.Action{
+		{Name: "alert_action", Trigger: config.TriggerConfig{EventType: "alert.created"}},
+	}, nil, nil, &mockReporter{})
+
+	executor.SetActions([]config.Action{
+		{Name: "incident_action", Trigger: config.TriggerConfig{EventType: "incident.created"}},
+	})
+
+	assert.Len(t, executor.Actions(), 1)
+	assert.Nil(t, executor.findMatchingAction(api.Event{Type: "alert.created"}), "Removed action should no longer match")
+	action := executor.findMatchingAction(api.Event{Type: "incident.created"})
+	require.NotNil(t, action)
+	assert.Equal(t, "incident_action", action.Name)
+}
+
 func TestSubstituteTemplate(t *testing.T) {
 	executor := &Executor{}
 
@@ -950,6 +1302,20 @@ func TestSubstituteTemplate_EnvironmentVariables(t *testing.T) {
 	}
 }
 
+func TestSubstituteTemplate_RefusesConnectorEnv(t *testing.T) {
+	t.Setenv("REC_API_KEY", "rec_secret")
+	t.Setenv("TEST_ENV_VAR", "env_value")
+
+	template := "{{ env.REC_API_KEY }}|{{ env.TEST_ENV_VAR }}"
+
+	executor := &Executor{}
+	assert.Equal(t, "|env_value", executor.substituteTemplate(template, api.Event{}))
+
+	rendered, err := NewHTTPExecutor().renderTemplate(template, api.Event{})
+	require.NoError(t, err)
+	assert.Equal(t, "|env_value", rendered)
+}
+
 func TestSubstituteTemplate_ComplexTemplates(t *testing.T) {
 	executor := &Executor{}
 
@@ -1150,6 +1516,150 @@ func TestExecute_ScriptActionFailure(t *testing.T) {
 	assert.NotNil(t, reportedResult.Error)
 }
 
+func TestExecute_ScriptRetry(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Skipping shell script test on Windows")
+	}
+
+	// Fails with exit code 75 until the third run
+	tmpDir := t.TempDir()
+	counterPath := filepath.Join(tmpDir, "runs")
+	scriptPath := filepath.Join(tmpDir, "flaky.sh")
+	scriptContent := `#!/bin/sh
+echo run >> "` + counterPath + `"
+runs=$(wc -l < "` + counterPath + `")
+echo "attempt $runs" >&2
+[ "$runs" -ge 3 ] || exit 75
+echo "ok"
+`
+	err := os.WriteFile(scriptPath, []byte(scriptContent), 0755)
+	require.NoError(t, err)
+
+	var reportedResult reporter.ScriptResult
+	mockRep := &mockReporter{
+		reportFunc: func(ctx context.Context, deliveryID, actionName, actionUUID string, result reporter.ScriptResult) error {
+			reportedResult = result
+			return nil
+		},
+	}
+
+	action := config.Action{
+		ID:      "flaky_script",
+		Type:    "script",
+		Script:  scriptPath,
+		Timeout: 5,
+		Retry: &config.RetryPolicy{
+			MaxAttempts: 3,
+			Backoff:     "linear",
+			DelayMs:     10,
+			MaxDelayMs:  100,
+			ExitCodes:   []int{75},
+		},
+		Trigger: config.TriggerConfig{EventType: "test.event"},
+	}
+	executor := New([]config.Action{action}, NewScriptRunner([]string{tmpDir}, nil), NewHTTPExecutor(), mockRep)
+
+	event := api.Event{ID: "delivery-1", Type: "test.event", Data: map[string]interface{}{}}
+	executor.Execute(context.Background(), event)
+
+	assert.False(t, reportedResult.Failed())
+	assert.Equal(t, "ok\n", reportedResult.Stdout)
+	require.Len(t, reportedResult.Attempts, 3, "Every attempt should be reported")
+	assert.True(t, reportedResult.Attempts[0].Failed)
+	assert.Equal(t, 75, reportedResult.Attempts[0].ExitCode)
+	assert.Equal(t, "attempt 1\n", reportedResult.Attempts[0].Stderr)
+	assert.True(t, reportedResult.Attempts[1].Failed)
+	assert.False(t, reportedResult.Attempts[2].Failed)
+
+	// Exit codes not in the list fail right away
+	require.NoError(t, os.WriteFile(scriptPath, []byte("#!/bin/sh\nexit 2\n"), 0755))
+	reportedResult = reporter.ScriptResult{}
+	executor.Execute(context.Background(), event)
+
+	assert.Equal(t, 2, reportedResult.ExitCode)
+	assert.Empty(t, reportedResult.Attempts, "A single attempt has no history")
+}
+
+func TestExecute_HTTPRetry(t *testing.T) {
+	var requests int
+	status := http.StatusServiceUnavailable
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests < 2 {
+			w.WriteHeader(status)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	var reportedResult reporter.ScriptResult
+	mockRep := &mockReporter{
+		reportFunc: func(ctx context.Context, deliveryID, actionName, actionUUID string, result reporter.ScriptResult) error {
+			reportedResult = result
+			return nil
+		},
+	}
+
+	action := config.Action{
+		ID:      "webhook",
+		Type:    "http",
+		Timeout: 5,
+		HTTP:    &config.HTTPAction{URL: server.URL, Method: "POST"},
+		Retry:   &config.RetryPolicy{MaxAttempts: 3, Backoff: "exponential", DelayMs: 5, MaxDelayMs: 50},
+		Trigger: config.TriggerConfig{EventType: "alert.created"},
+	}
+	executor := New([]config.Action{action}, NewScriptRunner(nil, nil), NewHTTPExecutor(), mockRep)
+	event := api.Event{ID: "delivery-1", Type: "alert.created", Data: map[string]interface{}{}}
+
+	executor.Execute(context.Background(), event)
+
+	assert.Equal(t, 2, requests)
+	assert.Equal(t, http.StatusOK, reportedResult.ExitCode)
+	require.Len(t, reportedResult.Attempts, 2)
+	assert.Equal(t, http.StatusServiceUnavailable, reportedResult.Attempts[0].ExitCode)
+
+	// 4xx responses are not retried by default
+	requests = 0
+	status = http.StatusBadRequest
+	executor.Execute(context.Background(), event)
+
+	assert.Equal(t, 1, requests)
+	assert.Equal(t, http.StatusBadRequest, reportedResult.ExitCode)
+}
+
+func TestIsRetryable(t *testing.T) {
+	script := &config.Action{Type: "script", Retry: &config.RetryPolicy{MaxAttempts: 2}}
+	scriptWithCodes := &config.Action{Type: "script", Retry: &config.RetryPolicy{MaxAttempts: 2, ExitCodes: []int{75}}}
+	httpAction := &config.Action{Type: "http", Retry: &config.RetryPolicy{MaxAttempts: 2}}
+	httpWithStatuses := &config.Action{Type: "http", Retry: &config.RetryPolicy{MaxAttempts: 2, HTTPStatuses: []int{429}}}
+	requestErr := fmt.Errorf("HTTP request failed: %w", &url.Error{Op: "Post", URL: "http://localhost", Err: errors.New("connection refused")})
+
+	tests := []struct {
+		name     string
+		action   *config.Action
+		result   reporter.ScriptResult
+		expected bool
+	}{
+		{"script non-zero exit", script, reporter.ScriptResult{ExitCode: 1}, true},
+		{"script timeout", script, reporter.ScriptResult{ExitCode: -1}, true},
+		{"script listed exit code", scriptWithCodes, reporter.ScriptResult{ExitCode: 75}, true},
+		{"script unlisted exit code", scriptWithCodes, reporter.ScriptResult{ExitCode: 1}, false},
+		{"http 5xx", httpAction, reporter.ScriptResult{ExitCode: 502}, true},
+		{"http 4xx", httpAction, reporter.ScriptResult{ExitCode: 404}, false},
+		{"http request error", httpAction, reporter.ScriptResult{ExitCode: 1, Error: requestErr}, true},
+		{"http other error", httpAction, reporter.ScriptResult{ExitCode: 1, Error: errors.New("failed to render body")}, false},
+		{"http listed status", httpWithStatuses, reporter.ScriptResult{ExitCode: 429}, true},
+		{"http unlisted 5xx", httpWithStatuses, reporter.ScriptResult{ExitCode: 500}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, isRetryable(tt.action, tt.result))
+		})
+	}
+}
+
 func TestExecute_HTTPActionSuccess(t *testing.T) {
 	// Create test HTTP server
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -1399,3 +1909,126 @@ func TestGetFieldValue_NonMapInPath(t *testing.T) {
 	result := exec.getFieldValue(data, "level1.level2")
 	assert.Nil(t, result)
 }
+
+// mockJournal records journal calls in order
+type mockJournal struct {
+	calls []string
+}
+
+func (m *mockJournal) Started(event api.Event, action *config.Action) {
+	m.calls = append(m.calls, "started:"+event.ID+":"+action.ID)
+}
+
+func (m *mockJournal) Finished(deliveryID string) {
+	m.calls = append(m.calls, "finished:"+deliveryID)
+}
+
+func TestExecute_JournalTracksExecution(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Skipping shell script test on Windows")
+	}
+
+	scriptPath, allowedDir, err := getFixturePath("test.sh")
+	require.NoError(t, err)
+
+	journal := &mockJournal{}
+	mockRep := &mockReporter{
+		reportFunc: func(ctx context.Context, deliveryID, actionName, actionUUID string, result reporter.ScriptResult) error {
+			journal.calls = append(journal.calls, "reported:"+deliveryID)
+			return nil
+		},
+	}
+
+	executor := New([]config.Action{
+		{
+			ID:      "test_script_id",
+			Type:    "script",
+			Script:  scriptPath,
+			Timeout: 5,
+			Trigger: config.TriggerConfig{
+				EventType: "test.event",
+			},
+		},
+	}, NewScriptRunner([]string{allowedDir}, nil), NewHTTPExecutor(), mockRep)
+	executor.SetJournal(journal)
+
+	executor.Execute(context.Background(), api.Event{ID: "delivery-1", Type: "test.event"})
+
+	assert.Equal(t, []string{
+		"started:delivery-1:test_script_id",
+		"reported:delivery-1",
+		"finished:delivery-1",
+	}, journal.calls)
+}
+
+func TestExecute_JournalFinishesUnmatchedEvent(t *testing.T) {
+	journal := &mockJournal{}
+	executor := New([]config.Action{}, nil, nil, &mockReporter{})
+	executor.SetJournal(journal)
+
+	executor.Execute(context.Background(), api.Event{ID: "delivery-1", Type: "unknown.event"})
+
+	assert.Equal(t, []string{"finished:delivery-1"}, journal.calls, "Unmatched events never start but must leave the journal")
+}
+
+func TestExecute_ReportsWithLiveContextAfterCancellation(t *testing.T) {
+	var reportCtxErr error
+	reported := false
+	mockRep := &mockReporter{
+		reportFunc: func(ctx context.Context, deliveryID, actionName, actionUUID string, result reporter.ScriptResult) error {
+			reported = true
+			reportCtxErr = ctx.Err()
+			return nil
+		},
+	}
+
+	executor := New([]config.Action{}, nil, nil, mockRep)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	executor.Execute(ctx, api.Event{ID: "delivery-1", Type: "unknown.event"})
+
+	assert.True(t, reported)
+	assert.NoError(t, reportCtxErr, "Results must still be reported after the execution context is canceled")
+}
+
+func TestReportNotStarted(t *testing.T) {
+	var reportedDeliveryID, reportedActionName, reportedUUID string
+	var reportedResult reporter.ScriptResult
+	mockRep := &mockReporter{
+		reportFunc: func(ctx context.Context, deliveryID, actionName, actionUUID string, result reporter.ScriptResult) error {
+			reportedDeliveryID = deliveryID
+			reportedActionName = actionName
+			reportedUUID = actionUUID
+			reportedResult = result
+			return nil
+		},
+	}
+	journal := &mockJournal{}
+
+	executor := New([]config.Action{
+		{
+			ID:   "restart_service",
+			Type: "script",
+			Trigger: config.TriggerConfig{
+				EventType: "action.triggered",
+			},
+		},
+	}, nil, nil, mockRep)
+	executor.SetJournal(journal)
+
+	reason := errors.New("not started: connector shut down before the action ran")
+	executor.ReportNotStarted(context.Background(), api.Event{
+		ID:     "delivery-1",
+		Type:   "action.triggered",
+		Action: &api.ActionMetadata{ID: "uuid-1", Slug: "restart_service"},
+	}, reason)
+
+	assert.Equal(t, "delivery-1", reportedDeliveryID)
+	assert.Equal(t, "restart_service", reportedActionName)
+	assert.Equal(t, "uuid-1", reportedUUID)
+	assert.Equal(t, 1, reportedResult.ExitCode)
+	assert.Equal(t, reason, reportedResult.Error)
+	assert.Contains(t, reportedResult.Stderr, "connector shut down")
+	assert.Equal(t, []string{"finished:delivery-1"}, journal.calls)
+}
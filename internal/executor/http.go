@@ -4,12 +4,11 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
-	"os"
-	"regexp"
 	"strings"
 	"time"
 
@@ -24,7 +23,9 @@ import (
 
 // HTTPExecutor handles HTTP action execution
 type HTTPExecutor struct {
-	client *http.Client
+	client       *http.Client
+	targets      *hostGuards // Per-host rate limits and circuit breakers (nil means none)
+	outputLimits OutputLimits
 }
 
 // HTTPResponse represents an HTTP response
@@ -44,8 +45,19 @@ func NewHTTPExecutor() *HTTPExecutor {
 	}
 }
 
+// SetOutputLimits sets the cap on captured response bodies
+func (h *HTTPExecutor) SetOutputLimits(limits OutputLimits) {
+	h.outputLimits = limits
+}
+
+// SetTargetLimits sets the rate limits and circuit breakers applied per destination host
+func (h *HTTPExecutor) SetTargetLimits(cfg *config.HTTPTargetsConfig) {
+	h.targets = newHostGuards(cfg)
+}
+
 // Execute executes an HTTP action
-func (h *HTTPExecutor) Execute(ctx context.Context, action *config.Action, event api.Event, params map[string]string) reporter.ScriptResult {
+// Without a body template, params are sent as a JSON object keeping their types
+func (h *HTTPExecutor) Execute(ctx context.Context, action *config.Action, event api.Event, params map[string]interface{}) reporter.ScriptResult {
 	start := time.Now()
 
 	if action.HTTP == nil {
@@ -172,14 +184,40 @@ func (h *HTTPExecutor) Execute(ctx context.Context, action *config.Action, event
 		"body_preview": truncateString(bodyContent, 100),
 	}).Info("Executing HTTP request")
 
+	// Fail fast while the host's circuit breaker is open or it is over its rate limit
+	guard := h.targets.get(parsedURL.Hostname())
+	if guard != nil {
+		if reason, err := guard.allow(time.Now()); err != nil {
+			metrics.RecordActionThrottled(action.ID, reason)
+			log.WithError(err).WithField("host", parsedURL.Hostname()).Warn("HTTP request not sent")
+			return reporter.ScriptResult{
+				ExitCode:   1,
+				Stderr:     err.Error(),
+				DurationMs: time.Since(start).Milliseconds(),
+				Error:      err,
+			}
+		}
+	}
+
 	// Execute HTTP request
 	log.Debug("Sending HTTP request...")
 	resp, err := h.client.Do(req)
 	duration := time.Since(start)
+	if guard != nil {
+		statusCode := 0
+		if resp != nil {
+			statusCode = resp.StatusCode
+		}
+		guard.done(statusCode, err, ctx.Err() != nil)
+	}
 
 	if err != nil {
 		log.WithError(err).Error("HTTP request failed")
 		metrics.RecordHTTPRequest(method, 0, duration)
+		// Surface the cancellation reason (e.g. shutdown deadline) instead of a bare "context canceled"
+		if cause := context.Cause(ctx); cause != nil && !errors.Is(cause, ctx.Err()) {
+			err = fmt.Errorf("%w: %w", err, cause)
+		}
 		return reporter.ScriptResult{
 			ExitCode:   1,
 			Stderr:     err.Error(),
@@ -197,8 +235,11 @@ func (h *HTTPExecutor) Execute(ctx context.Context, action *config.Action, event
 	// Record HTTP metrics
 	metrics.RecordHTTPRequest(method, resp.StatusCode, duration)
 
-	// Read response body
-	respBody, err := io.ReadAll(resp.Body)
+	// Read response body, keeping its head and tail within the output limit
+	body, finishBody := h.outputLimits.newCapture(action, captureRunID(action, event.ID), "response")
+	defer finishBody()
+	_, err = io.Copy(body, resp.Body)
+	respBody := body.String()
 	if err != nil {
 		log.WithError(err).Error("Failed to read HTTP response body")
 		return reporter.ScriptResult{
@@ -209,7 +250,7 @@ func (h *HTTPExecutor) Execute(ctx context.Context, action *config.Action, event
 	}
 
 	log.WithFields(log.Fields{
-		"body_length":   len(respBody),
+		"body_length":   body.Len(),
 		fieldStatusCode: resp.StatusCode,
 	}).Debug("HTTP response body read successfully")
 
@@ -217,7 +258,7 @@ func (h *HTTPExecutor) Execute(ctx context.Context, action *config.Action, event
 	httpResp := HTTPResponse{
 		StatusCode: resp.StatusCode,
 		Headers:    make(map[string]string),
-		Body:       string(respBody),
+		Body:       respBody,
 		Duration:   time.Since(start).Milliseconds(),
 	}
 
@@ -229,7 +270,7 @@ func (h *HTTPExecutor) Execute(ctx context.Context, action *config.Action, event
 	}
 
 	// Log response body at TRACE level
-	log.WithField("response_body", string(respBody)).Trace("HTTP response body")
+	log.WithField("response_body", respBody).Trace("HTTP response body")
 
 	// Marshal HTTP response as JSON for stdout
 	respJSON, err := json.MarshalIndent(httpResp, "", "  ")
@@ -241,6 +282,8 @@ func (h *HTTPExecutor) Execute(ctx context.Context, action *config.Action, event
 		Stdout:     string(respJSON),
 		DurationMs: time.Since(start).Milliseconds(),
 		ExitCode:   resp.StatusCode,
+		Truncated:  body.Truncated(),
+		MaxOutput:  body.Limit(),
 	}
 
 	// Consider 2xx status codes as success
@@ -252,10 +295,10 @@ func (h *HTTPExecutor) Execute(ctx context.Context, action *config.Action, event
 		}).Info("HTTP request completed successfully")
 		log.Debug("Returning success result to executor")
 	} else {
-		result.Error = fmt.Errorf("HTTP %d: %s", resp.StatusCode, string(respBody))
+		result.Error = fmt.Errorf("HTTP %d: %s", resp.StatusCode, respBody)
 		log.WithFields(log.Fields{
 			fieldStatusCode: resp.StatusCode,
-			"error":         string(respBody),
+			"error":         respBody,
 		}).Error("HTTP request failed")
 		log.Debug("Returning error result to executor")
 	}
@@ -312,20 +355,8 @@ func (h *HTTPExecutor) prepareTemplateContext(tmplStr string, event api.Event) m
 	// Add special "event" namespace for backward compatibility
 	context["event"] = event.Data
 
-	// Add environment variables under "env" namespace
-	// Extract env vars used in templates (scan for env.* pattern)
-	envVars := make(map[string]string)
-	re := regexp.MustCompile(`\{\{\s*env\.([A-Z_][A-Z0-9_]*)\s*[}|]`)
-	matches := re.FindAllStringSubmatch(tmplStr, -1)
-	for _, match := range matches {
-		if len(match) > 1 {
-			envVarName := match[1]
-			if value := os.Getenv(envVarName); value != "" {
-				envVars[envVarName] = value
-			}
-		}
-	}
-	if len(envVars) > 0 {
+	// Add environment variables used in the template under "env" namespace
+	if envVars := templateEnv(tmplStr); len(envVars) > 0 {
 		context["env"] = envVars
 	}
 
@@ -9,6 +9,7 @@ import (
 	"net/http/httptest"
 	"net/url"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
@@ -45,7 +46,7 @@ func TestHTTPExecutor_AutoBuildBody(t *testing.T) {
 		Data: map[string]interface{}{},
 	}
 
-	params := map[string]string{
+	params := map[string]interface{}{
 		"message":  "Hello",
 		"severity": "info",
 		"count":    "42",
@@ -60,6 +61,66 @@ func TestHTTPExecutor_AutoBuildBody(t *testing.T) {
 	assert.Equal(t, "42", receivedBody["count"])
 }
 
+func TestHTTPExecutor_AutoBuildBodyTypedParams(t *testing.T) {
+	var receivedBody map[string]interface{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		json.Unmarshal(body, &receivedBody)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	executor := NewHTTPExecutor()
+	action := &config.Action{
+		Name:    "test_http",
+		Type:    "http",
+		HTTP:    &config.HTTPAction{URL: server.URL, Method: "POST"},
+		Timeout: 10,
+	}
+
+	params := map[string]interface{}{
+		"replicas": float64(3),
+		"dry_run":  true,
+		"service":  "api",
+	}
+
+	result := executor.Execute(context.Background(), action, api.Event{}, params)
+
+	assert.Equal(t, 200, result.ExitCode)
+	assert.Equal(t, float64(3), receivedBody["replicas"], "Numbers should be sent as JSON numbers")
+	assert.Equal(t, true, receivedBody["dry_run"], "Booleans should be sent as JSON booleans")
+	assert.Equal(t, "api", receivedBody["service"])
+}
+
+func TestHTTPExecutor_ResponseBodyTruncated(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(strings.Repeat("x", 50000)))
+	}))
+	defer server.Close()
+
+	executor := NewHTTPExecutor()
+	executor.SetOutputLimits(OutputLimits{MaxBytes: 1000})
+	action := &config.Action{
+		Name: "test_http",
+		Type: "http",
+		HTTP: &config.HTTPAction{
+			URL:    server.URL,
+			Method: "GET",
+		},
+		Timeout: 10,
+	}
+
+	result := executor.Execute(context.Background(), action, api.Event{}, nil)
+
+	assert.Equal(t, 200, result.ExitCode)
+	assert.Nil(t, result.Error)
+	assert.True(t, result.Truncated)
+	assert.Less(t, len(result.Stdout), 2000)
+	assert.Contains(t, result.Stdout, "bytes truncated")
+}
+
 func TestHTTPExecutor_CustomBodyTemplate(t *testing.T) {
 	var receivedBody map[string]interface{}
 
@@ -88,7 +149,7 @@ func TestHTTPExecutor_CustomBodyTemplate(t *testing.T) {
 		},
 	}
 
-	params := map[string]string{
+	params := map[string]interface{}{
 		"ignored": "value", // Should be ignored since custom body is used
 	}
 
@@ -122,7 +183,7 @@ func TestHTTPExecutor_EmptyParamsNoBody(t *testing.T) {
 	}
 
 	event := api.Event{Data: map[string]interface{}{}}
-	params := map[string]string{} // Empty params
+	params := map[string]interface{}{} // Empty params
 
 	result := executor.Execute(context.Background(), action, event, params)
 
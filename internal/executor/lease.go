@@ -0,0 +1,127 @@
+package executor
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/rootly/edge-connector/internal/api"
+	"github.com/rootly/edge-connector/internal/metrics"
+)
+
+// leaseExtensionTimeout bounds a single lease extension so a slow API cannot delay the next one
+const leaseExtensionTimeout = 10 * time.Second
+
+// LeaseExtender renews the visibility timeout of a delivery that is still running
+type LeaseExtender interface {
+	ExtendLease(ctx context.Context, deliveryID string, visibilityTimeoutSec int) error
+}
+
+// leaseHeartbeat renews each claimed delivery's lease at a fixed interval
+type leaseHeartbeat struct {
+	extender             LeaseExtender
+	interval             time.Duration
+	visibilityTimeoutSec int
+
+	mu   sync.Mutex
+	held map[string]func() // Stops the heartbeat of each held delivery, by delivery ID
+}
+
+// SetLeaseExtender renews the lease of every delivery each interval from the time it is held
+// (usually when it is claimed) until its action finishes, so the backend does not hand a queued
+// or long-running delivery out again
+// Each renewal asks for visibilityTimeoutSec seconds from the time it is sent
+func (e *Executor) SetLeaseExtender(extender LeaseExtender, interval time.Duration, visibilityTimeoutSec int) {
+	e.lease = &leaseHeartbeat{
+		extender:             extender,
+		interval:             interval,
+		visibilityTimeoutSec: visibilityTimeoutSec,
+		held:                 make(map[string]func()),
+	}
+}
+
+// HoldLease starts renewing a claimed delivery's lease, so it stays leased while it waits in the worker queue
+// Execute and ReportNotStarted release it once the delivery is reported; holding a held lease does nothing
+func (e *Executor) HoldLease(event api.Event) {
+	if e.lease == nil || e.lease.interval <= 0 {
+		return
+	}
+
+	e.lease.mu.Lock()
+	defer e.lease.mu.Unlock()
+	if _, ok := e.lease.held[event.ID]; ok {
+		return
+	}
+	e.lease.held[event.ID] = e.startHeartbeat(context.Background(), event)
+}
+
+// ReleaseLease stops renewing a delivery's lease; releasing a lease that is not held does nothing
+func (e *Executor) ReleaseLease(deliveryID string) {
+	if e.lease == nil {
+		return
+	}
+
+	e.lease.mu.Lock()
+	stop, ok := e.lease.held[deliveryID]
+	delete(e.lease.held, deliveryID)
+	e.lease.mu.Unlock()
+	if ok {
+		stop()
+	}
+}
+
+// startHeartbeat renews the delivery's lease until the returned stop is called or ctx is done
+// stop cancels an extension in flight, waits for the heartbeat to exit and is safe to call more than once
+func (e *Executor) startHeartbeat(ctx context.Context, event api.Event) (stop func()) {
+	if e.lease == nil || e.lease.interval <= 0 {
+		return func() {}
+	}
+
+	heartbeatCtx, cancel := context.WithCancel(ctx)
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		ticker := time.NewTicker(e.lease.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-heartbeatCtx.Done():
+				return
+			case <-ticker.C:
+				e.lease.extend(heartbeatCtx, event.ID)
+			}
+		}
+	}()
+
+	return sync.OnceFunc(func() {
+		cancel()
+		wg.Wait()
+	})
+}
+
+// extend sends one lease extension and records its outcome
+func (h *leaseHeartbeat) extend(ctx context.Context, deliveryID string) {
+	extendCtx, cancel := context.WithTimeout(ctx, leaseExtensionTimeout)
+	defer cancel()
+
+	err := h.extender.ExtendLease(extendCtx, deliveryID, h.visibilityTimeoutSec)
+	if err == nil {
+		metrics.RecordLeaseHeartbeat("success")
+		log.WithField("delivery_id", deliveryID).Debug("Extended delivery lease")
+		return
+	}
+	if ctx.Err() != nil {
+		return // Stopped because the action finished
+	}
+
+	metrics.RecordLeaseHeartbeat("error")
+	log.WithFields(log.Fields{
+		"delivery_id":        deliveryID,
+		"visibility_timeout": h.visibilityTimeoutSec,
+	}).WithError(err).Warn("Failed to extend delivery lease, it may be redelivered")
+}
@@ -0,0 +1,159 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rootly/edge-connector/internal/api"
+	"github.com/rootly/edge-connector/internal/config"
+	"github.com/rootly/edge-connector/internal/reporter"
+)
+
+// mockLeaseExtender records lease extensions and signals each one
+type mockLeaseExtender struct {
+	extended chan string
+	err      error
+	mu       sync.Mutex
+	calls    int
+}
+
+func (m *mockLeaseExtender) ExtendLease(ctx context.Context, deliveryID string, visibilityTimeoutSec int) error {
+	m.mu.Lock()
+	m.calls++
+	m.mu.Unlock()
+	select {
+	case m.extended <- deliveryID:
+	default:
+	}
+	return m.err
+}
+
+func (m *mockLeaseExtender) count() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.calls
+}
+
+func TestExecute_ExtendsLeaseWhileRunning(t *testing.T) {
+	server, started, unblock := blockingServer(t)
+
+	var reported bool
+	mockRep := &mockReporter{
+		reportFunc: func(ctx context.Context, deliveryID, actionName, actionUUID string, result reporter.ScriptResult) error {
+			reported = true
+			return nil
+		},
+	}
+
+	action := config.Action{
+		ID:      "long_running",
+		Type:    "http",
+		Timeout: 5,
+		HTTP:    &config.HTTPAction{URL: server.URL, Method: "POST"},
+		Trigger: config.TriggerConfig{EventType: "alert.created"},
+	}
+	executor := New([]config.Action{action}, NewScriptRunner(nil, nil), NewHTTPExecutor(), mockRep)
+
+	extender := &mockLeaseExtender{extended: make(chan string, 10), err: errors.New("lease lost")}
+	executor.SetLeaseExtender(extender, 10*time.Millisecond, 30)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		executor.Execute(context.Background(), api.Event{ID: "delivery-1", Type: "alert.created"})
+	}()
+	<-started
+
+	// Failed extensions keep the heartbeat going
+	for range 2 {
+		select {
+		case deliveryID := <-extender.extended:
+			assert.Equal(t, "delivery-1", deliveryID)
+		case <-time.After(time.Second):
+			t.Fatal("Lease should be extended while the action runs")
+		}
+	}
+
+	unblock()
+	<-done
+	assert.True(t, reported)
+
+	// The heartbeat stops with the action
+	calls := extender.count()
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, calls, extender.count())
+}
+
+func TestHoldLease_RenewsQueuedDeliveryUntilExecuted(t *testing.T) {
+	executor := New(nil, NewScriptRunner(nil, nil), NewHTTPExecutor(), &mockReporter{})
+	extender := &mockLeaseExtender{extended: make(chan string, 10)}
+	executor.SetLeaseExtender(extender, 10*time.Millisecond, 30)
+
+	event := api.Event{ID: "delivery-1", Type: "alert.created"}
+	executor.HoldLease(event)
+	executor.HoldLease(event) // Already held
+
+	// Still queued: no worker has picked the delivery up yet
+	select {
+	case deliveryID := <-extender.extended:
+		assert.Equal(t, "delivery-1", deliveryID)
+	case <-time.After(time.Second):
+		t.Fatal("Lease should be extended while the delivery waits in the queue")
+	}
+
+	// No action matches, so the delivery is reported as failed and its lease released
+	executor.Execute(context.Background(), event)
+
+	executor.lease.mu.Lock()
+	assert.Empty(t, executor.lease.held)
+	executor.lease.mu.Unlock()
+	calls := extender.count()
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, calls, extender.count(), "The heartbeat should stop once the delivery is reported")
+}
+
+func TestReleaseLease_StopsHeartbeat(t *testing.T) {
+	executor := New(nil, NewScriptRunner(nil, nil), NewHTTPExecutor(), &mockReporter{})
+	extender := &mockLeaseExtender{extended: make(chan string, 10)}
+	executor.SetLeaseExtender(extender, 10*time.Millisecond, 30)
+
+	executor.HoldLease(api.Event{ID: "delivery-1"})
+	executor.ReleaseLease("delivery-1")
+	executor.ReleaseLease("delivery-1") // Not held anymore
+	executor.ReleaseLease("delivery-2") // Never held
+
+	time.Sleep(50 * time.Millisecond)
+	assert.Zero(t, extender.count())
+}
+
+func TestStartHeartbeat_WithoutExtender(t *testing.T) {
+	executor := New(nil, NewScriptRunner(nil, nil), NewHTTPExecutor(), &mockReporter{})
+	stop := executor.startHeartbeat(context.Background(), api.Event{ID: "delivery-1"})
+	require.NotNil(t, stop)
+	stop()
+}
+
+func TestStartHeartbeat_StopIsIdempotent(t *testing.T) {
+	executor := New(nil, NewScriptRunner(nil, nil), NewHTTPExecutor(), &mockReporter{})
+	extender := &mockLeaseExtender{extended: make(chan string, 1)}
+	executor.SetLeaseExtender(extender, time.Millisecond, 30)
+
+	stop := executor.startHeartbeat(context.Background(), api.Event{ID: "delivery-1"})
+	select {
+	case <-extender.extended:
+	case <-time.After(time.Second):
+		t.Fatal("Lease should be extended")
+	}
+	stop()
+	stop()
+
+	calls := extender.count()
+	time.Sleep(20 * time.Millisecond)
+	assert.Equal(t, calls, extender.count())
+}
@@ -0,0 +1,70 @@
+package executor
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/rootly/edge-connector/internal/reporter"
+)
+
+// maxScriptOutputBytes bounds how much of REC_OUTPUT_FILE is read
+const maxScriptOutputBytes = 1 << 20
+
+// createOutputFile creates the empty file a script may write its structured result to
+func createOutputFile() (string, error) {
+	file, err := os.CreateTemp("", "rec-output-*.json")
+	if err != nil {
+		return "", fmt.Errorf("failed to create script output file: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		removeScriptFile(file.Name())
+		return "", fmt.Errorf("failed to create script output file: %w", err)
+	}
+	return file.Name(), nil
+}
+
+// readScriptOutput reads the structured result from the output file
+// Returns nil if the script did not write anything
+func readScriptOutput(path string) (*reporter.ScriptOutput, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open output file: %w", err)
+	}
+	defer func() {
+		if closeErr := file.Close(); closeErr != nil {
+			log.WithError(closeErr).WithField("path", path).Debug("Failed to close script output file")
+		}
+	}()
+
+	data, err := io.ReadAll(io.LimitReader(file, maxScriptOutputBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read output file: %w", err)
+	}
+	if len(data) > maxScriptOutputBytes {
+		return nil, fmt.Errorf("output file is larger than %d bytes", maxScriptOutputBytes)
+	}
+	if strings.TrimSpace(string(data)) == "" {
+		return nil, nil
+	}
+
+	var output reporter.ScriptOutput
+	if err := json.Unmarshal(data, &output); err != nil {
+		return nil, fmt.Errorf("output file is not valid JSON: %w", err)
+	}
+	if err := output.Validate(); err != nil {
+		return nil, err
+	}
+	return &output, nil
+}
+
+// removeScriptFile deletes a temp file created for a script run
+func removeScriptFile(path string) {
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		log.WithError(err).WithField("path", path).Warn("Failed to remove script temp file")
+	}
+}
@@ -0,0 +1,167 @@
+package executor
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/rootly/edge-connector/internal/api"
+	"github.com/rootly/edge-connector/internal/config"
+	"github.com/rootly/edge-connector/internal/reporter"
+)
+
+// formParametersKey is the event.data key holding values submitted through the action form
+const formParametersKey = "parameters"
+
+// parameterFieldError is a validation failure for a single declared parameter
+type parameterFieldError struct {
+	name   string
+	reason string
+}
+
+// parameterValidationError lists every declared parameter whose value was rejected
+type parameterValidationError struct {
+	fields []parameterFieldError
+}
+
+func (e *parameterValidationError) add(name, reason string) {
+	e.fields = append(e.fields, parameterFieldError{name: name, reason: reason})
+}
+
+func (e *parameterValidationError) Error() string {
+	return "invalid parameters: " + strings.Join(e.fieldMessages(), "; ")
+}
+
+// fieldMessages returns one "name: reason" message per rejected parameter
+func (e *parameterValidationError) fieldMessages() []string {
+	messages := make([]string, 0, len(e.fields))
+	for _, field := range e.fields {
+		messages = append(messages, field.name+": "+field.reason)
+	}
+	return messages
+}
+
+// prepareParameters prepares parameters for execution with template substitution
+// User-provided values from event.data take precedence over config-defined templates.
+// Parameters with a definition are coerced to their declared type, fall back to the
+// definition's default, must be present if required and, for lists, must be one of the options.
+// Rejected values are returned as a *parameterValidationError with one message per field
+func (e *Executor) prepareParameters(action *config.Action, event api.Event) (map[string]interface{}, error) {
+	params := make(map[string]interface{}, len(action.Parameters))
+
+	// First, apply template substitution for all configured parameters
+	for key, template := range action.Parameters {
+		params[key] = e.substituteTemplate(template, event)
+	}
+
+	// Then, override with direct user-provided values from event.data
+	// This allows user input to take precedence over config defaults
+	for key := range action.Parameters {
+		if value, ok := event.Data[key]; ok && value != nil {
+			params[key] = parameterString(value)
+		}
+	}
+
+	// Finally, resolve and validate declared parameters
+	validationErr := &parameterValidationError{}
+	for _, def := range action.ParameterDefinitions {
+		value, ok := userParameterValue(action, def, event)
+		if !ok {
+			// Hardcoded or custom-templated value from parameters
+			if rendered, isString := params[def.Name].(string); isString && rendered != "" {
+				value, ok = rendered, true
+			}
+		}
+		if !ok && def.Default != nil {
+			value, ok = def.Default, true
+		}
+		if !ok {
+			delete(params, def.Name)
+			if def.Required {
+				validationErr.add(def.Name, "is required")
+			}
+			continue
+		}
+
+		validated, err := def.Validate(value)
+		if err != nil {
+			validationErr.add(def.Name, err.Error())
+			continue
+		}
+		params[def.Name] = validated
+	}
+
+	if len(validationErr.fields) > 0 {
+		return nil, validationErr
+	}
+	return params, nil
+}
+
+// userParameterValue returns the raw value the user supplied for a declared parameter
+// Direct event.data values win over values submitted through the action form. Empty values
+// count as not supplied, since the form sends blank fields as empty strings
+func userParameterValue(action *config.Action, def config.ParameterDefinition, event api.Event) (interface{}, bool) {
+	if value := event.Data[def.Name]; !isEmptyParameter(value) {
+		return value, true
+	}
+
+	// Read the form value directly (keeping its JSON type) only when the parameter uses the
+	// auto-generated mapping, so a custom template in parameters still decides the value
+	if template, ok := action.Parameters[def.Name]; ok && template != config.ParameterTemplate(def.Name) {
+		return nil, false
+	}
+	form, ok := event.Data[formParametersKey].(map[string]interface{})
+	if !ok {
+		return nil, false
+	}
+	if value := form[def.Name]; !isEmptyParameter(value) {
+		return value, true
+	}
+	return nil, false
+}
+
+// isEmptyParameter reports whether a supplied value should be treated as missing
+func isEmptyParameter(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+	str, ok := value.(string)
+	return ok && str == ""
+}
+
+// parameterString formats a parameter value for environment variables
+// Numbers use their shortest decimal form, booleans "true"/"false", and lists or objects JSON
+func parameterString(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case bool:
+		return strconv.FormatBool(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case int:
+		return strconv.Itoa(v)
+	default:
+		encoded, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(encoded)
+	}
+}
+
+// invalidParametersResult builds the failed result for a delivery rejected by parameter validation
+// Each rejected field is also written to stderr on its own line
+func invalidParametersResult(err error) reporter.ScriptResult {
+	result := reporter.ScriptResult{
+		ExitCode: 1,
+		Error:    err,
+	}
+	var validationErr *parameterValidationError
+	if errors.As(err, &validationErr) {
+		result.Stderr = strings.Join(validationErr.fieldMessages(), "\n") + "\n"
+	}
+	return result
+}
@@ -0,0 +1,237 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/rootly/edge-connector/internal/api"
+	"github.com/rootly/edge-connector/internal/config"
+	"github.com/rootly/edge-connector/internal/metrics"
+	"github.com/rootly/edge-connector/internal/reporter"
+)
+
+// Pipeline step statuses shown in the aggregated result
+const (
+	stepStatusCompleted = "completed"
+	stepStatusFailed    = "failed"
+	stepStatusSkipped   = "skipped"
+)
+
+// stepResult is the outcome of a single pipeline step
+type stepResult struct {
+	step     *config.Action
+	status   string
+	result   reporter.ScriptResult
+	filtered bool // Skipped because the step's when condition did not match
+}
+
+// runPipeline runs the steps of a pipeline action and aggregates their results into one
+// Sequential pipelines stop at the first failing step unless it has continue_on_error;
+// parallel pipelines always run every step. Steps still running at the pipeline's timeout are canceled
+func (e *Executor) runPipeline(ctx context.Context, action *config.Action, event api.Event) reporter.ScriptResult {
+	start := time.Now()
+	if action.Timeout > 0 {
+		timeout := time.Duration(action.Timeout) * time.Second
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeoutCause(ctx, timeout, fmt.Errorf("pipeline timed out after %v", timeout))
+		defer cancel()
+	}
+	results := make([]stepResult, len(action.Steps))
+	for i := range action.Steps {
+		results[i] = stepResult{step: &action.Steps[i], status: stepStatusSkipped}
+	}
+
+	if action.Parallel {
+		var wg sync.WaitGroup
+		for i := range action.Steps {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				results[i] = e.runStep(ctx, action, i, event)
+			}(i)
+		}
+		wg.Wait()
+	} else {
+		for i := range action.Steps {
+			results[i] = e.runStep(ctx, action, i, event)
+			if results[i].status == stepStatusFailed && !action.Steps[i].ContinueOnError {
+				break
+			}
+			if ctx.Err() != nil {
+				break
+			}
+		}
+	}
+
+	return aggregateStepResults(results, time.Since(start))
+}
+
+// runStep runs a single pipeline step
+func (e *Executor) runStep(ctx context.Context, pipeline *config.Action, index int, event api.Event) stepResult {
+	step := &pipeline.Steps[index]
+	logFields := log.Fields{
+		"action_id":   pipeline.ID,
+		"step_id":     step.ID,
+		"step_index":  index + 1,
+		"step_type":   step.Type,
+		"delivery_id": event.ID,
+	}
+
+	if !e.whenMatches(step, event) {
+		log.WithFields(logFields).Info("Pipeline step when condition not met, skipping")
+		return stepResult{step: step, status: stepStatusSkipped, filtered: true}
+	}
+
+	log.WithFields(logFields).Info("Running pipeline step")
+
+	start := time.Now()
+	result := e.runAction(ctx, step, event)
+
+	status := stepStatusCompleted
+	if result.Failed() {
+		status = stepStatusFailed
+	}
+	metrics.RecordActionExecution(step.ID, step.Type, status, time.Since(start))
+
+	logFields[fieldDurationMs] = result.DurationMs
+	logFields["exit_code"] = result.ExitCode
+	if status == stepStatusFailed {
+		log.WithFields(logFields).WithError(result.Error).Warn("Pipeline step failed")
+	} else {
+		log.WithFields(logFields).Info("Pipeline step completed")
+	}
+
+	return stepResult{step: step, status: status, result: result}
+}
+
+// aggregateStepResults combines step results into a single execution result
+// Each step's output is reported under a header line; the pipeline fails on the first
+// failed step that does not have continue_on_error
+func aggregateStepResults(results []stepResult, duration time.Duration) reporter.ScriptResult {
+	var stdout, stderr strings.Builder
+	var failed *stepResult
+	truncated := false
+	maxOutput := 0
+
+	for i := range results {
+		res := &results[i]
+		header := stepHeader(res, i, len(results))
+
+		stdout.WriteString(header)
+		if res.result.Stdout != "" {
+			stdout.WriteString(res.result.Stdout)
+			if !strings.HasSuffix(res.result.Stdout, "\n") {
+				stdout.WriteString("\n")
+			}
+		}
+
+		if res.result.Stderr != "" || res.result.Error != nil {
+			stderr.WriteString(header)
+			if res.result.Stderr != "" {
+				stderr.WriteString(res.result.Stderr)
+				if !strings.HasSuffix(res.result.Stderr, "\n") {
+					stderr.WriteString("\n")
+				}
+			}
+			if res.result.Error != nil {
+				fmt.Fprintf(&stderr, "error: %v\n", res.result.Error)
+			}
+		}
+
+		if failed == nil && res.status == stepStatusFailed && !res.step.ContinueOnError {
+			failed = res
+		}
+		truncated = truncated || res.result.Truncated
+		maxOutput = max(maxOutput, res.result.MaxOutput)
+	}
+
+	aggregated := reporter.ScriptResult{
+		Stdout:     stdout.String(),
+		Stderr:     stderr.String(),
+		DurationMs: duration.Milliseconds(),
+		Truncated:  truncated,
+		MaxOutput:  maxOutput,
+		Output:     aggregateStepOutputs(results),
+	}
+
+	if failed != nil {
+		aggregated.ExitCode = failed.result.ExitCode
+		if aggregated.ExitCode == 0 || (aggregated.ExitCode >= 200 && aggregated.ExitCode < 300) {
+			aggregated.ExitCode = 1
+		}
+		cause := failed.result.Error
+		if cause == nil && failed.result.Output != nil && failed.result.Output.StatusOverride != "" {
+			cause = fmt.Errorf("step reported status %s", failed.result.Output.StatusOverride)
+		} else if cause == nil {
+			cause = fmt.Errorf("exit code %d", failed.result.ExitCode)
+		}
+		aggregated.Error = fmt.Errorf("pipeline step %s failed: %w", failed.step.ID, cause)
+	} else if ctxErr := canceledStep(results); ctxErr != nil {
+		aggregated.ExitCode = 1
+		aggregated.Error = ctxErr
+	}
+
+	return aggregated
+}
+
+// aggregateStepOutputs merges the structured outputs of the steps, or returns nil if no step wrote one
+// Summaries are joined one per line and output keys are prefixed with the step id
+func aggregateStepOutputs(results []stepResult) *reporter.ScriptOutput {
+	var merged *reporter.ScriptOutput
+	var summaries []string
+
+	for i := range results {
+		output := results[i].result.Output
+		if output == nil {
+			continue
+		}
+		if merged == nil {
+			merged = &reporter.ScriptOutput{}
+		}
+
+		stepID := results[i].step.ID
+		if output.Summary != "" {
+			summaries = append(summaries, stepID+": "+output.Summary)
+		}
+		merged.Links = append(merged.Links, output.Links...)
+		for key, value := range output.Outputs {
+			if merged.Outputs == nil {
+				merged.Outputs = make(map[string]interface{})
+			}
+			merged.Outputs[stepID+"."+key] = value
+		}
+	}
+
+	if merged != nil {
+		merged.Summary = strings.Join(summaries, "\n")
+	}
+	return merged
+}
+
+// canceledStep returns an error if steps were skipped because the pipeline was canceled
+// (as opposed to skipped after a failure, which is reported as that failure)
+func canceledStep(results []stepResult) error {
+	for i := range results {
+		if results[i].status == stepStatusSkipped && !results[i].filtered {
+			return fmt.Errorf("pipeline canceled before step %s ran", results[i].step.ID)
+		}
+	}
+	return nil
+}
+
+// stepHeader returns the header line written before a step's output
+func stepHeader(res *stepResult, index, total int) string {
+	if res.filtered {
+		return fmt.Sprintf("==> [%d/%d] %s: %s (when condition not met)\n", index+1, total, res.step.ID, res.status)
+	}
+	if res.status == stepStatusSkipped {
+		return fmt.Sprintf("==> [%d/%d] %s: %s\n", index+1, total, res.step.ID, res.status)
+	}
+	return fmt.Sprintf("==> [%d/%d] %s: %s (exit %d, %dms)\n",
+		index+1, total, res.step.ID, res.status, res.result.ExitCode, res.result.DurationMs)
+}
@@ -0,0 +1,251 @@
+package executor
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rootly/edge-connector/internal/api"
+	"github.com/rootly/edge-connector/internal/config"
+	"github.com/rootly/edge-connector/internal/reporter"
+)
+
+// pipelineServer records requested paths and fails requests to /fail
+func pipelineServer(t *testing.T) (*httptest.Server, func() []string) {
+	t.Helper()
+
+	var mu sync.Mutex
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		paths = append(paths, r.URL.Path)
+		mu.Unlock()
+
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(`{"error":"boom"}`))
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"status":"ok"}`))
+	}))
+	t.Cleanup(server.Close)
+
+	return server, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), paths...)
+	}
+}
+
+func httpStep(id, url string) config.Action {
+	return config.Action{
+		ID:      id,
+		Type:    "http",
+		Timeout: 10,
+		HTTP:    &config.HTTPAction{URL: url, Method: "POST"},
+	}
+}
+
+func runTestPipeline(t *testing.T, pipeline config.Action) (reporter.ScriptResult, string) {
+	t.Helper()
+
+	pipeline.ID = "alert.created"
+	pipeline.Type = "pipeline"
+	pipeline.Trigger = config.TriggerConfig{EventType: "alert.created"}
+
+	var reportedResult reporter.ScriptResult
+	var reportedActionName string
+	mockRep := &mockReporter{
+		reportFunc: func(ctx context.Context, deliveryID, actionName, actionUUID string, result reporter.ScriptResult) error {
+			reportedResult = result
+			reportedActionName = actionName
+			return nil
+		},
+	}
+
+	executor := New([]config.Action{pipeline}, NewScriptRunner(nil, nil), NewHTTPExecutor(), mockRep)
+	executor.Execute(context.Background(), api.Event{
+		ID:   "delivery-1",
+		Type: "alert.created",
+		Data: map[string]interface{}{},
+	})
+
+	return reportedResult, reportedActionName
+}
+
+func TestExecute_PipelineSequential(t *testing.T) {
+	server, paths := pipelineServer(t)
+
+	result, actionName := runTestPipeline(t, config.Action{
+		Steps: []config.Action{
+			httpStep("collect_diagnostics", server.URL+"/diagnostics"),
+			httpStep("notify_webhook", server.URL+"/webhook"),
+		},
+	})
+
+	assert.Equal(t, "alert.created", actionName)
+	assert.Equal(t, []string{"/diagnostics", "/webhook"}, paths(), "Steps should run in order")
+	assert.NoError(t, result.Error)
+	assert.Equal(t, 0, result.ExitCode)
+	assert.False(t, result.Failed())
+	assert.Contains(t, result.Stdout, "==> [1/2] collect_diagnostics: completed (exit 200")
+	assert.Contains(t, result.Stdout, "==> [2/2] notify_webhook: completed (exit 200")
+}
+
+func TestExecute_PipelineStopsAtFailedStep(t *testing.T) {
+	server, paths := pipelineServer(t)
+
+	result, _ := runTestPipeline(t, config.Action{
+		Steps: []config.Action{
+			httpStep("collect_diagnostics", server.URL+"/fail"),
+			httpStep("notify_webhook", server.URL+"/webhook"),
+		},
+	})
+
+	assert.Equal(t, []string{"/fail"}, paths(), "Steps after a failure should not run")
+	require.Error(t, result.Error)
+	assert.Contains(t, result.Error.Error(), "pipeline step collect_diagnostics failed")
+	assert.Equal(t, 500, result.ExitCode)
+	assert.Contains(t, result.Stdout, "==> [2/2] notify_webhook: skipped")
+	assert.Contains(t, result.Stderr, "==> [1/2] collect_diagnostics: failed")
+}
+
+func TestExecute_PipelineContinueOnError(t *testing.T) {
+	server, paths := pipelineServer(t)
+
+	diagnostics := httpStep("collect_diagnostics", server.URL+"/fail")
+	diagnostics.ContinueOnError = true
+
+	result, _ := runTestPipeline(t, config.Action{
+		Steps: []config.Action{
+			diagnostics,
+			httpStep("notify_webhook", server.URL+"/webhook"),
+		},
+	})
+
+	assert.Equal(t, []string{"/fail", "/webhook"}, paths())
+	assert.NoError(t, result.Error, "Step with continue_on_error should not fail the pipeline")
+	assert.Equal(t, 0, result.ExitCode)
+	assert.Contains(t, result.Stdout, "==> [1/2] collect_diagnostics: failed")
+	assert.Contains(t, result.Stdout, "==> [2/2] notify_webhook: completed")
+}
+
+func TestExecute_PipelineTimeout(t *testing.T) {
+	server, _, unblock := blockingServer(t)
+	defer unblock()
+
+	start := time.Now()
+	result, _ := runTestPipeline(t, config.Action{
+		Timeout: 1,
+		Steps: []config.Action{
+			httpStep("slow", server.URL+"/slow"),
+			httpStep("notify_webhook", server.URL+"/webhook"),
+		},
+	})
+
+	assert.Less(t, time.Since(start), 5*time.Second, "The pipeline timeout should cut the 10s step short")
+	require.Error(t, result.Error)
+	assert.Contains(t, result.Stderr, "==> [1/2] slow: failed")
+	assert.Contains(t, result.Stdout, "==> [2/2] notify_webhook: skipped")
+}
+
+func TestExecute_PipelineParallel(t *testing.T) {
+	// Each request waits until both have arrived, which only happens if the steps run concurrently
+	var arrived sync.WaitGroup
+	arrived.Add(2)
+	allArrived := make(chan struct{})
+	go func() {
+		arrived.Wait()
+		close(allArrived)
+	}()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		arrived.Done()
+		select {
+		case <-allArrived:
+			w.WriteHeader(http.StatusOK)
+		case <-time.After(5 * time.Second):
+			w.WriteHeader(http.StatusGatewayTimeout)
+		}
+	}))
+	defer server.Close()
+
+	result, _ := runTestPipeline(t, config.Action{
+		Parallel: true,
+		Steps: []config.Action{
+			httpStep("notify_slack", server.URL+"/slack"),
+			httpStep("notify_pager", server.URL+"/pager"),
+		},
+	})
+
+	assert.NoError(t, result.Error)
+	assert.Contains(t, result.Stdout, "==> [1/2] notify_slack: completed")
+	assert.Contains(t, result.Stdout, "==> [2/2] notify_pager: completed")
+}
+
+func TestExecute_PipelineStepWhenCondition(t *testing.T) {
+	server, paths := pipelineServer(t)
+
+	pager := httpStep("notify_pager", server.URL+"/pager")
+	pager.When = `labels.severity == "critical"`
+
+	result, _ := runTestPipeline(t, config.Action{
+		Steps: []config.Action{
+			pager,
+			httpStep("notify_webhook", server.URL+"/webhook"),
+		},
+	})
+
+	assert.Equal(t, []string{"/webhook"}, paths(), "Step with a non-matching when should not run")
+	assert.NoError(t, result.Error)
+	assert.Contains(t, result.Stdout, "==> [1/2] notify_pager: skipped (when condition not met)")
+	assert.Contains(t, result.Stdout, "==> [2/2] notify_webhook: completed")
+}
+
+func TestAggregateStepResults_Canceled(t *testing.T) {
+	steps := []config.Action{{ID: "first"}, {ID: "second"}}
+	results := []stepResult{
+		{step: &steps[0], status: stepStatusCompleted},
+		{step: &steps[1], status: stepStatusSkipped},
+	}
+
+	result := aggregateStepResults(results, time.Second)
+
+	require.Error(t, result.Error)
+	assert.Contains(t, result.Error.Error(), "pipeline canceled before step second ran")
+	assert.Equal(t, 1, result.ExitCode)
+	assert.Equal(t, int64(1000), result.DurationMs)
+}
+
+func TestAggregateStepResults_Outputs(t *testing.T) {
+	steps := []config.Action{{ID: "restart"}, {ID: "verify"}, {ID: "notify"}}
+	results := []stepResult{
+		{step: &steps[0], status: stepStatusCompleted, result: reporter.ScriptResult{Output: &reporter.ScriptOutput{
+			Summary: "restarted 3 pods",
+			Links:   []api.ExecutionLink{{URL: "https://grafana.example.com"}},
+			Outputs: map[string]interface{}{"pods": float64(3)},
+		}}},
+		{step: &steps[1], status: stepStatusFailed, result: reporter.ScriptResult{Output: &reporter.ScriptOutput{
+			Summary:        "health check failing",
+			StatusOverride: "failed",
+		}}},
+		{step: &steps[2], status: stepStatusSkipped},
+	}
+
+	result := aggregateStepResults(results, time.Second)
+
+	require.NotNil(t, result.Output)
+	assert.Equal(t, "restart: restarted 3 pods\nverify: health check failing", result.Output.Summary)
+	assert.Len(t, result.Output.Links, 1)
+	assert.Equal(t, map[string]interface{}{"restart.pods": float64(3)}, result.Output.Outputs)
+	assert.Empty(t, result.Output.StatusOverride, "Step overrides should not leak into the pipeline status")
+	require.Error(t, result.Error)
+	assert.Contains(t, result.Error.Error(), "pipeline step verify failed: step reported status failed")
+}
@@ -0,0 +1,141 @@
+package executor
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/rootly/edge-connector/internal/api"
+	"github.com/rootly/edge-connector/internal/capture"
+	"github.com/rootly/edge-connector/internal/reporter"
+)
+
+// DefaultProgressInterval is how often new output is sent when no interval is configured
+const DefaultProgressInterval = 5 * time.Second
+
+// progressUpdateTimeout bounds a single progress update so a slow API cannot stall the stream
+const progressUpdateTimeout = 10 * time.Second
+
+// ProgressReporter sends the output of a delivery that is still running
+type ProgressReporter interface {
+	ReportProgress(ctx context.Context, deliveryID string, result reporter.ScriptResult) error
+}
+
+// progressStream sends a running script's stdout and stderr at a fixed interval
+// Each update carries all output so far, and is only sent once a new line has been written
+type progressStream struct {
+	reporter   ProgressReporter
+	stdout     *capture.Buffer
+	stderr     *capture.Buffer
+	done       chan struct{}
+	deliveryID string
+	interval   time.Duration
+	wg         sync.WaitGroup
+	mu         sync.Mutex // Guards the buffers and dirty while the script writes to them
+	dirty      bool       // A complete line was written since the last update
+}
+
+func newProgressStream(progressReporter ProgressReporter, interval time.Duration, deliveryID string, stdout, stderr *capture.Buffer) *progressStream {
+	if interval <= 0 {
+		interval = DefaultProgressInterval
+	}
+	return &progressStream{
+		reporter:   progressReporter,
+		stdout:     stdout,
+		stderr:     stderr,
+		done:       make(chan struct{}),
+		deliveryID: deliveryID,
+		interval:   interval,
+	}
+}
+
+// writer returns a writer for one of the stream's buffers, safe to use while updates are sent
+func (s *progressStream) writer(buf *capture.Buffer) io.Writer {
+	return &progressWriter{stream: s, buf: buf}
+}
+
+// start sends updates until stop is called or ctx is done
+func (s *progressStream) start(ctx context.Context) {
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-s.done:
+				return
+			case <-ticker.C:
+				s.flush(ctx)
+			}
+		}
+	}()
+}
+
+// stop ends the stream without a final update; the execution report follows right after
+func (s *progressStream) stop() {
+	close(s.done)
+	s.wg.Wait()
+}
+
+// flush sends the output so far if a new line was written since the last update
+func (s *progressStream) flush(ctx context.Context) {
+	s.mu.Lock()
+	if !s.dirty {
+		s.mu.Unlock()
+		return
+	}
+	s.dirty = false
+	snapshot := reporter.ScriptResult{
+		Stdout:    s.stdout.String(),
+		Stderr:    s.stderr.String(),
+		Truncated: s.stdout.Truncated() || s.stderr.Truncated(),
+		MaxOutput: s.stdout.Limit(),
+	}
+	s.mu.Unlock()
+
+	updateCtx, cancel := context.WithTimeout(ctx, progressUpdateTimeout)
+	defer cancel()
+
+	err := s.reporter.ReportProgress(updateCtx, s.deliveryID, snapshot)
+	if err == nil {
+		return
+	}
+
+	// Updates carry all output so far, so the next tick resends what this one missed
+	s.mu.Lock()
+	s.dirty = true
+	s.mu.Unlock()
+
+	logger := log.WithError(err).WithField("delivery_id", s.deliveryID)
+	if errors.Is(err, api.ErrProgressRateLimited) {
+		logger.Debug("Progress update skipped by rate limit")
+		return
+	}
+	logger.Warn("Failed to send progress update")
+}
+
+// progressWriter writes to a capture buffer under the stream's lock
+type progressWriter struct {
+	stream *progressStream
+	buf    *capture.Buffer
+}
+
+func (w *progressWriter) Write(p []byte) (int, error) {
+	w.stream.mu.Lock()
+	defer w.stream.mu.Unlock()
+
+	if bytes.IndexByte(p, '\n') >= 0 {
+		w.stream.dirty = true
+	}
+	return w.buf.Write(p)
+}
@@ -0,0 +1,168 @@
+package executor
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/rootly/edge-connector/internal/api"
+	"github.com/rootly/edge-connector/internal/breaker"
+	"github.com/rootly/edge-connector/internal/config"
+	"github.com/rootly/edge-connector/internal/metrics"
+	"github.com/rootly/edge-connector/internal/ratelimit"
+	"github.com/rootly/edge-connector/internal/reporter"
+)
+
+// Reasons recorded for deliveries failed by a rate limit or circuit breaker
+const (
+	throttleRateLimited     = "rate_limited"
+	throttleHostRateLimited = "host_rate_limited"
+	throttleCircuitOpen     = "circuit_open"
+)
+
+var (
+	errRateLimited = errors.New("rate limit exceeded")
+	errCircuitOpen = errors.New("circuit breaker open")
+)
+
+// actionRateLimiter holds a token bucket per action
+type actionRateLimiter struct {
+	limiters map[string]*actionLimiter
+	mu       sync.Mutex
+}
+
+// actionLimiter is an action's bucket and the limit it was created with
+// A reload that changes the limit starts a new, full bucket
+type actionLimiter struct {
+	limiter *ratelimit.Limiter
+	limit   config.RateLimit
+}
+
+func newActionRateLimiter() *actionRateLimiter {
+	return &actionRateLimiter{limiters: make(map[string]*actionLimiter)}
+}
+
+// allow takes a token from the action's bucket
+func (l *actionRateLimiter) allow(action *config.Action, now time.Time) bool {
+	l.mu.Lock()
+	bucket, ok := l.limiters[action.ID]
+	if !ok || bucket.limit != *action.RateLimit {
+		bucket = &actionLimiter{
+			limiter: ratelimit.New(action.RateLimit.PerSec, action.RateLimit.Burst),
+			limit:   *action.RateLimit,
+		}
+		l.limiters[action.ID] = bucket
+	}
+	l.mu.Unlock()
+
+	return bucket.limiter.Allow(now)
+}
+
+// checkRateLimit applies the action's rate limit to a delivery
+// Returns the failed result to report instead of running the action, or nil if the delivery may run
+func (e *Executor) checkRateLimit(action *config.Action, event api.Event) *reporter.ScriptResult {
+	if action.RateLimit == nil || e.rateLimits.allow(action, time.Now()) {
+		return nil
+	}
+
+	metrics.RecordActionThrottled(action.ID, throttleRateLimited)
+	log.WithFields(log.Fields{
+		"action_id":   action.ID,
+		"delivery_id": event.ID,
+		"per_sec":     action.RateLimit.PerSec,
+	}).Warn("Action over its rate limit, failing delivery")
+
+	err := fmt.Errorf("%w: action %s allows %g deliveries per second", errRateLimited, action.ID, action.RateLimit.PerSec)
+	return &reporter.ScriptResult{ExitCode: 1, Error: err, Stderr: err.Error()}
+}
+
+// hostGuards holds the rate limit and circuit breaker of each destination host of HTTP actions
+type hostGuards struct {
+	config *config.HTTPTargetsConfig
+	hosts  map[string]*hostGuard
+	mu     sync.Mutex
+}
+
+// hostGuard limits requests to one host; either control may be nil when not configured
+type hostGuard struct {
+	limiter *ratelimit.Limiter
+	breaker *breaker.Breaker
+	host    string
+	perSec  float64
+}
+
+func newHostGuards(cfg *config.HTTPTargetsConfig) *hostGuards {
+	return &hostGuards{config: cfg, hosts: make(map[string]*hostGuard)}
+}
+
+// get returns the guard for a host, or nil if the host has no limits
+func (g *hostGuards) get(host string) *hostGuard {
+	if g == nil {
+		return nil
+	}
+	host = strings.ToLower(host)
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	guard, ok := g.hosts[host]
+	if ok {
+		return guard
+	}
+
+	target := g.config.For(host)
+	if target.RateLimit != nil || target.CircuitBreaker != nil {
+		guard = &hostGuard{host: host}
+		if limit := target.RateLimit; limit != nil {
+			guard.limiter = ratelimit.New(limit.PerSec, limit.Burst)
+			guard.perSec = limit.PerSec
+		}
+		if cb := target.CircuitBreaker; cb != nil {
+			guard.breaker = breaker.New(cb.FailureThreshold, time.Duration(cb.CooldownSec)*time.Second, func(state breaker.State) {
+				metrics.SetCircuitBreakerState(host, int(state))
+				log.WithFields(log.Fields{
+					"host":  host,
+					"state": state.String(),
+				}).Warn("Circuit breaker state changed")
+			})
+			metrics.SetCircuitBreakerState(host, int(breaker.Closed))
+		}
+	}
+	g.hosts[host] = guard // Hosts without limits are cached as nil
+	return guard
+}
+
+// allow checks the host's circuit breaker, then its rate limit
+// Returns the throttle reason and error if the request must not be sent
+// Every allowed request must be followed by done
+func (g *hostGuard) allow(now time.Time) (string, error) {
+	if g.breaker != nil && !g.breaker.Allow(now) {
+		return throttleCircuitOpen, fmt.Errorf("%w: host %s is failing, next attempt after %s",
+			errCircuitOpen, g.host, g.breaker.RetryAt().UTC().Format(time.RFC3339))
+	}
+	if g.limiter != nil && !g.limiter.Allow(now) {
+		if g.breaker != nil {
+			g.breaker.Release()
+		}
+		return throttleHostRateLimited, fmt.Errorf("%w: host %s allows %g requests per second", errRateLimited, g.host, g.perSec)
+	}
+	return "", nil
+}
+
+// done records the outcome of an allowed request with the circuit breaker
+// Request errors, 429 and 5xx responses are failures; requests canceled by the caller are not counted
+func (g *hostGuard) done(statusCode int, err error, canceled bool) {
+	if g.breaker == nil {
+		return
+	}
+	if canceled {
+		g.breaker.Release()
+		return
+	}
+	failed := err != nil || statusCode == 429 || statusCode >= 500
+	g.breaker.Record(!failed, time.Now())
+}
@@ -0,0 +1,169 @@
+package executor
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rootly/edge-connector/internal/api"
+	"github.com/rootly/edge-connector/internal/breaker"
+	"github.com/rootly/edge-connector/internal/config"
+	"github.com/rootly/edge-connector/internal/reporter"
+)
+
+func TestExecute_ActionRateLimit(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	var results []reporter.ScriptResult
+	mockRep := &mockReporter{
+		reportFunc: func(ctx context.Context, deliveryID, actionName, actionUUID string, result reporter.ScriptResult) error {
+			results = append(results, result)
+			return nil
+		},
+	}
+
+	action := config.Action{
+		ID:        "alert.created",
+		Type:      "http",
+		Timeout:   5,
+		HTTP:      &config.HTTPAction{URL: server.URL, Method: "POST"},
+		RateLimit: &config.RateLimit{PerSec: 0.01, Burst: 2},
+		Trigger:   config.TriggerConfig{EventType: "alert.created"},
+	}
+	executor := New([]config.Action{action}, NewScriptRunner(nil, nil), NewHTTPExecutor(), mockRep)
+
+	for _, id := range []string{"delivery-1", "delivery-2", "delivery-3"} {
+		executor.Execute(context.Background(), api.Event{ID: id, Type: "alert.created"})
+	}
+
+	require.Len(t, results, 3, "Rate limited deliveries are still reported")
+	assert.False(t, results[0].Failed())
+	assert.False(t, results[1].Failed())
+	assert.True(t, results[2].Failed())
+	require.ErrorIs(t, results[2].Error, errRateLimited)
+	assert.Contains(t, results[2].Stderr, "action alert.created allows 0.01 deliveries per second")
+	assert.Equal(t, int32(2), requests.Load())
+
+	// A reload with a different limit starts a new bucket
+	action.RateLimit = &config.RateLimit{PerSec: 0.01, Burst: 1}
+	executor.SetActions([]config.Action{action})
+	executor.Execute(context.Background(), api.Event{ID: "delivery-4", Type: "alert.created"})
+	require.Len(t, results, 4)
+	assert.False(t, results[3].Failed())
+}
+
+func TestHTTPExecutor_HostRateLimit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	serverURL, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	h := NewHTTPExecutor()
+	h.SetTargetLimits(&config.HTTPTargetsConfig{
+		Hosts: map[string]config.HTTPTargetConfig{
+			serverURL.Hostname(): {RateLimit: &config.RateLimit{PerSec: 0.01, Burst: 1}},
+		},
+	})
+
+	action := &config.Action{ID: "notify", Type: "http", Timeout: 5, HTTP: &config.HTTPAction{URL: server.URL, Method: "POST"}}
+	first := h.Execute(context.Background(), action, api.Event{ID: "delivery-1"}, nil)
+	assert.False(t, first.Failed())
+
+	second := h.Execute(context.Background(), action, api.Event{ID: "delivery-2"}, nil)
+	assert.True(t, second.Failed())
+	require.ErrorIs(t, second.Error, errRateLimited)
+	assert.Contains(t, second.Stderr, "host "+serverURL.Hostname()+" allows 0.01 requests per second")
+
+	// Other hosts fall back to the top-level limits (none here)
+	assert.Nil(t, h.targets.get("other.example.com"))
+}
+
+func TestHTTPExecutor_CircuitBreaker(t *testing.T) {
+	var requests atomic.Int32
+	var healthy atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if healthy.Load() {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	h := NewHTTPExecutor()
+	h.SetTargetLimits(&config.HTTPTargetsConfig{
+		HTTPTargetConfig: config.HTTPTargetConfig{
+			CircuitBreaker: &config.CircuitBreakerConfig{FailureThreshold: 2, CooldownSec: 60},
+		},
+	})
+
+	action := &config.Action{ID: "notify", Type: "http", Timeout: 5, HTTP: &config.HTTPAction{URL: server.URL, Method: "POST"}}
+	for range 2 {
+		result := h.Execute(context.Background(), action, api.Event{ID: "delivery"}, nil)
+		assert.Equal(t, http.StatusServiceUnavailable, result.ExitCode)
+	}
+
+	// Open: fails fast without sending
+	result := h.Execute(context.Background(), action, api.Event{ID: "delivery"}, nil)
+	assert.True(t, result.Failed())
+	require.ErrorIs(t, result.Error, errCircuitOpen)
+	assert.Equal(t, int32(2), requests.Load())
+
+	serverURL, err := url.Parse(server.URL)
+	require.NoError(t, err)
+	guard := h.targets.get(serverURL.Hostname())
+	require.NotNil(t, guard)
+	assert.Equal(t, breaker.Open, guard.breaker.State())
+
+	// Let the cooldown pass (half-open without a trial in flight), so the next request is the trial
+	// A successful trial closes the breaker
+	healthy.Store(true)
+	assert.True(t, guard.breaker.Allow(time.Now().Add(time.Minute)))
+	guard.breaker.Release()
+	result = h.Execute(context.Background(), action, api.Event{ID: "delivery"}, nil)
+	assert.False(t, result.Failed())
+	assert.Equal(t, breaker.Closed, guard.breaker.State())
+	assert.Equal(t, int32(3), requests.Load())
+}
+
+func TestHostGuard_CanceledRequestsAreNotFailures(t *testing.T) {
+	guards := newHostGuards(&config.HTTPTargetsConfig{
+		HTTPTargetConfig: config.HTTPTargetConfig{
+			CircuitBreaker: &config.CircuitBreakerConfig{FailureThreshold: 1, CooldownSec: 60},
+		},
+	})
+	guard := guards.get("Hooks.Example.com")
+	require.NotNil(t, guard)
+	assert.Same(t, guard, guards.get("hooks.example.com"), "Hosts are matched case-insensitively")
+
+	reason, err := guard.allow(time.Now())
+	require.NoError(t, err)
+	assert.Empty(t, reason)
+	guard.done(0, context.Canceled, true)
+	assert.Equal(t, breaker.Closed, guard.breaker.State())
+
+	_, err = guard.allow(time.Now())
+	require.NoError(t, err)
+	guard.done(http.StatusTooManyRequests, nil, false)
+	assert.Equal(t, breaker.Open, guard.breaker.State())
+
+	reason, err = guard.allow(time.Now())
+	require.ErrorIs(t, err, errCircuitOpen)
+	assert.Equal(t, throttleCircuitOpen, reason)
+}
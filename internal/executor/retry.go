@@ -0,0 +1,100 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"slices"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/rootly/edge-connector/internal/api"
+	"github.com/rootly/edge-connector/internal/backoff"
+	"github.com/rootly/edge-connector/internal/config"
+	"github.com/rootly/edge-connector/internal/reporter"
+)
+
+// runWithRetry runs an action and re-runs it per its retry policy while it fails with a retryable result
+// The last attempt's result is returned; if the action ran more than once, it carries every attempt
+// and its duration covers all attempts including the delays between them
+func runWithRetry(ctx context.Context, action *config.Action, event api.Event, run func() reporter.ScriptResult) reporter.ScriptResult {
+	policy := action.Retry
+	start := time.Now()
+	attempts := make([]reporter.Attempt, 0, policy.Attempts())
+
+	var result reporter.ScriptResult
+	for attempt := 1; ; attempt++ {
+		attemptStart := time.Now()
+		result = run()
+		attempts = append(attempts, reporter.Attempt{
+			StartedAt:  attemptStart,
+			Error:      result.Error,
+			Stderr:     result.Stderr,
+			DurationMs: result.DurationMs,
+			ExitCode:   result.ExitCode,
+			Failed:     result.Failed(),
+		})
+
+		if !result.Failed() || attempt >= policy.Attempts() || ctx.Err() != nil || !isRetryable(action, result) {
+			break
+		}
+
+		delay := backoff.Delay(policy.Backoff, time.Duration(policy.DelayMs)*time.Millisecond, attempt,
+			time.Duration(policy.MaxDelayMs)*time.Millisecond)
+		log.WithFields(log.Fields{
+			"action_id":    action.ID,
+			"delivery_id":  event.ID,
+			"attempt":      attempt,
+			"max_attempts": policy.Attempts(),
+			"exit_code":    result.ExitCode,
+			"delay":        delay,
+		}).Warn("Action failed, retrying")
+
+		if !sleepContext(ctx, delay) {
+			break
+		}
+	}
+
+	if len(attempts) > 1 {
+		result.Attempts = attempts
+		result.DurationMs = time.Since(start).Milliseconds()
+	}
+	return result
+}
+
+// isRetryable reports whether a failed result matches the action's retry policy
+// Scripts retry on the listed exit codes (any non-zero by default). HTTP actions retry on the
+// listed statuses (5xx by default) and on request errors such as refused connections
+func isRetryable(action *config.Action, result reporter.ScriptResult) bool {
+	policy := action.Retry
+
+	if action.Type == actionTypeHTTP {
+		var urlErr *url.Error
+		if errors.As(result.Error, &urlErr) {
+			return true
+		}
+		if len(policy.HTTPStatuses) > 0 {
+			return slices.Contains(policy.HTTPStatuses, result.ExitCode)
+		}
+		return result.ExitCode >= 500 && result.ExitCode <= 599
+	}
+
+	if len(policy.ExitCodes) > 0 {
+		return slices.Contains(policy.ExitCodes, result.ExitCode)
+	}
+	return result.ExitCode != 0
+}
+
+// sleepContext waits for d, returning false if ctx is done first
+func sleepContext(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
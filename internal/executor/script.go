@@ -3,6 +3,7 @@ package executor
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
 	"os/exec"
@@ -12,6 +13,7 @@ import (
 
 	log "github.com/sirupsen/logrus"
 
+	"github.com/rootly/edge-connector/internal/api"
 	"github.com/rootly/edge-connector/internal/config"
 	"github.com/rootly/edge-connector/internal/reporter"
 )
@@ -22,11 +24,18 @@ type GitManager interface {
 	RUnlock(repoURL string)
 }
 
+// scriptWaitDelay bounds how long Run waits for output pipes after a script is killed
+// Child processes that inherited stdout/stderr would otherwise keep Run blocked until they exit
+const scriptWaitDelay = 2 * time.Second
+
 // ScriptRunner handles script execution
 type ScriptRunner struct {
-	gitManager   GitManager
-	globalEnv    map[string]string
-	allowedPaths []string
+	gitManager       GitManager
+	progress         ProgressReporter
+	globalEnv        map[string]string
+	outputLimits     OutputLimits
+	allowedPaths     []string
+	progressInterval time.Duration
 }
 
 // NewScriptRunner creates a new script runner
@@ -42,8 +51,23 @@ func (r *ScriptRunner) SetGitManager(gitManager GitManager) {
 	r.gitManager = gitManager
 }
 
+// SetOutputLimits sets the caps on captured stdout and stderr
+func (r *ScriptRunner) SetOutputLimits(limits OutputLimits) {
+	r.outputLimits = limits
+}
+
+// SetProgressReporter enables live output updates for actions with progress: true
+// New output is sent at most once per interval while the script runs
+func (r *ScriptRunner) SetProgressReporter(progressReporter ProgressReporter, interval time.Duration) {
+	r.progress = progressReporter
+	r.progressInterval = interval
+}
+
 // Run executes a script with the given action configuration and parameters
-func (r *ScriptRunner) Run(ctx context.Context, action *config.Action, params map[string]string) reporter.ScriptResult {
+// Each parameter is exposed as REC_PARAM_<NAME>, and all of them as a JSON object in REC_PARAMS_JSON.
+// With input: stdin or file the script also gets a JSON document with the parameters, event and action.
+// A structured result written to REC_OUTPUT_FILE is returned in the result's Output
+func (r *ScriptRunner) Run(ctx context.Context, action *config.Action, event api.Event, params map[string]interface{}) reporter.ScriptResult {
 	start := time.Now()
 
 	// Acquire read lock on git repository if this is a git-based action
@@ -134,9 +158,10 @@ func (r *ScriptRunner) Run(ctx context.Context, action *config.Action, params ma
 
 	// Set working directory to script directory
 	cmd.Dir = filepath.Dir(action.Script)
+	cmd.WaitDelay = scriptWaitDelay
 
-	// Set environment variables
-	cmd.Env = os.Environ()
+	// Start from a minimal environment so connector secrets such as REC_API_KEY never reach the script
+	cmd.Env = inheritedEnv(action.InheritEnv)
 	for key, value := range r.globalEnv {
 		cmd.Env = append(cmd.Env, fmt.Sprintf("%s=%s", key, value))
 	}
@@ -144,13 +169,83 @@ func (r *ScriptRunner) Run(ctx context.Context, action *config.Action, params ma
 		cmd.Env = append(cmd.Env, fmt.Sprintf("%s=%s", key, value))
 	}
 	for key, value := range params {
-		cmd.Env = append(cmd.Env, fmt.Sprintf("REC_PARAM_%s=%s", strings.ToUpper(key), value))
+		cmd.Env = append(cmd.Env, fmt.Sprintf("REC_PARAM_%s=%s", strings.ToUpper(key), parameterString(value)))
+	}
+	if len(params) > 0 {
+		paramsJSON, err := json.Marshal(params)
+		if err != nil {
+			log.WithError(err).Warn("Failed to encode parameters as JSON, REC_PARAMS_JSON not set")
+		} else {
+			cmd.Env = append(cmd.Env, "REC_PARAMS_JSON="+string(paramsJSON))
+		}
+	}
+
+	// Scripts may write a structured result to REC_OUTPUT_FILE
+	outputPath, outputErr := createOutputFile()
+	if outputErr != nil {
+		return reporter.ScriptResult{
+			ExitCode: 1,
+			Error:    outputErr,
+		}
+	}
+	defer removeScriptFile(outputPath)
+	cmd.Env = append(cmd.Env, "REC_OUTPUT_FILE="+outputPath)
+
+	// Pass the input document on stdin or in a temp file if the action asks for it
+	if action.Input == config.InputStdin || action.Input == config.InputFile {
+		input, err := json.Marshal(newScriptInput(action, event, params))
+		if err != nil {
+			return reporter.ScriptResult{
+				ExitCode: 1,
+				Error:    fmt.Errorf("failed to encode script input: %w", err),
+			}
+		}
+
+		if action.Input == config.InputStdin {
+			cmd.Stdin = bytes.NewReader(input)
+		} else {
+			inputPath, err := writeInputFile(input)
+			if err != nil {
+				return reporter.ScriptResult{
+					ExitCode: 1,
+					Error:    err,
+				}
+			}
+			defer removeScriptFile(inputPath)
+			cmd.Env = append(cmd.Env, "REC_INPUT_FILE="+inputPath)
+		}
+	}
+
+	// Capture output, keeping the head and tail of chatty scripts within the output limit
+	runID := captureRunID(action, event.ID)
+	stdout, finishStdout := r.outputLimits.newCapture(action, runID, "stdout")
+	defer finishStdout()
+	stderr, finishStderr := r.outputLimits.newCapture(action, runID, "stderr")
+	defer finishStderr()
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+
+	// Stream output while the script runs; the delivery stays running until the final report
+	stopProgress := func() {}
+	if action.Progress && r.progress != nil && event.ID != "" {
+		stream := newProgressStream(r.progress, r.progressInterval, event.ID, stdout, stderr)
+		cmd.Stdout = stream.writer(stdout)
+		cmd.Stderr = stream.writer(stderr)
+		stream.start(ctxWithTimeout)
+		stopProgress = stream.stop
 	}
 
-	// Capture output
-	var stdout, stderr bytes.Buffer
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
+	// The stdout/stderr files get the full streams, not the truncated captures
+	if action.Stdout != "" {
+		var closeFile func()
+		cmd.Stdout, closeFile = teeToFile(cmd.Stdout, action.Stdout, "stdout")
+		defer closeFile()
+	}
+	if action.Stderr != "" {
+		var closeFile func()
+		cmd.Stderr, closeFile = teeToFile(cmd.Stderr, action.Stderr, "stderr")
+		defer closeFile()
+	}
 
 	log.WithFields(log.Fields{
 		actionTypeScript: action.Script,
@@ -159,14 +254,28 @@ func (r *ScriptRunner) Run(ctx context.Context, action *config.Action, params ma
 
 	// Execute command
 	err := cmd.Run()
+	stopProgress()
 
 	duration := time.Since(start)
 	result := reporter.ScriptResult{
 		Stdout:     stdout.String(),
 		Stderr:     stderr.String(),
+		Truncated:  stdout.Truncated() || stderr.Truncated(),
+		MaxOutput:  stdout.Limit(),
 		DurationMs: duration.Milliseconds(),
 	}
 
+	// Check for cancellation by the caller (e.g. shutdown or pipeline deadline reached)
+	if ctx.Err() != nil {
+		result.ExitCode = -1
+		result.Error = fmt.Errorf("script canceled: %w", context.Cause(ctx))
+		log.WithFields(log.Fields{
+			actionTypeScript: action.Script,
+			"cause":          context.Cause(ctx),
+		}).Warn("Script execution canceled")
+		return result
+	}
+
 	// Check for timeout
 	if ctxWithTimeout.Err() == context.DeadlineExceeded {
 		result.ExitCode = -1
@@ -178,6 +287,17 @@ func (r *ScriptRunner) Run(ctx context.Context, action *config.Action, params ma
 		return result
 	}
 
+	// Read the structured result, if the script wrote one
+	output, outputErr := readScriptOutput(outputPath)
+	if outputErr != nil {
+		log.WithError(outputErr).WithField(actionTypeScript, action.Script).Warn("Ignoring invalid script output file")
+		if result.Stderr != "" && !strings.HasSuffix(result.Stderr, "\n") {
+			result.Stderr += "\n"
+		}
+		result.Stderr += fmt.Sprintf("rec: ignoring REC_OUTPUT_FILE: %v\n", outputErr)
+	}
+	result.Output = output
+
 	// Check for execution error
 	if err != nil {
 		if exitErr, ok := err.(*exec.ExitError); ok {
@@ -233,19 +353,57 @@ func (r *ScriptRunner) Run(ctx context.Context, action *config.Action, params ma
 		}).Debugf("Script output (stderr):\n%s", result.Stderr)
 	}
 
-	// Write output to files if configured
-	if action.Stdout != "" {
-		if err := os.WriteFile(action.Stdout, stdout.Bytes(), 0644); err != nil {
-			log.WithError(err).Warn("Failed to write stdout to file")
-		}
+	return result
+}
+
+// scriptInput is the JSON document passed to scripts with input: stdin or file
+type scriptInput struct {
+	Parameters map[string]interface{} `json:"parameters"`
+	Action     scriptInputAction      `json:"action"`
+	Event      api.Event              `json:"event"`
+}
+
+// scriptInputAction is the action metadata in the script input document
+type scriptInputAction struct {
+	ID   string `json:"id"`
+	Name string `json:"name,omitempty"`
+	Type string `json:"type"`
+}
+
+// newScriptInput builds the input document for a script run
+func newScriptInput(action *config.Action, event api.Event, params map[string]interface{}) scriptInput {
+	if params == nil {
+		params = map[string]interface{}{}
 	}
-	if action.Stderr != "" {
-		if err := os.WriteFile(action.Stderr, stderr.Bytes(), 0644); err != nil {
-			log.WithError(err).Warn("Failed to write stderr to file")
-		}
+	return scriptInput{
+		Parameters: params,
+		Action: scriptInputAction{
+			ID:   action.ID,
+			Name: action.Name,
+			Type: action.Type,
+		},
+		Event: event,
 	}
+}
 
-	return result
+// writeInputFile writes the input document to a temp file readable only by the connector user
+func writeInputFile(input []byte) (string, error) {
+	file, err := os.CreateTemp("", "rec-input-*.json")
+	if err != nil {
+		return "", fmt.Errorf("failed to create script input file: %w", err)
+	}
+	if _, err := file.Write(input); err != nil {
+		if closeErr := file.Close(); closeErr != nil {
+			log.WithError(closeErr).WithField("path", file.Name()).Debug("Failed to close script input file")
+		}
+		removeScriptFile(file.Name())
+		return "", fmt.Errorf("failed to write script input file: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		removeScriptFile(file.Name())
+		return "", fmt.Errorf("failed to write script input file: %w", err)
+	}
+	return file.Name(), nil
 }
 
 // isAllowedPath checks if the script path is within allowed paths
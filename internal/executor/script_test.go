@@ -2,19 +2,25 @@ package executor_test
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"runtime"
+	"slices"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/rootly/edge-connector/internal/api"
 	"github.com/rootly/edge-connector/internal/config"
 	"github.com/rootly/edge-connector/internal/executor"
+	"github.com/rootly/edge-connector/internal/reporter"
 )
 
 func TestScriptRunner_Run_Success(t *testing.T) {
@@ -48,11 +54,11 @@ exit 0
 		},
 	}
 
-	params := map[string]string{
+	params := map[string]interface{}{
 		"message": "Hello World",
 	}
 
-	result := runner.Run(context.Background(), action, params)
+	result := runner.Run(context.Background(), action, api.Event{}, params)
 
 	assert.Equal(t, 0, result.ExitCode)
 	assert.Nil(t, result.Error)
@@ -61,6 +67,47 @@ exit 0
 	assert.GreaterOrEqual(t, result.DurationMs, int64(0)) // Can be 0ms on fast CI systems
 }
 
+func TestScriptRunner_Run_ScrubsConnectorEnv(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Skipping shell script test on Windows")
+	}
+
+	t.Setenv("REC_API_KEY", "rec_secret")
+	t.Setenv("REC_LOG_LEVEL", "debug")
+	t.Setenv("DATABASE_PASSWORD", "hunter2")
+	t.Setenv("AWS_REGION", "us-east-1")
+	t.Setenv("KUBECONFIG", "/etc/kube/config")
+
+	tmpDir := t.TempDir()
+	scriptPath := filepath.Join(tmpDir, "env.sh")
+	err := os.WriteFile(scriptPath, []byte("#!/bin/bash\nenv\n"), 0755)
+	require.NoError(t, err)
+
+	runner := executor.NewScriptRunner([]string{tmpDir}, map[string]string{"GLOBAL_VAR": "global_value"})
+	action := &config.Action{
+		Script:     scriptPath,
+		Timeout:    5,
+		Env:        map[string]string{"ACTION_VAR": "action_value"},
+		InheritEnv: []string{"AWS_*", "KUBECONFIG", "REC_*"},
+	}
+
+	result := runner.Run(context.Background(), action, api.Event{}, map[string]interface{}{"service": "api"})
+	require.NoError(t, result.Error)
+
+	env := strings.Split(result.Stdout, "\n")
+	assert.Contains(t, env, "PATH="+os.Getenv("PATH"))
+	assert.Contains(t, env, "GLOBAL_VAR=global_value")
+	assert.Contains(t, env, "ACTION_VAR=action_value")
+	assert.Contains(t, env, "REC_PARAM_SERVICE=api")
+	assert.Contains(t, env, "AWS_REGION=us-east-1")
+	assert.Contains(t, env, "KUBECONFIG=/etc/kube/config")
+
+	// Variables that are not allowlisted, and connector variables even when they are, never reach the script
+	assert.NotContains(t, result.Stdout, "hunter2")
+	assert.NotContains(t, result.Stdout, "rec_secret")
+	assert.NotContains(t, env, "REC_LOG_LEVEL=debug")
+}
+
 func TestScriptRunner_Run_Failure(t *testing.T) {
 	if runtime.GOOS == "windows" {
 		t.Skip("Skipping shell script test on Windows")
@@ -83,13 +130,314 @@ exit 1
 		Timeout: 5,
 	}
 
-	result := runner.Run(context.Background(), action, nil)
+	result := runner.Run(context.Background(), action, api.Event{}, nil)
 
 	assert.Equal(t, 1, result.ExitCode)
 	assert.NotNil(t, result.Error)
 	assert.Contains(t, result.Stderr, "Script failed")
 }
 
+func TestScriptRunner_Run_TypedParameters(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Skipping shell script test on Windows")
+	}
+
+	tmpDir := t.TempDir()
+
+	scriptPath := filepath.Join(tmpDir, "params.sh")
+	scriptContent := `#!/bin/bash
+echo "count=$REC_PARAM_COUNT"
+echo "dry_run=$REC_PARAM_DRY_RUN"
+echo "json=$REC_PARAMS_JSON"
+`
+	err := os.WriteFile(scriptPath, []byte(scriptContent), 0755)
+	require.NoError(t, err)
+
+	runner := executor.NewScriptRunner([]string{tmpDir}, nil)
+	result := runner.Run(context.Background(), &config.Action{
+		Script:  scriptPath,
+		Timeout: 5,
+	}, api.Event{}, map[string]interface{}{
+		"count":   float64(3),
+		"dry_run": false,
+	})
+
+	require.NoError(t, result.Error)
+	assert.Contains(t, result.Stdout, "count=3\n")
+	assert.Contains(t, result.Stdout, "dry_run=false\n")
+	assert.Contains(t, result.Stdout, `json={"count":3,"dry_run":false}`)
+}
+
+func TestScriptRunner_Run_InputStdin(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Skipping shell script test on Windows")
+	}
+
+	tmpDir := t.TempDir()
+
+	scriptPath := filepath.Join(tmpDir, "stdin.sh")
+	err := os.WriteFile(scriptPath, []byte("#!/bin/bash\ncat\n"), 0755)
+	require.NoError(t, err)
+
+	runner := executor.NewScriptRunner([]string{tmpDir}, nil)
+	action := &config.Action{
+		ID:      "restart_service",
+		Name:    "Restart Service",
+		Type:    "script",
+		Script:  scriptPath,
+		Timeout: 5,
+		Input:   config.InputStdin,
+	}
+	event := api.Event{
+		ID:   "delivery-1",
+		Type: "action.triggered",
+		Data: map[string]interface{}{"note": "line one\nline two"},
+	}
+
+	result := runner.Run(context.Background(), action, event, map[string]interface{}{"replicas": float64(2)})
+	require.NoError(t, result.Error)
+
+	var input map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(result.Stdout), &input))
+	assert.Equal(t, map[string]interface{}{"replicas": float64(2)}, input["parameters"])
+	assert.Equal(t, map[string]interface{}{"id": "restart_service", "name": "Restart Service", "type": "script"}, input["action"])
+	eventInput, ok := input["event"].(map[string]interface{})
+	require.True(t, ok)
+	assert.Equal(t, "delivery-1", eventInput["id"])
+	assert.Equal(t, map[string]interface{}{"note": "line one\nline two"}, eventInput["data"])
+}
+
+func TestScriptRunner_Run_InputFile(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Skipping shell script test on Windows")
+	}
+
+	tmpDir := t.TempDir()
+
+	scriptPath := filepath.Join(tmpDir, "file.sh")
+	scriptContent := `#!/bin/bash
+echo "$REC_INPUT_FILE"
+cat "$REC_INPUT_FILE" >&2
+`
+	err := os.WriteFile(scriptPath, []byte(scriptContent), 0755)
+	require.NoError(t, err)
+
+	runner := executor.NewScriptRunner([]string{tmpDir}, nil)
+	action := &config.Action{
+		ID:      "alert.created",
+		Type:    "script",
+		Script:  scriptPath,
+		Timeout: 5,
+		Input:   config.InputFile,
+	}
+
+	result := runner.Run(context.Background(), action, api.Event{ID: "delivery-1"}, nil)
+	require.NoError(t, result.Error)
+
+	inputPath := strings.TrimSpace(result.Stdout)
+	require.NotEmpty(t, inputPath)
+	assert.Contains(t, result.Stderr, `"parameters":{}`)
+	assert.Contains(t, result.Stderr, `"id":"delivery-1"`)
+	assert.NoFileExists(t, inputPath, "Input file should be removed after the run")
+}
+
+func TestScriptRunner_Run_StructuredOutput(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Skipping shell script test on Windows")
+	}
+
+	tmpDir := t.TempDir()
+
+	scriptPath := filepath.Join(tmpDir, "output.sh")
+	scriptContent := `#!/bin/bash
+echo "lots of log lines"
+cat > "$REC_OUTPUT_FILE" <<'EOF'
+{"summary": "restarted 3 pods", "links": [{"title": "Dashboard", "url": "https://grafana.example.com"}], "outputs": {"pods": 3}, "status_override": "failed"}
+EOF
+`
+	err := os.WriteFile(scriptPath, []byte(scriptContent), 0755)
+	require.NoError(t, err)
+
+	runner := executor.NewScriptRunner([]string{tmpDir}, nil)
+	result := runner.Run(context.Background(), &config.Action{Script: scriptPath, Timeout: 5}, api.Event{}, nil)
+
+	require.NoError(t, result.Error)
+	require.NotNil(t, result.Output)
+	assert.Equal(t, "restarted 3 pods", result.Output.Summary)
+	assert.Equal(t, []api.ExecutionLink{{Title: "Dashboard", URL: "https://grafana.example.com"}}, result.Output.Links)
+	assert.Equal(t, map[string]interface{}{"pods": float64(3)}, result.Output.Outputs)
+	assert.True(t, result.Failed(), "status_override should win over a zero exit code")
+}
+
+func TestScriptRunner_Run_StructuredOutputInvalid(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Skipping shell script test on Windows")
+	}
+
+	tmpDir := t.TempDir()
+
+	scriptPath := filepath.Join(tmpDir, "output.sh")
+	err := os.WriteFile(scriptPath, []byte("#!/bin/bash\necho 'not json' > \"$REC_OUTPUT_FILE\"\n"), 0755)
+	require.NoError(t, err)
+
+	runner := executor.NewScriptRunner([]string{tmpDir}, nil)
+	result := runner.Run(context.Background(), &config.Action{Script: scriptPath, Timeout: 5}, api.Event{}, nil)
+
+	require.NoError(t, result.Error, "An invalid output file should not fail the script")
+	assert.Nil(t, result.Output)
+	assert.Contains(t, result.Stderr, "rec: ignoring REC_OUTPUT_FILE: output file is not valid JSON")
+}
+
+func TestScriptRunner_Run_OutputTruncated(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Skipping shell script test on Windows")
+	}
+
+	tmpDir := t.TempDir()
+	overflowDir := filepath.Join(tmpDir, "overflow")
+
+	scriptPath := filepath.Join(tmpDir, "chatty.sh")
+	scriptContent := `#!/bin/bash
+echo "first line"
+for i in $(seq 1 5000); do echo "noise $i"; done
+echo "last line"
+echo "small stderr" >&2
+`
+	err := os.WriteFile(scriptPath, []byte(scriptContent), 0755)
+	require.NoError(t, err)
+
+	runner := executor.NewScriptRunner([]string{tmpDir}, nil)
+	runner.SetOutputLimits(executor.OutputLimits{OverflowDir: overflowDir, MaxBytes: 100000})
+	action := &config.Action{ID: "chatty", Script: scriptPath, Timeout: 10, MaxOutput: 200}
+	result := runner.Run(context.Background(), action, api.Event{ID: "delivery-1"}, nil)
+
+	require.NoError(t, result.Error)
+	assert.True(t, result.Truncated)
+	assert.True(t, strings.HasPrefix(result.Stdout, "first line\n"))
+	assert.True(t, strings.HasSuffix(result.Stdout, "last line\n"))
+	assert.Contains(t, result.Stdout, "bytes truncated, full output in "+overflowDir)
+	assert.Less(t, len(result.Stdout), 400, "max_output_bytes should override the global limit")
+	assert.Equal(t, "small stderr\n", result.Stderr)
+
+	// Only the truncated stream is kept on disk
+	files, err := filepath.Glob(filepath.Join(overflowDir, "delivery-1-*"))
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	assert.True(t, strings.HasSuffix(files[0], "-stdout.log"))
+	full, err := os.ReadFile(files[0])
+	require.NoError(t, err)
+	assert.Contains(t, string(full), "noise 2500\n")
+}
+
+func TestScriptRunner_Run_OutputFilesKeepFullOutput(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Skipping shell script test on Windows")
+	}
+
+	tmpDir := t.TempDir()
+	scriptPath := filepath.Join(tmpDir, "chatty.sh")
+	scriptContent := `#!/bin/bash
+for i in $(seq 1 1000); do echo "out $i"; echo "err $i" >&2; done
+`
+	err := os.WriteFile(scriptPath, []byte(scriptContent), 0755)
+	require.NoError(t, err)
+
+	stdoutPath := filepath.Join(tmpDir, "stdout.txt")
+	stderrPath := filepath.Join(tmpDir, "stderr.txt")
+
+	runner := executor.NewScriptRunner([]string{tmpDir}, nil)
+	action := &config.Action{
+		ID:        "chatty",
+		Script:    scriptPath,
+		Timeout:   10,
+		MaxOutput: 200,
+		Stdout:    stdoutPath,
+		Stderr:    stderrPath,
+	}
+	result := runner.Run(context.Background(), action, api.Event{ID: "delivery-1"}, nil)
+
+	require.NoError(t, result.Error)
+	assert.True(t, result.Truncated)
+	assert.Less(t, len(result.Stdout), 400)
+
+	// The redirect files are not limited by max_output_bytes
+	stdout, err := os.ReadFile(stdoutPath)
+	require.NoError(t, err)
+	stderr, err := os.ReadFile(stderrPath)
+	require.NoError(t, err)
+	assert.Equal(t, 1000, strings.Count(string(stdout), "\n"))
+	assert.Contains(t, string(stdout), "out 500\n")
+	assert.NotContains(t, string(stdout), "truncated")
+	assert.Equal(t, 1000, strings.Count(string(stderr), "\n"))
+	assert.True(t, strings.HasPrefix(string(stderr), "err 1\n"))
+}
+
+// progressRecorder collects progress updates sent by a ScriptRunner
+type progressRecorder struct {
+	mu      sync.Mutex
+	updates []reporter.ScriptResult
+	ids     []string
+}
+
+func (p *progressRecorder) ReportProgress(_ context.Context, deliveryID string, result reporter.ScriptResult) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.updates = append(p.updates, result)
+	p.ids = append(p.ids, deliveryID)
+	return nil
+}
+
+func (p *progressRecorder) snapshot() ([]reporter.ScriptResult, []string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return slices.Clone(p.updates), slices.Clone(p.ids)
+}
+
+func TestScriptRunner_Run_Progress(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Skipping shell script test on Windows")
+	}
+
+	tmpDir := t.TempDir()
+
+	scriptPath := filepath.Join(tmpDir, "failover.sh")
+	scriptContent := `#!/bin/bash
+echo "promoting replica"
+sleep 0.3
+echo "updating DNS"
+sleep 0.3
+echo "done"
+`
+	err := os.WriteFile(scriptPath, []byte(scriptContent), 0755)
+	require.NoError(t, err)
+
+	recorder := &progressRecorder{}
+	runner := executor.NewScriptRunner([]string{tmpDir}, nil)
+	runner.SetProgressReporter(recorder, 50*time.Millisecond)
+
+	action := &config.Action{Script: scriptPath, Timeout: 5, Progress: true}
+	result := runner.Run(context.Background(), action, api.Event{ID: "delivery-1"}, nil)
+	require.NoError(t, result.Error)
+	assert.Equal(t, "promoting replica\nupdating DNS\ndone\n", result.Stdout)
+
+	updates, ids := recorder.snapshot()
+	require.GreaterOrEqual(t, len(updates), 2, "Output should be sent while the script runs")
+	assert.Equal(t, "promoting replica\n", updates[0].Stdout)
+	assert.Equal(t, "promoting replica\nupdating DNS\n", updates[1].Stdout, "Each update carries all output so far")
+	for _, id := range ids {
+		assert.Equal(t, "delivery-1", id)
+	}
+
+	// Without progress: true nothing is streamed
+	quiet := &progressRecorder{}
+	runner.SetProgressReporter(quiet, 50*time.Millisecond)
+	action.Progress = false
+	result = runner.Run(context.Background(), action, api.Event{ID: "delivery-2"}, nil)
+	require.NoError(t, result.Error)
+	updates, _ = quiet.snapshot()
+	assert.Empty(t, updates)
+}
+
 func TestScriptRunner_Run_Timeout(t *testing.T) {
 	if runtime.GOOS == "windows" {
 		t.Skip("Skipping shell script test on Windows")
@@ -113,7 +461,7 @@ echo "This should not appear"
 	}
 
 	start := time.Now()
-	result := runner.Run(context.Background(), action, nil)
+	result := runner.Run(context.Background(), action, api.Event{}, nil)
 	duration := time.Since(start)
 
 	assert.Equal(t, -1, result.ExitCode)
@@ -124,6 +472,40 @@ echo "This should not appear"
 	assert.Greater(t, duration, 500*time.Millisecond, "Should take at least the timeout duration")
 }
 
+func TestScriptRunner_Run_CanceledWithCause(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Skipping shell script test on Windows")
+	}
+
+	tmpDir := t.TempDir()
+
+	scriptPath := filepath.Join(tmpDir, "slow.sh")
+	err := os.WriteFile(scriptPath, []byte("#!/bin/bash\nsleep 5\n"), 0755)
+	require.NoError(t, err)
+
+	runner := executor.NewScriptRunner([]string{tmpDir}, nil)
+	action := &config.Action{
+		Script:  scriptPath,
+		Timeout: 30,
+	}
+
+	shutdownErr := errors.New("canceled by connector shutdown")
+	ctx, cancel := context.WithCancelCause(context.Background())
+	go func() {
+		time.Sleep(200 * time.Millisecond)
+		cancel(shutdownErr)
+	}()
+
+	start := time.Now()
+	result := runner.Run(ctx, action, api.Event{}, nil)
+
+	assert.Less(t, time.Since(start), 4*time.Second, "Script should be killed when the context is canceled")
+	assert.Equal(t, -1, result.ExitCode)
+	require.Error(t, result.Error)
+	assert.ErrorIs(t, result.Error, shutdownErr)
+	assert.Contains(t, result.Error.Error(), "script canceled")
+}
+
 func TestScriptRunner_PathWhitelist(t *testing.T) {
 	tmpDir := t.TempDir()
 	allowedDir := filepath.Join(tmpDir, "allowed")
@@ -176,7 +558,7 @@ echo "test"
 				Timeout: 5,
 			}
 
-			result := runner.Run(context.Background(), action, nil)
+			result := runner.Run(context.Background(), action, api.Event{}, nil)
 
 			if tt.expectSuccess {
 				assert.Equal(t, 0, result.ExitCode)
@@ -220,12 +602,12 @@ echo "ACTION=$ACTION_ENV"
 		},
 	}
 
-	params := map[string]string{
+	params := map[string]interface{}{
 		"host": "localhost",
 		"port": "8080",
 	}
 
-	result := runner.Run(context.Background(), action, params)
+	result := runner.Run(context.Background(), action, api.Event{}, params)
 
 	assert.Equal(t, 0, result.ExitCode)
 	assert.Contains(t, result.Stdout, "HOST=localhost")
@@ -278,7 +660,7 @@ func TestScriptRunner_InterpreterDetection(t *testing.T) {
 				Timeout: 5,
 			}
 
-			result := runner.Run(context.Background(), action, nil)
+			result := runner.Run(context.Background(), action, api.Event{}, nil)
 
 			// We just check that the script executed without path errors
 			// Actual execution might fail if interpreter not available
@@ -297,7 +679,7 @@ func TestScriptRunner_NonExistentScript(t *testing.T) {
 		Timeout: 5,
 	}
 
-	result := runner.Run(context.Background(), action, nil)
+	result := runner.Run(context.Background(), action, api.Event{}, nil)
 
 	assert.Equal(t, 1, result.ExitCode)
 	assert.NotNil(t, result.Error)
@@ -333,7 +715,7 @@ func TestScriptRunner_PythonScript(t *testing.T) {
 	result := runner.Run(context.Background(), &config.Action{
 		Script:  scriptPath,
 		Timeout: 5,
-	}, map[string]string{"message": "Hello from Python"})
+	}, api.Event{}, map[string]interface{}{"message": "Hello from Python"})
 
 	assert.Equal(t, 0, result.ExitCode)
 	assert.Nil(t, result.Error)
@@ -355,7 +737,7 @@ func TestScriptRunner_NodeScript(t *testing.T) {
 	result := runner.Run(context.Background(), &config.Action{
 		Script:  scriptPath,
 		Timeout: 5,
-	}, map[string]string{"message": "Hello from Node"})
+	}, api.Event{}, map[string]interface{}{"message": "Hello from Node"})
 
 	assert.Equal(t, 0, result.ExitCode)
 	assert.Nil(t, result.Error)
@@ -377,7 +759,7 @@ func TestScriptRunner_RubyScript(t *testing.T) {
 	result := runner.Run(context.Background(), &config.Action{
 		Script:  scriptPath,
 		Timeout: 5,
-	}, map[string]string{"message": "Hello from Ruby"})
+	}, api.Event{}, map[string]interface{}{"message": "Hello from Ruby"})
 
 	assert.Equal(t, 0, result.ExitCode)
 	assert.Nil(t, result.Error)
@@ -396,7 +778,7 @@ func TestScriptRunner_GoScript(t *testing.T) {
 	result := runner.Run(context.Background(), &config.Action{
 		Script:  scriptPath,
 		Timeout: 30, // Go compilation + execution is slower on Windows CI
-	}, map[string]string{"message": "Hello from Go"})
+	}, api.Event{}, map[string]interface{}{"message": "Hello from Go"})
 
 	assert.Equal(t, 0, result.ExitCode)
 	assert.Nil(t, result.Error)
@@ -415,7 +797,7 @@ func TestScriptRunner_BashScript(t *testing.T) {
 	result := runner.Run(context.Background(), &config.Action{
 		Script:  scriptPath,
 		Timeout: 5,
-	}, map[string]string{"message": "Hello from Bash"})
+	}, api.Event{}, map[string]interface{}{"message": "Hello from Bash"})
 
 	assert.Equal(t, 0, result.ExitCode)
 	assert.Nil(t, result.Error)
@@ -434,7 +816,7 @@ func TestScriptRunner_ShebangScript(t *testing.T) {
 	result := runner.Run(context.Background(), &config.Action{
 		Script:  scriptPath,
 		Timeout: 5,
-	}, map[string]string{"message": "Hello from Shebang"})
+	}, api.Event{}, map[string]interface{}{"message": "Hello from Shebang"})
 
 	assert.Equal(t, 0, result.ExitCode)
 	assert.Nil(t, result.Error)
@@ -474,7 +856,7 @@ func TestScriptRunner_SetGitManager(t *testing.T) {
 	}
 
 	// This should attempt to lock the git repo
-	result := runner.Run(context.Background(), action, map[string]string{"message": "test"})
+	result := runner.Run(context.Background(), action, api.Event{}, map[string]interface{}{"message": "test"})
 
 	// Should have attempted to lock
 	assert.True(t, mockGitMgr.rlockCalled)
@@ -524,9 +906,9 @@ func TestScriptRunner_DefaultTimeout(t *testing.T) {
 		Timeout:    0, // Should use default 300s
 	}
 
-	params := map[string]string{}
+	params := map[string]interface{}{}
 
-	result := runner.Run(context.Background(), action, params)
+	result := runner.Run(context.Background(), action, api.Event{}, params)
 
 	require.NoError(t, result.Error)
 	assert.Equal(t, 0, result.ExitCode)
@@ -562,7 +944,7 @@ exit 0
 		Timeout: 5,
 	}
 
-	result := runner.Run(context.Background(), action, map[string]string{})
+	result := runner.Run(context.Background(), action, api.Event{}, map[string]interface{}{})
 
 	require.NoError(t, result.Error)
 	assert.Contains(t, result.Stdout, "--verbose")
@@ -595,7 +977,7 @@ exit 1
 		Timeout:    5,
 	}
 
-	result := runner.Run(context.Background(), action, map[string]string{})
+	result := runner.Run(context.Background(), action, api.Event{}, map[string]interface{}{})
 
 	assert.NotNil(t, result.Error)
 	assert.Equal(t, 1, result.ExitCode)
@@ -623,7 +1005,7 @@ func TestScriptRunner_OutputFileWriteError(t *testing.T) {
 		Timeout:    5,
 	}
 
-	result := runner.Run(context.Background(), action, map[string]string{})
+	result := runner.Run(context.Background(), action, api.Event{}, map[string]interface{}{})
 
 	// Script succeeds but file writes fail (logged as warnings)
 	require.NoError(t, result.Error)
@@ -647,7 +1029,7 @@ func TestScriptRunner_PowerShellScript(t *testing.T) {
 		Timeout:    5,
 	}
 
-	result := runner.Run(context.Background(), action, map[string]string{})
+	result := runner.Run(context.Background(), action, api.Event{}, map[string]interface{}{})
 
 	// PowerShell Core (pwsh/powershell) can be installed on any OS
 	// Test passes if:
@@ -688,7 +1070,7 @@ func TestScriptRunner_NoAllowedPaths(t *testing.T) {
 		Timeout:    5,
 	}
 
-	result := runner.Run(context.Background(), action, map[string]string{})
+	result := runner.Run(context.Background(), action, api.Event{}, map[string]interface{}{})
 
 	require.NoError(t, result.Error)
 	assert.Equal(t, 0, result.ExitCode)
@@ -723,7 +1105,7 @@ func TestScriptRunner_GitLockFailure(t *testing.T) {
 		Timeout: 5,
 	}
 
-	result := runner.Run(context.Background(), action, map[string]string{})
+	result := runner.Run(context.Background(), action, api.Event{}, map[string]interface{}{})
 
 	// Should fail due to lock acquisition error
 	assert.NotNil(t, result.Error)
@@ -759,7 +1141,7 @@ func TestScriptRunner_GitActionSuccess(t *testing.T) {
 		Timeout: 5,
 	}
 
-	result := runner.Run(context.Background(), action, map[string]string{})
+	result := runner.Run(context.Background(), action, api.Event{}, map[string]interface{}{})
 
 	// Should succeed
 	require.NoError(t, result.Error)
@@ -793,7 +1175,7 @@ exit 0
 		Timeout:    5,
 	}
 
-	result := runner.Run(context.Background(), action, map[string]string{})
+	result := runner.Run(context.Background(), action, api.Event{}, map[string]interface{}{})
 
 	require.NoError(t, result.Error)
 	assert.Equal(t, 0, result.ExitCode)
@@ -838,7 +1220,7 @@ func TestScriptRunner_IsAllowedPath_AbsErrorOnScriptPath(t *testing.T) {
 		Timeout:    5,
 	}
 
-	result := runner.Run(context.Background(), action, map[string]string{})
+	result := runner.Run(context.Background(), action, api.Event{}, map[string]interface{}{})
 
 	// Should fail because isAllowedPath returns false when Abs fails
 	assert.NotNil(t, result.Error)
@@ -890,7 +1272,7 @@ func TestScriptRunner_IsAllowedPath_AbsErrorOnAllowedPath(t *testing.T) {
 		Timeout:    5,
 	}
 
-	result := runner.Run(context.Background(), action, map[string]string{})
+	result := runner.Run(context.Background(), action, api.Event{}, map[string]interface{}{})
 
 	// Should succeed because second allowedPath is valid absolute path
 	require.NoError(t, result.Error)
@@ -931,7 +1313,7 @@ sys.exit(0)
 		Timeout:    5,
 	}
 
-	result := runner.Run(context.Background(), action, map[string]string{})
+	result := runner.Run(context.Background(), action, api.Event{}, map[string]interface{}{})
 
 	// On systems without python3, should use 'python' fallback
 	// Result depends on whether 'python' is available
@@ -975,7 +1357,7 @@ func TestScriptRunner_PathNotAllowed_SinglePath(t *testing.T) {
 		Timeout:    5,
 	}
 
-	result := runner.Run(context.Background(), action, map[string]string{})
+	result := runner.Run(context.Background(), action, api.Event{}, map[string]interface{}{})
 
 	// Verify error message contains helpful information
 	require.NotNil(t, result.Error)
@@ -1023,7 +1405,7 @@ func TestScriptRunner_PathNotAllowed_MultiplePaths(t *testing.T) {
 		Timeout:    5,
 	}
 
-	result := runner.Run(context.Background(), action, map[string]string{})
+	result := runner.Run(context.Background(), action, api.Event{}, map[string]interface{}{})
 
 	// Verify error message shows all allowed paths
 	require.NotNil(t, result.Error)
@@ -1062,7 +1444,7 @@ func TestScriptRunner_PathAllowed_ErrorMessageNotShown(t *testing.T) {
 		Timeout:    5,
 	}
 
-	result := runner.Run(context.Background(), action, map[string]string{})
+	result := runner.Run(context.Background(), action, api.Event{}, map[string]interface{}{})
 
 	// Should succeed without any path-related error
 	require.NoError(t, result.Error)
@@ -1090,7 +1472,7 @@ func TestScriptRunner_NoAllowedPaths_ErrorMessageFormat(t *testing.T) {
 		Timeout:    5,
 	}
 
-	result := runner.Run(context.Background(), action, map[string]string{})
+	result := runner.Run(context.Background(), action, api.Event{}, map[string]interface{}{})
 
 	// With empty allowed paths, all paths are allowed, so we get "script not found" error instead
 	require.NotNil(t, result.Error)
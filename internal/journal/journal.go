@@ -0,0 +1,381 @@
+package journal
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/rootly/edge-connector/internal/api"
+	"github.com/rootly/edge-connector/internal/config"
+	"github.com/rootly/edge-connector/internal/reporter"
+)
+
+// fileName is the name of the journal file inside the state directory
+const fileName = "journal.jsonl"
+
+// Journal record operations
+const (
+	opClaimed  = "claimed"
+	opStarted  = "started"
+	opFinished = "finished"
+)
+
+// compactThreshold is the number of appended records after which the file is rewritten
+// with only the deliveries still in flight
+const compactThreshold = 1000
+
+// interruptedError is reported for deliveries that were running when the connector died
+const interruptedError = "interrupted by connector restart"
+
+// Reporter reports execution results back to the Rootly API
+type Reporter interface {
+	Report(ctx context.Context, deliveryID, actionName, actionUUID string, result reporter.ScriptResult) error
+}
+
+// Entry is a claimed delivery that has not finished yet
+type Entry struct {
+	ClaimedAt  time.Time
+	StartedAt  time.Time  // Zero if the action never started
+	Event      *api.Event // Kept only for idempotent actions, which are re-run on recovery
+	DeliveryID string
+	EventID    string
+	ActionUUID string // Rootly action UUID the delivery was reported against
+	ActionID   string // Set once the action starts
+	Idempotent bool   // Action is safe to re-run after an interruption
+}
+
+// record is a single line in the journal file
+// Only the IDs needed to report an interrupted delivery are stored, plus the event of idempotent actions
+type record struct {
+	Time       time.Time  `json:"time"`
+	Event      *api.Event `json:"event,omitempty"`
+	Op         string     `json:"op"`
+	DeliveryID string     `json:"delivery_id"`
+	EventID    string     `json:"event_id,omitempty"`
+	ActionUUID string     `json:"action_uuid,omitempty"`
+	ActionID   string     `json:"action_id,omitempty"`
+	Idempotent bool       `json:"idempotent,omitempty"`
+}
+
+// Journal is an append-only log of claimed deliveries stored in the state directory
+// Deliveries still in the journal on startup were interrupted by a crash or kill. Records are not
+// fsynced: they survive the process dying, which is what the journal covers, but not a host crash
+type Journal struct {
+	file     *os.File
+	pending  map[string]*Entry
+	path     string
+	mu       sync.Mutex
+	appended int
+}
+
+// Open opens (or creates) the journal in the given state directory and loads unfinished deliveries
+func Open(dir string) (*Journal, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create state directory: %w", err)
+	}
+
+	j := &Journal{
+		path:    filepath.Join(dir, fileName),
+		pending: make(map[string]*Entry),
+	}
+
+	if err := j.load(); err != nil {
+		return nil, err
+	}
+
+	// Start from a compact file holding only unfinished deliveries
+	if err := j.compactLocked(); err != nil {
+		return nil, err
+	}
+
+	return j, nil
+}
+
+// Close closes the journal file
+func (j *Journal) Close() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.file == nil {
+		return nil
+	}
+	err := j.file.Close()
+	j.file = nil
+	return err
+}
+
+// Claimed records that a delivery was marked running and handed to the worker pool
+func (j *Journal) Claimed(event api.Event) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	entry := &Entry{
+		DeliveryID: event.ID,
+		EventID:    event.EventID,
+		ClaimedAt:  time.Now(),
+	}
+	if event.Action != nil {
+		entry.ActionUUID = event.Action.ID
+	}
+	j.pending[event.ID] = entry
+	j.appendLocked(claimedRecord(entry))
+}
+
+// Started records that the action for a claimed delivery began executing
+// The event is kept only if the action is idempotent, since only those deliveries are re-run
+func (j *Journal) Started(event api.Event, action *config.Action) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	entry, ok := j.pending[event.ID]
+	if !ok {
+		return
+	}
+
+	entry.StartedAt = time.Now()
+	entry.ActionID = action.ID
+	entry.Idempotent = action.Idempotent
+	if action.Idempotent {
+		entry.Event = &event
+	}
+	j.appendLocked(startedRecord(entry))
+}
+
+// Finished records that a delivery's result was reported and it no longer needs recovery
+func (j *Journal) Finished(deliveryID string) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if _, ok := j.pending[deliveryID]; !ok {
+		return
+	}
+
+	delete(j.pending, deliveryID)
+	j.appendLocked(record{Op: opFinished, DeliveryID: deliveryID, Time: time.Now()})
+
+	if j.appended >= compactThreshold {
+		if err := j.compactLocked(); err != nil {
+			log.WithError(err).Error("Failed to compact journal")
+		}
+	}
+}
+
+// Pending returns the deliveries that have been claimed but not finished, oldest first
+func (j *Journal) Pending() []Entry {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	entries := make([]Entry, 0, len(j.pending))
+	for _, entry := range j.pending {
+		entries = append(entries, *entry)
+	}
+	sort.Slice(entries, func(a, b int) bool {
+		return entries[a].ClaimedAt.Before(entries[b].ClaimedAt)
+	})
+	return entries
+}
+
+// Recover resolves deliveries left over from a previous run
+// Deliveries of idempotent actions are re-submitted; the rest, including those whose action never
+// started, are reported as failed so they don't stay running on the backend
+func (j *Journal) Recover(ctx context.Context, rep Reporter, submit func(api.Event) bool) {
+	entries := j.Pending()
+	if len(entries) == 0 {
+		return
+	}
+
+	log.WithField("count", len(entries)).Warn("Recovering deliveries interrupted by connector restart")
+
+	for _, entry := range entries {
+		fields := log.Fields{
+			"delivery_id": entry.DeliveryID,
+			"event_id":    entry.EventID,
+			"action_id":   entry.ActionID,
+			"claimed_at":  entry.ClaimedAt.Format(time.RFC3339),
+		}
+
+		if entry.Idempotent && entry.Event != nil {
+			if submit(*entry.Event) {
+				log.WithFields(fields).Info("Re-running interrupted delivery")
+				continue
+			}
+			log.WithFields(fields).Warn("Could not re-queue interrupted delivery, reporting it as failed")
+		}
+
+		j.reportInterrupted(ctx, rep, entry)
+	}
+}
+
+// reportInterrupted reports an interrupted delivery as failed and removes it from the journal
+func (j *Journal) reportInterrupted(ctx context.Context, rep Reporter, entry Entry) {
+	actionName := entry.ActionID
+	if actionName == "" {
+		actionName = "none"
+	}
+	result := reporter.ScriptResult{
+		ExitCode: 1,
+		Error:    errors.New(interruptedError),
+		Stderr:   fmt.Sprintf("Delivery was %s at %s", interruptedError, entry.ClaimedAt.UTC().Format(time.RFC3339)),
+	}
+	if !entry.StartedAt.IsZero() {
+		result.Stderr = fmt.Sprintf("Action started at %s was %s", entry.StartedAt.UTC().Format(time.RFC3339), interruptedError)
+	}
+
+	if err := rep.Report(ctx, entry.DeliveryID, actionName, entry.ActionUUID, result); err != nil {
+		log.WithError(err).WithField("delivery_id", entry.DeliveryID).Error("Failed to report interrupted delivery")
+		return
+	}
+
+	log.WithFields(log.Fields{
+		"delivery_id": entry.DeliveryID,
+		"action_id":   entry.ActionID,
+	}).Warn("Reported interrupted delivery as failed")
+	j.Finished(entry.DeliveryID)
+}
+
+// load replays the journal file into the pending map, skipping lines that cannot be parsed
+func (j *Journal) load() error {
+	data, err := os.ReadFile(j.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to read journal file: %w", err)
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var rec record
+		if err := json.Unmarshal(line, &rec); err != nil {
+			// A torn final line is expected if the process died mid-write
+			log.WithError(err).WithField("path", j.path).Warn("Skipping corrupt journal record")
+			continue
+		}
+		j.apply(rec)
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to parse journal file: %w", err)
+	}
+
+	return nil
+}
+
+// apply updates the pending map with a single record
+func (j *Journal) apply(rec record) {
+	switch rec.Op {
+	case opClaimed:
+		j.pending[rec.DeliveryID] = &Entry{
+			DeliveryID: rec.DeliveryID,
+			EventID:    rec.EventID,
+			ActionUUID: rec.ActionUUID,
+			ClaimedAt:  rec.Time,
+		}
+	case opStarted:
+		if entry, ok := j.pending[rec.DeliveryID]; ok {
+			entry.StartedAt = rec.Time
+			entry.ActionID = rec.ActionID
+			entry.Idempotent = rec.Idempotent
+			entry.Event = rec.Event
+		}
+	case opFinished:
+		delete(j.pending, rec.DeliveryID)
+	}
+}
+
+// claimedRecord returns the record for a delivery being claimed
+func claimedRecord(entry *Entry) record {
+	return record{
+		Op:         opClaimed,
+		DeliveryID: entry.DeliveryID,
+		EventID:    entry.EventID,
+		ActionUUID: entry.ActionUUID,
+		Time:       entry.ClaimedAt,
+	}
+}
+
+// startedRecord returns the record for a delivery whose action started
+func startedRecord(entry *Entry) record {
+	return record{
+		Op:         opStarted,
+		DeliveryID: entry.DeliveryID,
+		ActionID:   entry.ActionID,
+		Idempotent: entry.Idempotent,
+		Event:      entry.Event,
+		Time:       entry.StartedAt,
+	}
+}
+
+// appendLocked writes a record to the journal file
+// Caller must hold j.mu
+func (j *Journal) appendLocked(rec record) {
+	if j.file == nil {
+		return
+	}
+
+	line, err := json.Marshal(rec)
+	if err != nil {
+		log.WithError(err).WithField("delivery_id", rec.DeliveryID).Error("Failed to encode journal record")
+		return
+	}
+	line = append(line, '\n')
+
+	if _, err := j.file.Write(line); err != nil {
+		log.WithError(err).WithField("delivery_id", rec.DeliveryID).Error("Failed to write journal record")
+		return
+	}
+	j.appended++
+}
+
+// compactLocked rewrites the journal with only unfinished deliveries and reopens it for appending
+// Caller must hold j.mu (or be the only goroutine with access)
+func (j *Journal) compactLocked() error {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	for _, entry := range j.pending {
+		if err := encoder.Encode(claimedRecord(entry)); err != nil {
+			return fmt.Errorf("failed to encode journal record: %w", err)
+		}
+		if !entry.StartedAt.IsZero() {
+			if err := encoder.Encode(startedRecord(entry)); err != nil {
+				return fmt.Errorf("failed to encode journal record: %w", err)
+			}
+		}
+	}
+
+	tmpPath := j.path + ".tmp"
+	if err := os.WriteFile(tmpPath, buf.Bytes(), 0o600); err != nil {
+		return fmt.Errorf("failed to write journal file: %w", err)
+	}
+
+	if err := os.Rename(tmpPath, j.path); err != nil {
+		return fmt.Errorf("failed to replace journal file: %w", err)
+	}
+
+	file, err := os.OpenFile(j.path, os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open journal file: %w", err)
+	}
+	if j.file != nil {
+		if err := j.file.Close(); err != nil {
+			log.WithError(err).Warn("Failed to close previous journal file")
+		}
+	}
+	j.file = file
+	j.appended = 0
+
+	return nil
+}
@@ -0,0 +1,222 @@
+package journal_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rootly/edge-connector/internal/api"
+	"github.com/rootly/edge-connector/internal/config"
+	"github.com/rootly/edge-connector/internal/journal"
+	"github.com/rootly/edge-connector/internal/reporter"
+)
+
+type reportCall struct {
+	deliveryID string
+	actionName string
+	actionUUID string
+	result     reporter.ScriptResult
+}
+
+type mockReporter struct {
+	err   error
+	calls []reportCall
+}
+
+func (m *mockReporter) Report(ctx context.Context, deliveryID, actionName, actionUUID string, result reporter.ScriptResult) error {
+	m.calls = append(m.calls, reportCall{deliveryID, actionName, actionUUID, result})
+	return m.err
+}
+
+func testEvent(id string) api.Event {
+	return api.Event{
+		ID:      id,
+		EventID: "event-" + id,
+		Type:    "action.triggered",
+		Action:  &api.ActionMetadata{ID: "uuid-" + id, Slug: "restart_service"},
+		Data:    map[string]interface{}{"service": "api"},
+	}
+}
+
+func TestJournal_FinishedDeliveriesAreNotPending(t *testing.T) {
+	dir := t.TempDir()
+
+	j, err := journal.Open(dir)
+	require.NoError(t, err)
+
+	j.Claimed(testEvent("delivery-1"))
+	j.Claimed(testEvent("delivery-2"))
+	j.Started(testEvent("delivery-1"), &config.Action{ID: "restart_service"})
+	j.Finished("delivery-1")
+	require.NoError(t, j.Close())
+
+	reopened, err := journal.Open(dir)
+	require.NoError(t, err)
+	defer reopened.Close()
+
+	pending := reopened.Pending()
+	require.Len(t, pending, 1)
+	assert.Equal(t, "delivery-2", pending[0].DeliveryID)
+	assert.Equal(t, "event-delivery-2", pending[0].EventID)
+	assert.Equal(t, "uuid-delivery-2", pending[0].ActionUUID)
+	assert.True(t, pending[0].StartedAt.IsZero())
+	assert.Nil(t, pending[0].Event)
+
+	data, err := os.ReadFile(filepath.Join(dir, "journal.jsonl"))
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), `"service"`, "Event payloads of non-idempotent actions should not be stored")
+}
+
+func TestJournal_RecoverReportsInterruptedDeliveryAsFailed(t *testing.T) {
+	dir := t.TempDir()
+
+	j, err := journal.Open(dir)
+	require.NoError(t, err)
+	j.Claimed(testEvent("delivery-1"))
+	j.Started(testEvent("delivery-1"), &config.Action{ID: "restart_service"})
+	require.NoError(t, j.Close())
+
+	// Simulate restart
+	reopened, err := journal.Open(dir)
+	require.NoError(t, err)
+	defer reopened.Close()
+
+	rep := &mockReporter{}
+	submitted := 0
+	reopened.Recover(context.Background(), rep, func(event api.Event) bool {
+		submitted++
+		return true
+	})
+
+	assert.Equal(t, 0, submitted, "Non-idempotent action that already started must not be re-run")
+	require.Len(t, rep.calls, 1)
+	call := rep.calls[0]
+	assert.Equal(t, "delivery-1", call.deliveryID)
+	assert.Equal(t, "restart_service", call.actionName)
+	assert.Equal(t, "uuid-delivery-1", call.actionUUID)
+	assert.Equal(t, 1, call.result.ExitCode)
+	require.Error(t, call.result.Error)
+	assert.Contains(t, call.result.Error.Error(), "interrupted by connector restart")
+	assert.Empty(t, reopened.Pending())
+}
+
+func TestJournal_RecoverRerunsOnlyIdempotentDeliveries(t *testing.T) {
+	dir := t.TempDir()
+
+	j, err := journal.Open(dir)
+	require.NoError(t, err)
+	j.Claimed(testEvent("delivery-idempotent"))
+	j.Started(testEvent("delivery-idempotent"), &config.Action{ID: "restart_service", Idempotent: true})
+	j.Claimed(testEvent("delivery-queued"))
+	require.NoError(t, j.Close())
+
+	reopened, err := journal.Open(dir)
+	require.NoError(t, err)
+	defer reopened.Close()
+
+	rep := &mockReporter{}
+	var submitted []api.Event
+	reopened.Recover(context.Background(), rep, func(event api.Event) bool {
+		submitted = append(submitted, event)
+		return true
+	})
+
+	require.Len(t, submitted, 1)
+	assert.Equal(t, "delivery-idempotent", submitted[0].ID)
+	assert.Equal(t, "api", submitted[0].Data["service"], "Idempotent deliveries are re-run with their full event")
+
+	// A delivery whose action never started is not known to be safe to re-run
+	require.Len(t, rep.calls, 1)
+	assert.Equal(t, "delivery-queued", rep.calls[0].deliveryID)
+	assert.Equal(t, "none", rep.calls[0].actionName)
+	assert.Equal(t, "uuid-delivery-queued", rep.calls[0].actionUUID)
+	assert.Contains(t, rep.calls[0].result.Error.Error(), "interrupted by connector restart")
+
+	// Re-submitted deliveries stay pending until the executor finishes them
+	pending := reopened.Pending()
+	require.Len(t, pending, 1)
+	assert.Equal(t, "delivery-idempotent", pending[0].DeliveryID)
+}
+
+func TestJournal_RecoverReportsWhenResubmitFails(t *testing.T) {
+	dir := t.TempDir()
+
+	j, err := journal.Open(dir)
+	require.NoError(t, err)
+	j.Claimed(testEvent("delivery-1"))
+	j.Started(testEvent("delivery-1"), &config.Action{ID: "restart_service", Idempotent: true})
+
+	rep := &mockReporter{}
+	j.Recover(context.Background(), rep, func(event api.Event) bool { return false })
+	defer j.Close()
+
+	require.Len(t, rep.calls, 1)
+	assert.Equal(t, "restart_service", rep.calls[0].actionName)
+	assert.Empty(t, j.Pending())
+}
+
+func TestJournal_RecoverKeepsEntryWhenReportFails(t *testing.T) {
+	j, err := journal.Open(t.TempDir())
+	require.NoError(t, err)
+	defer j.Close()
+
+	j.Claimed(testEvent("delivery-1"))
+	j.Started(testEvent("delivery-1"), &config.Action{ID: "restart_service"})
+
+	rep := &mockReporter{err: errors.New("api down")}
+	j.Recover(context.Background(), rep, func(event api.Event) bool { return true })
+
+	assert.Len(t, j.Pending(), 1, "Delivery should be retried on the next start")
+}
+
+func TestJournal_SkipsTornRecord(t *testing.T) {
+	dir := t.TempDir()
+
+	j, err := journal.Open(dir)
+	require.NoError(t, err)
+	j.Claimed(testEvent("delivery-1"))
+	require.NoError(t, j.Close())
+
+	// Simulate a crash in the middle of writing the next record
+	f, err := os.OpenFile(filepath.Join(dir, "journal.jsonl"), os.O_APPEND|os.O_WRONLY, 0o600)
+	require.NoError(t, err)
+	_, err = f.WriteString(`{"op":"claimed","delivery_id":"delivery-2","ev`)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	reopened, err := journal.Open(dir)
+	require.NoError(t, err)
+	defer reopened.Close()
+
+	pending := reopened.Pending()
+	require.Len(t, pending, 1)
+	assert.Equal(t, "delivery-1", pending[0].DeliveryID)
+}
+
+func TestJournal_CompactsAfterManyRecords(t *testing.T) {
+	dir := t.TempDir()
+
+	j, err := journal.Open(dir)
+	require.NoError(t, err)
+	defer j.Close()
+
+	for i := 0; i < 600; i++ {
+		event := testEvent("delivery")
+		j.Claimed(event)
+		j.Finished(event.ID)
+	}
+	j.Claimed(testEvent("delivery-last"))
+
+	info, err := os.Stat(filepath.Join(dir, "journal.jsonl"))
+	require.NoError(t, err)
+	assert.Less(t, info.Size(), int64(200*1024), "Journal should be compacted instead of growing without bound")
+
+	pending := j.Pending()
+	require.Len(t, pending, 1)
+	assert.Equal(t, "delivery-last", pending[0].DeliveryID)
+}
@@ -25,6 +25,10 @@ var (
 
 	EventsReceived prometheus.Counter
 
+	DeliveryStreamConnected prometheus.Gauge
+
+	PollInterval prometheus.Gauge
+
 	// Action execution metrics
 	ActionsExecuted *prometheus.CounterVec
 
@@ -35,11 +39,37 @@ var (
 
 	EventsRunning prometheus.Gauge
 
+	LeaseHeartbeats *prometheus.CounterVec
+
 	// Worker pool metrics
 	WorkerPoolSize prometheus.Gauge
 
 	WorkerPoolQueueSize prometheus.Gauge
 
+	EventsRejected *prometheus.CounterVec
+
+	EventsDuplicate *prometheus.CounterVec
+
+	// Action concurrency metrics (concurrency limits and mutex keys)
+	ActionConcurrencyLimited *prometheus.CounterVec
+
+	ActionConcurrencyWait *prometheus.HistogramVec
+
+	ActionsWaiting *prometheus.GaugeVec
+
+	// Rate limit and circuit breaker metrics
+	ActionsThrottled *prometheus.CounterVec
+
+	CircuitBreakerState *prometheus.GaugeVec
+
+	// Outbox metrics (execution reports waiting to be re-sent)
+	OutboxDepth prometheus.Gauge
+
+	OutboxOldestAge prometheus.Gauge
+
+	// Execution reports given up on and moved to the dead letter file (by reason)
+	OutboxDeadLetters *prometheus.CounterVec
+
 	// HTTP client metrics (for HTTP actions)
 	HTTPRequestsTotal *prometheus.CounterVec
 
@@ -66,7 +96,7 @@ func InitMetrics(customLabels map[string]string) {
 				Help:        "Total number of events polled from Rootly API",
 				ConstLabels: constLabels,
 			},
-			[]string{labelStatus}, // success, error
+			[]string{labelStatus}, // success, error, rate_limited
 		)
 
 		EventsReceived = prometheus.NewCounter(
@@ -77,6 +107,22 @@ func InitMetrics(customLabels map[string]string) {
 			},
 		)
 
+		DeliveryStreamConnected = prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Name:        "rec_delivery_stream_connected",
+				Help:        "Whether deliveries are received over the stream (1) or by polling while it is unavailable (0)",
+				ConstLabels: constLabels,
+			},
+		)
+
+		PollInterval = prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Name:        "rec_poll_interval_seconds",
+				Help:        "Current interval between polls, adapted to the delivery load",
+				ConstLabels: constLabels,
+			},
+		)
+
 		ActionsExecuted = prometheus.NewCounterVec(
 			prometheus.CounterOpts{
 				Name:        "rec_actions_executed_total",
@@ -105,6 +151,15 @@ func InitMetrics(customLabels map[string]string) {
 			[]string{labelStatus}, // success, error
 		)
 
+		LeaseHeartbeats = prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name:        "rec_lease_heartbeats_total",
+				Help:        "Total number of lease extensions sent for deliveries still running",
+				ConstLabels: constLabels,
+			},
+			[]string{labelStatus}, // success, error
+		)
+
 		EventsRunning = prometheus.NewGauge(
 			prometheus.GaugeOpts{
 				Name:        "rec_events_running",
@@ -129,6 +184,95 @@ func InitMetrics(customLabels map[string]string) {
 			},
 		)
 
+		EventsRejected = prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name:        "rec_events_rejected_total",
+				Help:        "Total number of events rejected because the worker queue was full",
+				ConstLabels: constLabels,
+			},
+			[]string{"outcome"}, // unclaimed (left for redelivery), reported_failed
+		)
+
+		EventsDuplicate = prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name:        "rec_events_duplicate_total",
+				Help:        "Total number of deliveries skipped as duplicates of a recently seen delivery or event",
+				ConstLabels: constLabels,
+			},
+			[]string{"reason"}, // delivery_id (redelivered), event_id (same event in a new delivery)
+		)
+
+		ActionConcurrencyLimited = prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name:        "rec_action_concurrency_limited_total",
+				Help:        "Total number of deliveries that hit an action's concurrency limit",
+				ConstLabels: constLabels,
+			},
+			[]string{"action_id", "outcome"}, // outcome: queued, rejected, dropped
+		)
+
+		ActionConcurrencyWait = prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:        "rec_action_concurrency_wait_seconds",
+				Help:        "Time queued deliveries waited for a free concurrency slot in seconds",
+				Buckets:     []float64{0.1, 0.5, 1, 5, 10, 30, 60, 120, 300},
+				ConstLabels: constLabels,
+			},
+			[]string{"action_id"},
+		)
+
+		ActionsWaiting = prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name:        "rec_actions_waiting",
+				Help:        "Current number of deliveries waiting for a free concurrency slot",
+				ConstLabels: constLabels,
+			},
+			[]string{"action_id"},
+		)
+
+		ActionsThrottled = prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name:        "rec_actions_throttled_total",
+				Help:        "Total number of deliveries failed by a rate limit or an open circuit breaker",
+				ConstLabels: constLabels,
+			},
+			[]string{"action_id", "reason"}, // rate_limited, host_rate_limited, circuit_open
+		)
+
+		CircuitBreakerState = prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name:        "rec_circuit_breaker_state",
+				Help:        "Circuit breaker state per destination host (0 = closed, 1 = half-open, 2 = open)",
+				ConstLabels: constLabels,
+			},
+			[]string{"host"},
+		)
+
+		OutboxDepth = prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Name:        "rec_outbox_depth",
+				Help:        "Number of execution reports waiting in the outbox to be re-sent",
+				ConstLabels: constLabels,
+			},
+		)
+
+		OutboxOldestAge = prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Name:        "rec_outbox_oldest_entry_age_seconds",
+				Help:        "Age in seconds of the oldest execution report waiting in the outbox",
+				ConstLabels: constLabels,
+			},
+		)
+
+		OutboxDeadLetters = prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name:        "rec_outbox_dead_letters_total",
+				Help:        "Execution reports moved from the outbox to the dead letter file (permanent_error or max_attempts)",
+				ConstLabels: constLabels,
+			},
+			[]string{"reason"},
+		)
+
 		HTTPRequestsTotal = prometheus.NewCounterVec(
 			prometheus.CounterOpts{
 				Name:        "rec_http_requests_total",
@@ -170,12 +314,25 @@ func InitMetrics(customLabels map[string]string) {
 		// Register all metrics with Prometheus default registry
 		prometheus.MustRegister(EventsPolled)
 		prometheus.MustRegister(EventsReceived)
+		prometheus.MustRegister(DeliveryStreamConnected)
+		prometheus.MustRegister(PollInterval)
 		prometheus.MustRegister(ActionsExecuted)
 		prometheus.MustRegister(ActionExecutionDuration)
 		prometheus.MustRegister(DeliveriesMarkedRunning)
+		prometheus.MustRegister(LeaseHeartbeats)
 		prometheus.MustRegister(EventsRunning)
 		prometheus.MustRegister(WorkerPoolSize)
 		prometheus.MustRegister(WorkerPoolQueueSize)
+		prometheus.MustRegister(EventsRejected)
+		prometheus.MustRegister(EventsDuplicate)
+		prometheus.MustRegister(ActionConcurrencyLimited)
+		prometheus.MustRegister(ActionConcurrencyWait)
+		prometheus.MustRegister(ActionsWaiting)
+		prometheus.MustRegister(ActionsThrottled)
+		prometheus.MustRegister(CircuitBreakerState)
+		prometheus.MustRegister(OutboxDepth)
+		prometheus.MustRegister(OutboxOldestAge)
+		prometheus.MustRegister(OutboxDeadLetters)
 		prometheus.MustRegister(HTTPRequestsTotal)
 		prometheus.MustRegister(HTTPRequestDuration)
 		prometheus.MustRegister(GitPullsTotal)
@@ -231,6 +388,107 @@ func RecordActionExecution(actionName, actionType, status string, duration time.
 	ActionExecutionDuration.WithLabelValues(actionName, actionType).Observe(duration.Seconds())
 }
 
+// RecordEventsRejected records events rejected due to worker queue backpressure
+func RecordEventsRejected(outcome string, count int) {
+	if EventsRejected == nil {
+		return // Metrics not initialized (disabled)
+	}
+	EventsRejected.WithLabelValues(outcome).Add(float64(count))
+}
+
+// RecordEventsDuplicate records a delivery skipped as a duplicate
+func RecordEventsDuplicate(reason string) {
+	if EventsDuplicate == nil {
+		return // Metrics not initialized (disabled)
+	}
+	EventsDuplicate.WithLabelValues(reason).Inc()
+}
+
+// RecordConcurrencyLimited records a delivery that hit an action's concurrency limit
+func RecordConcurrencyLimited(actionID, outcome string) {
+	if ActionConcurrencyLimited == nil {
+		return // Metrics not initialized (disabled)
+	}
+	ActionConcurrencyLimited.WithLabelValues(actionID, outcome).Inc()
+}
+
+// RecordConcurrencyWait records how long a queued delivery waited for a concurrency slot
+func RecordConcurrencyWait(actionID string, wait time.Duration) {
+	if ActionConcurrencyWait == nil {
+		return // Metrics not initialized (disabled)
+	}
+	ActionConcurrencyWait.WithLabelValues(actionID).Observe(wait.Seconds())
+}
+
+// AddActionsWaiting adjusts the number of deliveries waiting for a concurrency slot
+func AddActionsWaiting(actionID string, delta int) {
+	if ActionsWaiting == nil {
+		return // Metrics not initialized (disabled)
+	}
+	ActionsWaiting.WithLabelValues(actionID).Add(float64(delta))
+}
+
+// RecordLeaseHeartbeat records a lease extension sent for a running delivery
+func RecordLeaseHeartbeat(status string) {
+	if LeaseHeartbeats == nil {
+		return // Metrics not initialized (disabled)
+	}
+	LeaseHeartbeats.WithLabelValues(status).Inc()
+}
+
+// RecordActionThrottled records a delivery failed by a rate limit or an open circuit breaker
+func RecordActionThrottled(actionID, reason string) {
+	if ActionsThrottled == nil {
+		return // Metrics not initialized (disabled)
+	}
+	ActionsThrottled.WithLabelValues(actionID, reason).Inc()
+}
+
+// SetCircuitBreakerState records the state of a destination host's circuit breaker
+func SetCircuitBreakerState(host string, state int) {
+	if CircuitBreakerState == nil {
+		return // Metrics not initialized (disabled)
+	}
+	CircuitBreakerState.WithLabelValues(host).Set(float64(state))
+}
+
+// SetDeliveryStreamConnected records whether the delivery stream is connected
+func SetDeliveryStreamConnected(connected bool) {
+	if DeliveryStreamConnected == nil {
+		return // Metrics not initialized (disabled)
+	}
+	if connected {
+		DeliveryStreamConnected.Set(1)
+	} else {
+		DeliveryStreamConnected.Set(0)
+	}
+}
+
+// SetPollInterval records the current interval between polls
+func SetPollInterval(interval time.Duration) {
+	if PollInterval == nil {
+		return // Metrics not initialized (disabled)
+	}
+	PollInterval.Set(interval.Seconds())
+}
+
+// RecordOutboxState records the outbox depth and the age of its oldest entry
+func RecordOutboxState(depth int, oldestAge time.Duration) {
+	if OutboxDepth == nil || OutboxOldestAge == nil {
+		return
+	}
+	OutboxDepth.Set(float64(depth))
+	OutboxOldestAge.Set(oldestAge.Seconds())
+}
+
+// RecordOutboxDeadLetter counts an execution report moved to the dead letter file
+func RecordOutboxDeadLetter(reason string) {
+	if OutboxDeadLetters == nil {
+		return
+	}
+	OutboxDeadLetters.WithLabelValues(reason).Inc()
+}
+
 // RecordHTTPRequest records metrics for an HTTP request
 func RecordHTTPRequest(method string, statusCode int, duration time.Duration) {
 	if HTTPRequestsTotal == nil || HTTPRequestDuration == nil {
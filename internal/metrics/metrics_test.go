@@ -36,8 +36,21 @@ func TestInitMetrics(t *testing.T) {
 	assert.NotNil(t, metrics.ActionExecutionDuration)
 	assert.NotNil(t, metrics.DeliveriesMarkedRunning)
 	assert.NotNil(t, metrics.EventsRunning)
+	assert.NotNil(t, metrics.DeliveryStreamConnected)
+	assert.NotNil(t, metrics.PollInterval)
+	assert.NotNil(t, metrics.LeaseHeartbeats)
 	assert.NotNil(t, metrics.WorkerPoolSize)
 	assert.NotNil(t, metrics.WorkerPoolQueueSize)
+	assert.NotNil(t, metrics.EventsRejected)
+	assert.NotNil(t, metrics.EventsDuplicate)
+	assert.NotNil(t, metrics.ActionConcurrencyLimited)
+	assert.NotNil(t, metrics.ActionConcurrencyWait)
+	assert.NotNil(t, metrics.ActionsWaiting)
+	assert.NotNil(t, metrics.ActionsThrottled)
+	assert.NotNil(t, metrics.CircuitBreakerState)
+	assert.NotNil(t, metrics.OutboxDepth)
+	assert.NotNil(t, metrics.OutboxOldestAge)
+	assert.NotNil(t, metrics.OutboxDeadLetters)
 	assert.NotNil(t, metrics.HTTPRequestsTotal)
 	assert.NotNil(t, metrics.HTTPRequestDuration)
 	assert.NotNil(t, metrics.GitPullsTotal)
@@ -144,6 +157,32 @@ func TestRecordActionExecution(t *testing.T) {
 	assert.True(t, foundHistogram, "Should have recorded action execution duration")
 }
 
+func TestRecordActionLimits_LabelledByActionID(t *testing.T) {
+	metrics.InitMetrics(map[string]string{"test": "true"})
+
+	// on: actions have no name, so limit metrics are labelled with the action ID
+	metrics.RecordConcurrencyLimited("incident.created", "rejected")
+	metrics.RecordActionThrottled("incident.created", "rate_limited")
+
+	metricFamilies, err := prometheus.DefaultGatherer.Gather()
+	require.NoError(t, err)
+
+	found := 0
+	for _, mf := range metricFamilies {
+		if mf.GetName() != "rec_action_concurrency_limited_total" && mf.GetName() != "rec_actions_throttled_total" {
+			continue
+		}
+		for _, m := range mf.GetMetric() {
+			for _, label := range m.GetLabel() {
+				if label.GetName() == "action_id" && label.GetValue() == "incident.created" {
+					found++
+				}
+			}
+		}
+	}
+	assert.Equal(t, 2, found, "Should label limit metrics with the action ID")
+}
+
 func TestRecordHTTPRequest(t *testing.T) {
 	// Initialize metrics first
 	metrics.InitMetrics(map[string]string{"test": "true"})
@@ -217,6 +256,33 @@ func TestRecordMetrics_WhenNotInitialized(t *testing.T) {
 	assert.NotPanics(t, func() {
 		metrics.RecordGitPull("repo", "success", time.Second)
 	})
+
+	assert.NotPanics(t, func() {
+		metrics.RecordEventsRejected("unclaimed", 1)
+	})
+
+	assert.NotPanics(t, func() {
+		metrics.RecordEventsDuplicate("delivery_id")
+	})
+
+	assert.NotPanics(t, func() {
+		metrics.RecordOutboxState(1, time.Minute)
+		metrics.RecordOutboxDeadLetter("max_attempts")
+	})
+
+	assert.NotPanics(t, func() {
+		metrics.RecordConcurrencyLimited("restart_service", "queued")
+		metrics.RecordConcurrencyWait("restart_service", time.Second)
+		metrics.AddActionsWaiting("restart_service", 1)
+	})
+
+	assert.NotPanics(t, func() {
+		metrics.RecordActionThrottled("notify", "rate_limited")
+		metrics.RecordLeaseHeartbeat("error")
+		metrics.SetDeliveryStreamConnected(true)
+		metrics.SetPollInterval(5 * time.Second)
+		metrics.SetCircuitBreakerState("hooks.example.com", 2)
+	})
 }
 
 func TestServer_MetricsEndpoint(t *testing.T) {
@@ -369,8 +435,19 @@ func TestMetrics_AllMetricsPresent(t *testing.T) {
 	metrics.ActionExecutionDuration.WithLabelValues("test", "script").Observe(1.0)
 	metrics.DeliveriesMarkedRunning.WithLabelValues("success").Inc()
 	metrics.EventsRunning.Set(5)
+	metrics.RecordLeaseHeartbeat("success")
+	metrics.SetDeliveryStreamConnected(false)
+	metrics.SetPollInterval(5 * time.Second)
 	metrics.WorkerPoolSize.Set(3)
 	metrics.WorkerPoolQueueSize.Set(10)
+	metrics.EventsRejected.WithLabelValues("unclaimed").Inc()
+	metrics.RecordEventsDuplicate("event_id")
+	metrics.RecordConcurrencyLimited("restart_service", "queued")
+	metrics.RecordConcurrencyWait("restart_service", time.Second)
+	metrics.AddActionsWaiting("restart_service", 1)
+	metrics.RecordActionThrottled("notify", "circuit_open")
+	metrics.SetCircuitBreakerState("hooks.example.com", 0)
+	metrics.RecordOutboxState(2, 30*time.Second)
 	metrics.HTTPRequestsTotal.WithLabelValues("POST", "200").Inc()
 	metrics.HTTPRequestDuration.WithLabelValues("POST").Observe(0.5)
 	metrics.GitPullsTotal.WithLabelValues("repo", "success").Inc()
@@ -384,12 +461,24 @@ func TestMetrics_AllMetricsPresent(t *testing.T) {
 	expectedMetrics := []string{
 		"rec_events_polled_total",
 		"rec_events_received_total",
+		"rec_delivery_stream_connected",
+		"rec_poll_interval_seconds",
 		"rec_actions_executed_total",
 		"rec_action_execution_duration_seconds",
 		"rec_deliveries_marked_running_total",
 		"rec_events_running",
+		"rec_lease_heartbeats_total",
 		"rec_worker_pool_size",
 		"rec_worker_pool_queue_size",
+		"rec_events_rejected_total",
+		"rec_events_duplicate_total",
+		"rec_action_concurrency_limited_total",
+		"rec_action_concurrency_wait_seconds",
+		"rec_actions_waiting",
+		"rec_actions_throttled_total",
+		"rec_circuit_breaker_state",
+		"rec_outbox_depth",
+		"rec_outbox_oldest_entry_age_seconds",
 		"rec_http_requests_total",
 		"rec_http_request_duration_seconds",
 		"rec_git_pulls_total",
@@ -0,0 +1,398 @@
+package outbox
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/rootly/edge-connector/internal/api"
+	"github.com/rootly/edge-connector/internal/config"
+	"github.com/rootly/edge-connector/internal/metrics"
+)
+
+// fileName is the name of the outbox file inside the state directory
+const fileName = "outbox.jsonl"
+
+// deadLetterFileName holds reports that are no longer replayed, kept for inspection
+const deadLetterFileName = "outbox-dead.jsonl"
+
+// Reasons a report is moved to the dead letter file
+const (
+	deadLetterPermanent   = "permanent_error"
+	deadLetterMaxAttempts = "max_attempts"
+)
+
+// Sender delivers execution results to the Rootly API
+type Sender interface {
+	ReportExecution(ctx context.Context, execution api.ExecutionResult) error
+}
+
+// Entry is an execution report waiting to be re-sent
+type Entry struct {
+	EnqueuedAt    time.Time           `json:"enqueued_at"`
+	NextAttemptAt time.Time           `json:"next_attempt_at"`
+	ID            string              `json:"id"`
+	DeliveryID    string              `json:"delivery_id"` // ExecutionResult omits it from JSON since it travels in the URL
+	LastError     string              `json:"last_error,omitempty"`
+	Execution     api.ExecutionResult `json:"execution"`
+	Attempts      int                 `json:"attempts"`
+
+	DeadLetterReason string `json:"dead_letter_reason,omitempty"` // Set in the dead letter file only
+}
+
+// Outbox stores execution reports that failed to send in a JSON-lines file
+// and replays them in the background with backoff, including after a restart
+type Outbox struct {
+	sender         Sender
+	path           string
+	deadPath       string
+	entries        []Entry
+	mu             sync.Mutex
+	replayMu       sync.Mutex
+	replayInterval time.Duration
+	maxBackoff     time.Duration
+	maxEntries     int
+	maxAttempts    int
+}
+
+// New creates an outbox in the given state directory and loads any pending entries
+func New(dir string, cfg *config.OutboxConfig, sender Sender) (*Outbox, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create state directory: %w", err)
+	}
+
+	o := &Outbox{
+		sender:         sender,
+		path:           filepath.Join(dir, fileName),
+		deadPath:       filepath.Join(dir, deadLetterFileName),
+		replayInterval: time.Duration(cfg.ReplayIntervalMs) * time.Millisecond,
+		maxBackoff:     time.Duration(cfg.MaxBackoffSec) * time.Second,
+		maxEntries:     cfg.MaxEntries,
+		maxAttempts:    cfg.MaxAttempts,
+	}
+
+	if err := o.load(); err != nil {
+		return nil, err
+	}
+
+	if len(o.entries) > 0 {
+		log.WithFields(log.Fields{
+			"pending": len(o.entries),
+			"path":    o.path,
+		}).Info("Loaded pending execution reports from outbox")
+	}
+	o.updateMetricsLocked(time.Now())
+
+	return o, nil
+}
+
+// Enqueue persists an execution report that could not be delivered
+func (o *Outbox) Enqueue(execution api.ExecutionResult, cause error) error {
+	now := time.Now()
+	entry := Entry{
+		ID:            fmt.Sprintf("%s-%d", execution.DeliveryID, now.UnixNano()),
+		DeliveryID:    execution.DeliveryID,
+		Execution:     execution,
+		EnqueuedAt:    now,
+		NextAttemptAt: now.Add(o.replayInterval),
+	}
+	if cause != nil {
+		entry.LastError = cause.Error()
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.entries = append(o.entries, entry)
+	if o.maxEntries > 0 && len(o.entries) > o.maxEntries {
+		dropped := o.entries[0]
+		o.entries = o.entries[1:]
+		log.WithFields(log.Fields{
+			"delivery_id": dropped.Execution.DeliveryID,
+			"max_entries": o.maxEntries,
+		}).Error("Outbox is full, dropping oldest execution report")
+	}
+
+	if err := o.persistLocked(); err != nil {
+		return err
+	}
+	o.updateMetricsLocked(now)
+
+	return nil
+}
+
+// Len returns the number of pending execution reports
+func (o *Outbox) Len() int {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return len(o.entries)
+}
+
+// Start replays pending execution reports until the context is canceled
+func (o *Outbox) Start(ctx context.Context) {
+	ticker := time.NewTicker(o.replayInterval)
+	defer ticker.Stop()
+
+	log.WithField("replay_interval", o.replayInterval.String()).Info("Starting outbox replay loop")
+
+	// Replay immediately so reports left over from a previous run go out on startup
+	o.Replay(ctx, true)
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Info("Stopping outbox replay loop")
+			return
+		case <-ticker.C:
+			o.Replay(ctx, false)
+		}
+	}
+}
+
+// Replay tries to send every entry that is due (or every entry when force is true)
+// Entries that are delivered are removed; failed entries are rescheduled with backoff, unless the
+// API rejected them for good or they ran out of attempts, in which case they go to the dead letter file
+func (o *Outbox) Replay(ctx context.Context, force bool) {
+	o.replayMu.Lock()
+	defer o.replayMu.Unlock()
+
+	now := time.Now()
+	o.mu.Lock()
+	var due []Entry
+	for _, entry := range o.entries {
+		if force || !entry.NextAttemptAt.After(now) {
+			due = append(due, entry)
+		}
+	}
+	o.updateMetricsLocked(now)
+	o.mu.Unlock()
+
+	if len(due) == 0 {
+		return
+	}
+
+	delivered := make(map[string]bool, len(due))
+	failed := make(map[string]error)
+	for _, entry := range due {
+		if ctx.Err() != nil {
+			break
+		}
+		if err := o.sender.ReportExecution(ctx, entry.Execution); err != nil {
+			if errors.Is(err, context.Canceled) {
+				break
+			}
+			failed[entry.ID] = err
+			if o.deadLetterReason(entry, err) == "" {
+				log.WithError(err).WithFields(log.Fields{
+					"delivery_id": entry.Execution.DeliveryID,
+					"attempts":    entry.Attempts + 1,
+				}).Warn("Failed to replay execution report from outbox")
+			}
+			continue
+		}
+		delivered[entry.ID] = true
+		log.WithFields(log.Fields{
+			"delivery_id":      entry.Execution.DeliveryID,
+			"execution_status": entry.Execution.ExecutionStatus,
+			"queued_for":       time.Since(entry.EnqueuedAt).String(),
+		}).Info("Replayed execution report from outbox")
+	}
+
+	if len(delivered) == 0 && len(failed) == 0 {
+		return
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	now = time.Now()
+	var dead []Entry
+	remaining := o.entries[:0]
+	for _, entry := range o.entries {
+		if delivered[entry.ID] {
+			continue
+		}
+		if err, ok := failed[entry.ID]; ok {
+			reason := o.deadLetterReason(entry, err)
+			entry.Attempts++
+			entry.LastError = err.Error()
+			if reason != "" {
+				entry.DeadLetterReason = reason
+				dead = append(dead, entry)
+				continue
+			}
+			entry.NextAttemptAt = now.Add(o.backoff(entry.Attempts))
+		}
+		remaining = append(remaining, entry)
+	}
+	o.entries = remaining
+
+	// Write dead letters first so a crash in between replays them rather than losing them
+	o.deadLetterLocked(dead)
+	if err := o.persistLocked(); err != nil {
+		log.WithError(err).Error("Failed to persist outbox")
+	}
+	o.updateMetricsLocked(now)
+}
+
+// deadLetterReason returns why a failed entry should no longer be replayed, or "" to retry it
+func (o *Outbox) deadLetterReason(entry Entry, err error) string {
+	if !api.IsRetryable(err) {
+		return deadLetterPermanent
+	}
+	if o.maxAttempts > 0 && entry.Attempts+1 >= o.maxAttempts {
+		return deadLetterMaxAttempts
+	}
+	return ""
+}
+
+// deadLetterLocked appends entries that are given up on to the dead letter file
+// Caller must hold o.mu
+func (o *Outbox) deadLetterLocked(entries []Entry) {
+	if len(entries) == 0 {
+		return
+	}
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	for _, entry := range entries {
+		metrics.RecordOutboxDeadLetter(entry.DeadLetterReason)
+		log.WithFields(log.Fields{
+			"delivery_id": entry.Execution.DeliveryID,
+			"attempts":    entry.Attempts,
+			"reason":      entry.DeadLetterReason,
+			"last_error":  entry.LastError,
+			"path":        o.deadPath,
+		}).Error("Giving up on execution report, moved to dead letter file")
+		if err := encoder.Encode(entry); err != nil {
+			log.WithError(err).Error("Failed to encode dead letter entry")
+		}
+	}
+
+	if err := appendFileSync(o.deadPath, buf.Bytes()); err != nil {
+		log.WithError(err).WithField("path", o.deadPath).Error("Failed to write outbox dead letter file")
+	}
+}
+
+// backoff returns the delay before the next attempt, doubling per attempt up to maxBackoff
+func (o *Outbox) backoff(attempts int) time.Duration {
+	delay := o.replayInterval
+	for i := 1; i < attempts && delay < o.maxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > o.maxBackoff {
+		delay = o.maxBackoff
+	}
+	return delay
+}
+
+// load reads pending entries from disk, skipping lines that cannot be parsed
+func (o *Outbox) load() error {
+	data, err := os.ReadFile(o.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to read outbox file: %w", err)
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var entry Entry
+		if err := json.Unmarshal(line, &entry); err != nil {
+			log.WithError(err).WithField("path", o.path).Warn("Skipping corrupt outbox entry")
+			continue
+		}
+		entry.Execution.DeliveryID = entry.DeliveryID
+		o.entries = append(o.entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to parse outbox file: %w", err)
+	}
+
+	return nil
+}
+
+// persistLocked atomically rewrites the outbox file with the current entries
+// Caller must hold o.mu
+func (o *Outbox) persistLocked() error {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	for _, entry := range o.entries {
+		if err := encoder.Encode(entry); err != nil {
+			return fmt.Errorf("failed to encode outbox entry: %w", err)
+		}
+	}
+
+	tmpPath := o.path + ".tmp"
+	if err := writeFileSync(tmpPath, buf.Bytes()); err != nil {
+		return fmt.Errorf("failed to write outbox file: %w", err)
+	}
+	if err := os.Rename(tmpPath, o.path); err != nil {
+		return fmt.Errorf("failed to replace outbox file: %w", err)
+	}
+
+	return nil
+}
+
+// writeFileSync writes data to path and flushes it to disk before returning
+func writeFileSync(path string, data []byte) (err error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+
+	if _, err = file.Write(data); err != nil {
+		return err
+	}
+	return file.Sync()
+}
+
+// appendFileSync appends data to path and flushes it to disk before returning
+func appendFileSync(path string, data []byte) (err error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+
+	if _, err = file.Write(data); err != nil {
+		return err
+	}
+	return file.Sync()
+}
+
+// updateMetricsLocked publishes outbox depth and oldest entry age
+// Caller must hold o.mu (or be the only goroutine with access)
+func (o *Outbox) updateMetricsLocked(now time.Time) {
+	var oldestAge time.Duration
+	for _, entry := range o.entries {
+		if age := now.Sub(entry.EnqueuedAt); age > oldestAge {
+			oldestAge = age
+		}
+	}
+	metrics.RecordOutboxState(len(o.entries), oldestAge)
+}
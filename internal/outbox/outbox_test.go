@@ -0,0 +1,216 @@
+package outbox_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rootly/edge-connector/internal/api"
+	"github.com/rootly/edge-connector/internal/config"
+	"github.com/rootly/edge-connector/internal/outbox"
+)
+
+type mockSender struct {
+	err  error
+	sent []api.ExecutionResult
+	mu   sync.Mutex
+}
+
+func (m *mockSender) ReportExecution(ctx context.Context, execution api.ExecutionResult) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.err != nil {
+		return m.err
+	}
+	m.sent = append(m.sent, execution)
+	return nil
+}
+
+func (m *mockSender) setErr(err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.err = err
+}
+
+func (m *mockSender) sentCount() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.sent)
+}
+
+func testConfig() *config.OutboxConfig {
+	return &config.OutboxConfig{
+		ReplayIntervalMs: 20,
+		MaxBackoffSec:    1,
+		MaxEntries:       100,
+		MaxAttempts:      5,
+	}
+}
+
+func TestOutbox_EnqueuePersistsAcrossRestart(t *testing.T) {
+	dir := t.TempDir()
+	sender := &mockSender{}
+
+	ob, err := outbox.New(dir, testConfig(), sender)
+	require.NoError(t, err)
+	assert.Equal(t, 0, ob.Len())
+
+	err = ob.Enqueue(api.ExecutionResult{DeliveryID: "delivery-1", ExecutionStatus: "completed"}, &api.RequestError{Err: errors.New("api down")})
+	require.NoError(t, err)
+	err = ob.Enqueue(api.ExecutionResult{DeliveryID: "delivery-2", ExecutionStatus: "failed"}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 2, ob.Len())
+
+	_, err = os.Stat(filepath.Join(dir, "outbox.jsonl"))
+	require.NoError(t, err)
+
+	// Simulate a restart: a new outbox on the same directory sees the pending entries
+	restarted, err := outbox.New(dir, testConfig(), sender)
+	require.NoError(t, err)
+	assert.Equal(t, 2, restarted.Len())
+
+	restarted.Replay(context.Background(), true)
+	assert.Equal(t, 0, restarted.Len())
+	require.Equal(t, 2, sender.sentCount())
+	assert.Equal(t, "delivery-1", sender.sent[0].DeliveryID)
+	assert.Equal(t, "delivery-2", sender.sent[1].DeliveryID)
+
+	// Delivered entries are removed from disk too
+	reloaded, err := outbox.New(dir, testConfig(), sender)
+	require.NoError(t, err)
+	assert.Equal(t, 0, reloaded.Len())
+}
+
+func TestOutbox_ReplayFailureKeepsEntry(t *testing.T) {
+	sender := &mockSender{err: &api.RequestError{Err: errors.New("still down")}}
+
+	ob, err := outbox.New(t.TempDir(), testConfig(), sender)
+	require.NoError(t, err)
+	require.NoError(t, ob.Enqueue(api.ExecutionResult{DeliveryID: "delivery-1"}, nil))
+
+	ob.Replay(context.Background(), true)
+	assert.Equal(t, 1, ob.Len(), "Entry should stay in the outbox when replay fails")
+
+	sender.setErr(nil)
+	ob.Replay(context.Background(), true)
+	assert.Equal(t, 0, ob.Len())
+	assert.Equal(t, 1, sender.sentCount())
+}
+
+func TestOutbox_PermanentErrorMovedToDeadLetters(t *testing.T) {
+	dir := t.TempDir()
+	sender := &mockSender{err: &api.StatusError{StatusCode: 422}}
+
+	ob, err := outbox.New(dir, testConfig(), sender)
+	require.NoError(t, err)
+	require.NoError(t, ob.Enqueue(api.ExecutionResult{DeliveryID: "delivery-1"}, nil))
+
+	ob.Replay(context.Background(), true)
+	assert.Equal(t, 0, ob.Len(), "A rejected report should not be replayed again")
+
+	dead, err := os.ReadFile(filepath.Join(dir, "outbox-dead.jsonl"))
+	require.NoError(t, err)
+	assert.Contains(t, string(dead), `"delivery_id":"delivery-1"`)
+	assert.Contains(t, string(dead), `"dead_letter_reason":"permanent_error"`)
+	assert.Contains(t, string(dead), "unexpected status code: 422")
+}
+
+func TestOutbox_GivesUpAfterMaxAttempts(t *testing.T) {
+	dir := t.TempDir()
+	sender := &mockSender{err: &api.StatusError{StatusCode: 503}}
+
+	ob, err := outbox.New(dir, testConfig(), sender)
+	require.NoError(t, err)
+	require.NoError(t, ob.Enqueue(api.ExecutionResult{DeliveryID: "delivery-1"}, nil))
+
+	for range 4 {
+		ob.Replay(context.Background(), true)
+		assert.Equal(t, 1, ob.Len(), "Retryable failures are replayed until max_attempts")
+	}
+	ob.Replay(context.Background(), true)
+	assert.Equal(t, 0, ob.Len())
+
+	dead, err := os.ReadFile(filepath.Join(dir, "outbox-dead.jsonl"))
+	require.NoError(t, err)
+	assert.Contains(t, string(dead), `"dead_letter_reason":"max_attempts"`)
+	assert.Contains(t, string(dead), `"attempts":5`)
+}
+
+func TestOutbox_ReplaySkipsEntriesNotYetDue(t *testing.T) {
+	sender := &mockSender{}
+	cfg := testConfig()
+	cfg.ReplayIntervalMs = 60000
+
+	ob, err := outbox.New(t.TempDir(), cfg, sender)
+	require.NoError(t, err)
+	require.NoError(t, ob.Enqueue(api.ExecutionResult{DeliveryID: "delivery-1"}, nil))
+
+	ob.Replay(context.Background(), false)
+	assert.Equal(t, 1, ob.Len())
+	assert.Equal(t, 0, sender.sentCount())
+}
+
+func TestOutbox_DropsOldestWhenFull(t *testing.T) {
+	sender := &mockSender{}
+	cfg := testConfig()
+	cfg.MaxEntries = 2
+
+	ob, err := outbox.New(t.TempDir(), cfg, sender)
+	require.NoError(t, err)
+	require.NoError(t, ob.Enqueue(api.ExecutionResult{DeliveryID: "delivery-1"}, nil))
+	require.NoError(t, ob.Enqueue(api.ExecutionResult{DeliveryID: "delivery-2"}, nil))
+	require.NoError(t, ob.Enqueue(api.ExecutionResult{DeliveryID: "delivery-3"}, nil))
+	assert.Equal(t, 2, ob.Len())
+
+	ob.Replay(context.Background(), true)
+	require.Equal(t, 2, sender.sentCount())
+	assert.Equal(t, "delivery-2", sender.sent[0].DeliveryID)
+	assert.Equal(t, "delivery-3", sender.sent[1].DeliveryID)
+}
+
+func TestOutbox_SkipsCorruptLines(t *testing.T) {
+	dir := t.TempDir()
+	content := `{"id":"a","delivery_id":"delivery-1","execution":{"execution_status":"completed"}}
+not-json
+{"id":"b","delivery_id":"delivery-2","execution":{"execution_status":"failed"}}
+`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "outbox.jsonl"), []byte(content), 0o600))
+
+	sender := &mockSender{}
+	ob, err := outbox.New(dir, testConfig(), sender)
+	require.NoError(t, err)
+	assert.Equal(t, 2, ob.Len())
+
+	ob.Replay(context.Background(), true)
+	require.Equal(t, 2, sender.sentCount())
+	assert.Equal(t, "delivery-1", sender.sent[0].DeliveryID)
+	assert.Equal(t, "delivery-2", sender.sent[1].DeliveryID)
+}
+
+func TestOutbox_StartReplaysInBackground(t *testing.T) {
+	sender := &mockSender{err: &api.RequestError{Err: errors.New("api down")}}
+
+	ob, err := outbox.New(t.TempDir(), testConfig(), sender)
+	require.NoError(t, err)
+	require.NoError(t, ob.Enqueue(api.ExecutionResult{DeliveryID: "delivery-1"}, nil))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go ob.Start(ctx)
+
+	// API recovers; the loop should deliver the report on a later tick
+	time.Sleep(50 * time.Millisecond)
+	sender.setErr(nil)
+
+	assert.Eventually(t, func() bool {
+		return ob.Len() == 0
+	}, 2*time.Second, 10*time.Millisecond)
+	assert.Equal(t, 1, sender.sentCount())
+}
@@ -2,20 +2,59 @@ package poller
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"math"
 	"time"
 
 	log "github.com/sirupsen/logrus"
 
 	"github.com/rootly/edge-connector/internal/api"
+	"github.com/rootly/edge-connector/internal/backoff"
 	"github.com/rootly/edge-connector/internal/config"
+	"github.com/rootly/edge-connector/internal/dedup"
 	"github.com/rootly/edge-connector/internal/metrics"
+	"github.com/rootly/edge-connector/internal/reporter"
 )
 
+// Rejection outcomes for events that could not be queued
+const (
+	rejectedUnclaimed      = "unclaimed"
+	rejectedReportedFailed = "reported_failed"
+)
+
+// Source receives deliveries from the Rootly API and submits them to the worker pool until ctx is done
+// Implemented by Poller (GET /deliveries on an interval) and Streamer (server-sent events, polling as a fallback)
+type Source interface {
+	Start(ctx context.Context) error
+}
+
 // WorkerPool interface for submitting events
 type WorkerPool interface {
-	Submit(event api.Event)
+	Submit(event api.Event) bool
+	FreeSlots() int
+}
+
+// Journal records claimed deliveries so they can be recovered after a crash
+type Journal interface {
+	Claimed(event api.Event)
+	Finished(deliveryID string)
+}
+
+// Reporter reports the result of a delivery the poller finishes itself (skipped or rejected)
+type Reporter interface {
+	Report(ctx context.Context, deliveryID, actionName, actionUUID string, result reporter.ScriptResult) error
+}
+
+// LeaseHolder keeps a claimed delivery leased until it is executed and reported
+type LeaseHolder interface {
+	HoldLease(event api.Event)
+	ReleaseLease(deliveryID string)
+}
+
+// Deduplicator remembers claimed deliveries to recognize duplicates
+type Deduplicator interface {
+	Check(event api.Event) *dedup.Duplicate
+	Record(event api.Event)
 }
 
 // Poller manages polling events from the Rootly API
@@ -23,7 +62,17 @@ type Poller struct {
 	client     *api.Client
 	config     *config.PollerConfig
 	workerPool WorkerPool
+	journal    Journal
+	dedup      Deduplicator
+	leases     LeaseHolder
+	reporter   Reporter
+	cursor     *int64 // Position in the backlog to continue from; kept across poll cycles and errors
 	retryCount int
+
+	// Adaptive polling state
+	rateLimit api.RateLimit // From the last successful fetch
+	interval  time.Duration // Current interval between polls, adapted to the load of recent polls
+	load      pollLoad      // How many deliveries the last poll found
 }
 
 // New creates a new poller
@@ -32,58 +81,135 @@ func New(client *api.Client, cfg *config.PollerConfig, pool WorkerPool) *Poller
 		client:     client,
 		config:     cfg,
 		workerPool: pool,
+		reporter:   reporter.New(client),
 		retryCount: 0,
+		interval:   time.Duration(cfg.PollingWaitIntervalMs) * time.Millisecond,
 	}
 }
 
+// SetJournal sets the journal used to record claimed deliveries
+func (p *Poller) SetJournal(journal Journal) {
+	p.journal = journal
+}
+
+// SetReporter sets the reporter for deliveries the poller finishes itself, so failed reports reach the outbox
+func (p *Poller) SetReporter(rep Reporter) {
+	p.reporter = rep
+}
+
+// SetLeaseHolder sets what keeps claimed deliveries leased while they wait in the worker queue
+func (p *Poller) SetLeaseHolder(holder LeaseHolder) {
+	p.leases = holder
+}
+
+// SetDeduplicator sets the store used to skip deliveries that were already seen
+func (p *Poller) SetDeduplicator(deduplicator Deduplicator) {
+	p.dedup = deduplicator
+}
+
 // Start starts the polling loop
 func (p *Poller) Start(ctx context.Context) error {
 	log.WithFields(log.Fields{
 		"polling_interval_ms": p.config.PollingWaitIntervalMs,
 		"max_messages":        p.config.MaxNumberOfMessages,
+		"max_pages":           p.config.MaxPagesPerPoll,
 		"visibility_timeout":  p.config.VisibilityTimeoutSec,
+		"min_interval_ms":     p.config.MinPollingIntervalMs,
+		"max_interval_ms":     p.config.MaxPollingIntervalMs,
 	}).Info("Starting poller")
 
-	ticker := time.NewTicker(time.Duration(p.config.PollingWaitIntervalMs) * time.Millisecond)
-	defer ticker.Stop()
+	p.run(ctx, p.jitter(p.interval))
+	log.Info("Poller stopped")
+	return ctx.Err()
+}
+
+// run polls until ctx is done, first after the given delay and then as decided by nextDelay
+func (p *Poller) run(ctx context.Context, first time.Duration) {
+	timer := time.NewTimer(first)
+	defer timer.Stop()
 
 	for {
 		select {
 		case <-ctx.Done():
-			log.Info("Poller stopped")
-			return ctx.Err()
-		case <-ticker.C:
-			if err := p.poll(ctx); err != nil {
+			return
+		case <-timer.C:
+			err := p.poll(ctx)
+			if err != nil {
 				log.WithError(err).Error("Polling error")
-				if p.config.RetryOnError {
-					p.handleError(err, ticker)
-				}
-			} else {
-				// Reset retry count on success
-				p.retryCount = 0
 			}
+			timer.Reset(p.nextDelay(err, time.Now()))
 		}
 	}
 }
 
 // poll fetches and processes events from the API
+// While the API returns a next cursor, further pages are fetched in the same cycle (up to
+// max_pages_per_poll) as long as the worker queue has room; otherwise the next cycle continues from the cursor
 func (p *Poller) poll(ctx context.Context) error {
+	p.load = loadIdle
+	maxPages := max(p.config.MaxPagesPerPoll, 1)
+	for page := 1; page <= maxPages; page++ {
+		more, err := p.pollPage(ctx)
+		if err != nil {
+			return err
+		}
+		if !more || ctx.Err() != nil {
+			return nil
+		}
+	}
+
+	log.WithField("max_pages", maxPages).Debug("Reached page limit for this poll, continuing from cursor next cycle")
+	return nil
+}
+
+// pollPage fetches and processes one page of events
+// Returns whether another page should be fetched in this cycle
+func (p *Poller) pollPage(ctx context.Context) (bool, error) {
+	// Apply backpressure: never fetch more deliveries than the worker queue can accept
+	freeSlots := p.workerPool.FreeSlots()
+	if freeSlots <= 0 {
+		// Polling faster would not help until workers catch up
+		p.load = max(p.load, loadPartial)
+		log.Debug("Worker queue is full, skipping poll")
+		return false, nil
+	}
+	maxMessages := p.config.MaxNumberOfMessages
+	if freeSlots < maxMessages {
+		maxMessages = freeSlots
+	}
+
 	// Fetch events from Rootly API
-	events, err := p.client.FetchEvents(ctx, p.config.MaxNumberOfMessages, p.config.VisibilityTimeoutSec)
+	// A failed fetch keeps the cursor, so the retry continues where the backlog left off
+	response, err := p.client.FetchEventsPage(ctx, maxMessages, p.config.VisibilityTimeoutSec, p.cursor)
 	if err != nil {
 		if metrics.EventsPolled != nil {
-			metrics.EventsPolled.WithLabelValues("error").Inc()
+			status := "error"
+			var rateLimited *api.RateLimitError
+			if errors.As(err, &rateLimited) {
+				status = "rate_limited"
+			}
+			metrics.EventsPolled.WithLabelValues(status).Inc()
 		}
-		return fmt.Errorf("failed to fetch events: %w", err)
+		return false, fmt.Errorf("failed to fetch events: %w", err)
 	}
 
 	if metrics.EventsPolled != nil {
 		metrics.EventsPolled.WithLabelValues("success").Inc()
 	}
 
+	p.rateLimit = response.RateLimit
+	events := response.Events
 	if len(events) == 0 {
+		// Nothing left behind the cursor, start from the head of the queue again
+		p.cursor = nil
 		log.Debug("No events to process")
-		return nil
+		return false, nil
+	}
+	p.cursor = response.NextCursor
+	if len(events) >= maxMessages || p.cursor != nil {
+		p.load = loadFull
+	} else {
+		p.load = max(p.load, loadPartial)
 	}
 
 	// Record received events
@@ -91,10 +217,49 @@ func (p *Poller) poll(ctx context.Context) error {
 		metrics.EventsReceived.Add(float64(len(events)))
 	}
 
-	log.WithField("event_count", len(events)).Info("Fetched events from API")
+	log.WithFields(log.Fields{
+		"event_count": len(events),
+		"has_more":    p.cursor != nil,
+	}).Info("Fetched events from API")
+
+	if !p.processEvents(ctx, events) {
+		// Deliveries left unclaimed come back at the head of the queue after the visibility timeout,
+		// so the next cycle starts from there rather than past them
+		p.cursor = nil
+		return false, nil
+	}
+
+	return p.cursor != nil, nil
+}
+
+// processEvents claims fetched deliveries and submits them to the worker pool
+// Returns false if the worker queue filled up and some deliveries were left unclaimed
+func (p *Poller) processEvents(ctx context.Context, events []api.Event) bool {
+	for i, event := range events {
+		// A redelivered delivery is already running or finished here; its own result stands
+		var duplicate *dedup.Duplicate
+		if p.dedup != nil {
+			duplicate = p.dedup.Check(event)
+		}
+		if duplicate != nil && duplicate.Reason == dedup.ReasonDeliveryID {
+			metrics.RecordEventsDuplicate(duplicate.Reason)
+			log.WithFields(log.Fields{
+				"delivery_id": event.ID,
+				"event_id":    event.EventID,
+				"seen_at":     duplicate.SeenAt.Format(time.RFC3339),
+			}).Info("Skipping redelivered delivery that was already claimed")
+			continue
+		}
+
+		// Stop claiming once the queue has no room
+		// Unclaimed deliveries are redelivered by the backend after the visibility timeout
+		if p.workerPool.FreeSlots() <= 0 {
+			unclaimed := len(events) - i
+			metrics.RecordEventsRejected(rejectedUnclaimed, unclaimed)
+			log.WithField("unclaimed_count", unclaimed).Warn("Worker queue is full, leaving remaining deliveries unclaimed")
+			return false
+		}
 
-	// Process each event
-	for _, event := range events {
 		// Mark delivery as running immediately (claims it for execution)
 		if err := p.client.MarkDeliveryAsRunning(ctx, event.ID); err != nil {
 			if metrics.DeliveriesMarkedRunning != nil {
@@ -117,15 +282,89 @@ func (p *Poller) poll(ctx context.Context) error {
 			"event_type":  event.Type,
 		}).Debug("Delivery marked as running")
 
-		// Submit event to worker pool for processing
-		p.workerPool.Submit(event)
+		if p.journal != nil {
+			p.journal.Claimed(event)
+		}
+		if p.dedup != nil {
+			p.dedup.Record(event)
+		}
+
+		// A new delivery of an event that was already delivered is claimed and reported as skipped
+		if duplicate != nil {
+			metrics.RecordEventsDuplicate(duplicate.Reason)
+			p.skipDuplicate(ctx, event, duplicate)
+			continue
+		}
+
+		// Submit event to worker pool for processing, renewing its lease from now until it is reported
+		// A claimed delivery that cannot be queued is reported as failed so it doesn't stay running forever
+		if p.leases != nil {
+			p.leases.HoldLease(event)
+		}
+		if !p.workerPool.Submit(event) {
+			if p.leases != nil {
+				p.leases.ReleaseLease(event.ID)
+			}
+			metrics.RecordEventsRejected(rejectedReportedFailed, 1)
+			if err := p.reportRejected(ctx, event); err != nil {
+				log.WithFields(log.Fields{
+					"delivery_id": event.ID,
+					"event_id":    event.EventID,
+				}).WithError(err).Error("Failed to report rejected delivery")
+			} else if p.journal != nil {
+				// Left in the journal on failure so it is recovered on the next start
+				p.journal.Finished(event.ID)
+			}
+		}
 	}
 
-	return nil
+	return true
+}
+
+// skipDuplicate reports a claimed delivery as skipped because its event was already delivered
+func (p *Poller) skipDuplicate(ctx context.Context, event api.Event, duplicate *dedup.Duplicate) {
+	fields := log.Fields{
+		"delivery_id":          event.ID,
+		"event_id":             event.EventID,
+		"original_delivery_id": duplicate.DeliveryID,
+	}
+
+	result := reporter.ScriptResult{
+		SkipReason: fmt.Sprintf("duplicate of delivery %s (event %s)", duplicate.DeliveryID, event.EventID),
+	}
+
+	if err := p.reporter.Report(ctx, event.ID, "", eventActionUUID(event), result); err != nil {
+		log.WithFields(fields).WithError(err).Error("Failed to report duplicate delivery")
+		return
+	}
+
+	log.WithFields(fields).Info("Skipped duplicate delivery")
+	if p.journal != nil {
+		// Left in the journal on failure so it is recovered on the next start
+		p.journal.Finished(event.ID)
+	}
+}
+
+// reportRejected reports a claimed delivery as failed because the worker queue could not accept it
+func (p *Poller) reportRejected(ctx context.Context, event api.Event) error {
+	result := reporter.ScriptResult{
+		Error:    errors.New("rejected by connector: worker queue is full"),
+		ExitCode: 1,
+	}
+	return p.reporter.Report(ctx, event.ID, "", eventActionUUID(event), result)
+}
+
+// eventActionUUID returns the UUID of the callable action that triggered the event, if any
+func eventActionUUID(event api.Event) string {
+	if event.Action != nil {
+		return event.Action.ID
+	}
+	return ""
 }
 
 // handleError implements retry logic with backoff
-func (p *Poller) handleError(err error, ticker *time.Ticker) {
+// Returns the wait before the next poll
+func (p *Poller) handleError(err error) time.Duration {
 	p.retryCount++
 
 	if p.retryCount > p.config.MaxRetries {
@@ -134,24 +373,11 @@ func (p *Poller) handleError(err error, ticker *time.Ticker) {
 			"max_retries": p.config.MaxRetries,
 		}).Error("Max retries exceeded, resetting retry count")
 		p.retryCount = 0
-		return
+		return p.baseInterval()
 	}
 
-	var backoffDuration time.Duration
-	if p.config.RetryBackoff == "exponential" {
-		// Exponential backoff: 2^retry * polling_interval
-		multiplier := math.Pow(2, float64(p.retryCount))
-		backoffDuration = time.Duration(float64(p.config.PollingWaitIntervalMs)*multiplier) * time.Millisecond
-	} else {
-		// Linear backoff: retry * polling_interval
-		backoffDuration = time.Duration(p.config.PollingWaitIntervalMs*p.retryCount) * time.Millisecond
-	}
-
-	// Cap backoff at 5 minutes
-	maxBackoff := 5 * time.Minute
-	if backoffDuration > maxBackoff {
-		backoffDuration = maxBackoff
-	}
+	// Exponential (2^retry * polling_interval) or linear (retry * polling_interval), capped at 5 minutes
+	backoffDuration := backoff.Delay(p.config.RetryBackoff, p.baseInterval(), p.retryCount, 5*time.Minute)
 
 	log.WithFields(log.Fields{
 		"retry_count":      p.retryCount,
@@ -160,6 +386,5 @@ func (p *Poller) handleError(err error, ticker *time.Ticker) {
 		"error":            err.Error(),
 	}).Warn("Backing off before next poll")
 
-	// Reset ticker with new interval
-	ticker.Reset(backoffDuration)
+	return backoffDuration
 }
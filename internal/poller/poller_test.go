@@ -3,8 +3,11 @@ package poller_test
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"sync"
 	"testing"
 	"time"
@@ -14,19 +17,36 @@ import (
 
 	"github.com/rootly/edge-connector/internal/api"
 	"github.com/rootly/edge-connector/internal/config"
+	"github.com/rootly/edge-connector/internal/dedup"
 	"github.com/rootly/edge-connector/internal/poller"
+	"github.com/rootly/edge-connector/internal/reporter"
 )
 
 // mockWorkerPool implements poller.WorkerPool for testing
 type mockWorkerPool struct {
 	mu        sync.Mutex
 	submitted []api.Event
+	capacity  int  // Queue capacity (0 = unlimited)
+	reject    bool // Reject every submission
 }
 
-func (m *mockWorkerPool) Submit(event api.Event) {
+func (m *mockWorkerPool) Submit(event api.Event) bool {
 	m.mu.Lock()
 	defer m.mu.Unlock()
+	if m.reject {
+		return false
+	}
 	m.submitted = append(m.submitted, event)
+	return true
+}
+
+func (m *mockWorkerPool) FreeSlots() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.capacity == 0 {
+		return 1000
+	}
+	return m.capacity - len(m.submitted)
 }
 
 func (m *mockWorkerPool) GetSubmitted() []api.Event {
@@ -619,3 +639,690 @@ func TestPoller_ConcurrentPolls(t *testing.T) {
 	assert.GreaterOrEqual(t, finalPollCount, 2, "Should complete multiple rapid poll cycles")
 	assert.GreaterOrEqual(t, pool.Count(), 2, "Should submit events from multiple polls")
 }
+
+func TestPoller_Backpressure_SkipsFetchWhenQueueFull(t *testing.T) {
+	var fetchCalls int
+	var mu sync.Mutex
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		fetchCalls++
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(api.EventsResponse{Events: []api.Event{}})
+	}))
+	defer server.Close()
+
+	client := api.NewClient(server.URL, "", "test-key", "test")
+	cfg := &config.PollerConfig{
+		PollingWaitIntervalMs: 50,
+		MaxNumberOfMessages:   10,
+		VisibilityTimeoutSec:  30,
+	}
+	// Queue already full
+	pool := &mockWorkerPool{capacity: 1, submitted: []api.Event{{ID: "queued"}}}
+
+	p := poller.New(client, cfg, pool)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go p.Start(ctx)
+
+	time.Sleep(200 * time.Millisecond)
+	cancel()
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, 0, fetchCalls, "Poller should not fetch when the worker queue has no free slots")
+}
+
+func TestPoller_Backpressure_LimitsFetchAndClaimsToFreeSlots(t *testing.T) {
+	var requestedMax []string
+	var claimed []string
+	var mu sync.Mutex
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if r.Method == "GET" {
+			requestedMax = append(requestedMax, r.URL.Query().Get("max_messages"))
+			// Backend returns more than requested
+			response := api.EventsResponse{
+				Events: []api.Event{
+					{ID: "delivery-1", EventID: "event-1", Type: "test.event"},
+					{ID: "delivery-2", EventID: "event-2", Type: "test.event"},
+					{ID: "delivery-3", EventID: "event-3", Type: "test.event"},
+				},
+			}
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(response)
+			return
+		}
+		claimed = append(claimed, r.URL.Path)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := api.NewClient(server.URL, "", "test-key", "test")
+	cfg := &config.PollerConfig{
+		PollingWaitIntervalMs: 50,
+		MaxNumberOfMessages:   10,
+		VisibilityTimeoutSec:  30,
+	}
+	pool := &mockWorkerPool{capacity: 2}
+
+	p := poller.New(client, cfg, pool)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go p.Start(ctx)
+
+	time.Sleep(200 * time.Millisecond)
+	cancel()
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.NotEmpty(t, requestedMax)
+	assert.Equal(t, "2", requestedMax[0], "Fetch should be limited to free queue slots")
+	assert.Len(t, requestedMax, 1, "No further fetches once the queue is full")
+	assert.Equal(t, []string{"/deliveries/delivery-1", "/deliveries/delivery-2"}, claimed,
+		"Deliveries beyond free slots must not be claimed")
+	assert.Equal(t, 2, pool.Count())
+}
+
+func TestPoller_Backpressure_ReportsRejectedDeliveryAsFailed(t *testing.T) {
+	var reports []map[string]interface{}
+	var mu sync.Mutex
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" {
+			response := api.EventsResponse{
+				Events: []api.Event{
+					{ID: "delivery-1", EventID: "event-1", Type: "test.event"},
+				},
+			}
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(response)
+			return
+		}
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		mu.Lock()
+		reports = append(reports, body)
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := api.NewClient(server.URL, "", "test-key", "test")
+	cfg := &config.PollerConfig{
+		PollingWaitIntervalMs: 1000,
+		MaxNumberOfMessages:   10,
+		VisibilityTimeoutSec:  30,
+	}
+	pool := &mockWorkerPool{reject: true}
+
+	p := poller.New(client, cfg, pool)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go p.Start(ctx)
+
+	time.Sleep(1200 * time.Millisecond)
+	cancel()
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, reports, 2, "Expected claim (running) followed by failure report")
+	assert.Equal(t, "running", reports[0]["execution_status"])
+	assert.Equal(t, "failed", reports[1]["execution_status"])
+	assert.Contains(t, reports[1]["execution_error"], "worker queue is full")
+}
+
+type mockJournal struct {
+	claimed  []string
+	finished []string
+	mu       sync.Mutex
+}
+
+func (m *mockJournal) Claimed(event api.Event) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.claimed = append(m.claimed, event.ID)
+}
+
+func (m *mockJournal) Finished(deliveryID string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.finished = append(m.finished, deliveryID)
+}
+
+func TestPoller_JournalRecordsClaimedDeliveries(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" {
+			response := api.EventsResponse{
+				Events: []api.Event{
+					{ID: "delivery-1", EventID: "event-1", Type: "test.event"},
+					{ID: "delivery-2", EventID: "event-2", Type: "test.event"},
+				},
+			}
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(response)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := api.NewClient(server.URL, "", "test-key", "test")
+	cfg := &config.PollerConfig{
+		PollingWaitIntervalMs: 1000,
+		MaxNumberOfMessages:   10,
+		VisibilityTimeoutSec:  30,
+	}
+	pool := &mockWorkerPool{}
+	journal := &mockJournal{}
+
+	p := poller.New(client, cfg, pool)
+	p.SetJournal(journal)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go p.Start(ctx)
+
+	time.Sleep(1200 * time.Millisecond)
+	cancel()
+
+	journal.mu.Lock()
+	defer journal.mu.Unlock()
+	assert.Equal(t, []string{"delivery-1", "delivery-2"}, journal.claimed)
+	assert.Empty(t, journal.finished, "Queued deliveries are finished by the executor, not the poller")
+}
+
+func TestPoller_JournalFinishesRejectedDelivery(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" {
+			response := api.EventsResponse{
+				Events: []api.Event{
+					{ID: "delivery-1", EventID: "event-1", Type: "test.event"},
+				},
+			}
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(response)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := api.NewClient(server.URL, "", "test-key", "test")
+	cfg := &config.PollerConfig{
+		PollingWaitIntervalMs: 1000,
+		MaxNumberOfMessages:   10,
+		VisibilityTimeoutSec:  30,
+	}
+	journal := &mockJournal{}
+
+	p := poller.New(client, cfg, &mockWorkerPool{reject: true})
+	p.SetJournal(journal)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go p.Start(ctx)
+
+	time.Sleep(1200 * time.Millisecond)
+	cancel()
+
+	journal.mu.Lock()
+	defer journal.mu.Unlock()
+	assert.Equal(t, []string{"delivery-1"}, journal.claimed)
+	assert.Equal(t, []string{"delivery-1"}, journal.finished)
+}
+
+type mockLeaseHolder struct {
+	held     []string
+	released []string
+	mu       sync.Mutex
+}
+
+func (m *mockLeaseHolder) HoldLease(event api.Event) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.held = append(m.held, event.ID)
+}
+
+func (m *mockLeaseHolder) ReleaseLease(deliveryID string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.released = append(m.released, deliveryID)
+}
+
+func TestPoller_HoldsLeaseOfQueuedDeliveries(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" {
+			response := api.EventsResponse{
+				Events: []api.Event{
+					{ID: "delivery-1", EventID: "event-1", Type: "test.event"},
+					{ID: "delivery-2", EventID: "event-2", Type: "test.event"},
+				},
+			}
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(response)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := api.NewClient(server.URL, "", "test-key", "test")
+	cfg := &config.PollerConfig{
+		PollingWaitIntervalMs: 1000,
+		MaxNumberOfMessages:   10,
+		VisibilityTimeoutSec:  30,
+	}
+
+	for _, tt := range []struct {
+		name     string
+		pool     *mockWorkerPool
+		released []string
+	}{
+		{name: "queued", pool: &mockWorkerPool{}, released: nil},
+		{name: "rejected", pool: &mockWorkerPool{reject: true}, released: []string{"delivery-1", "delivery-2"}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			leases := &mockLeaseHolder{}
+			p := poller.New(client, cfg, tt.pool)
+			p.SetLeaseHolder(leases)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			go p.Start(ctx)
+
+			time.Sleep(1200 * time.Millisecond)
+			cancel()
+
+			leases.mu.Lock()
+			defer leases.mu.Unlock()
+			assert.Equal(t, []string{"delivery-1", "delivery-2"}, leases.held, "Claimed deliveries should be leased before they are queued")
+			assert.Equal(t, tt.released, leases.released, "Only deliveries that could not be queued are released by the poller")
+		})
+	}
+}
+
+func TestPoller_SkipsDuplicateDeliveries(t *testing.T) {
+	type report struct {
+		deliveryID string
+		status     string
+		stdout     string
+	}
+	var reports []report
+	var mu sync.Mutex
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" {
+			response := api.EventsResponse{
+				Events: []api.Event{
+					{ID: "delivery-1", EventID: "event-1", Type: "test.event"},
+					{ID: "delivery-1", EventID: "event-1", Type: "test.event"}, // Redelivered
+					{ID: "delivery-2", EventID: "event-1", Type: "test.event"}, // Same event, new delivery
+					{ID: "delivery-3", EventID: "event-3", Type: "test.event"},
+				},
+			}
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(response)
+			return
+		}
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		stdout, _ := body["execution_stdout"].(string)
+		mu.Lock()
+		reports = append(reports, report{
+			deliveryID: r.URL.Path[len("/deliveries/"):],
+			status:     body["execution_status"].(string),
+			stdout:     stdout,
+		})
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := api.NewClient(server.URL, "", "test-key", "test")
+	cfg := &config.PollerConfig{
+		PollingWaitIntervalMs: 1000,
+		MaxNumberOfMessages:   10,
+		VisibilityTimeoutSec:  30,
+	}
+	pool := &mockWorkerPool{}
+	journal := &mockJournal{}
+	seen, err := dedup.New(t.TempDir(), &config.DedupConfig{TTLSec: 3600})
+	require.NoError(t, err)
+
+	p := poller.New(client, cfg, pool)
+	p.SetJournal(journal)
+	p.SetDeduplicator(seen)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go p.Start(ctx)
+
+	time.Sleep(1200 * time.Millisecond)
+	cancel()
+
+	submitted := pool.GetSubmitted()
+	require.Len(t, submitted, 2)
+	assert.Equal(t, "delivery-1", submitted[0].ID)
+	assert.Equal(t, "delivery-3", submitted[1].ID)
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, reports, 4, "Redelivered delivery is not claimed again")
+	assert.Equal(t, report{"delivery-1", "running", ""}, reports[0])
+	assert.Equal(t, report{"delivery-2", "running", ""}, reports[1])
+	assert.Equal(t, report{"delivery-2", "completed", "Skipped: duplicate of delivery delivery-1 (event event-1)"}, reports[2])
+	assert.Equal(t, report{"delivery-3", "running", ""}, reports[3])
+
+	journal.mu.Lock()
+	defer journal.mu.Unlock()
+	assert.Equal(t, []string{"delivery-2"}, journal.finished, "Skipped duplicates are finished by the poller")
+}
+
+// mockReporter records the results the poller reports itself
+type mockReporter struct {
+	err     error
+	results map[string]reporter.ScriptResult
+	mu      sync.Mutex
+}
+
+func (m *mockReporter) Report(ctx context.Context, deliveryID, actionName, actionUUID string, result reporter.ScriptResult) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.results == nil {
+		m.results = make(map[string]reporter.ScriptResult)
+	}
+	m.results[deliveryID] = result
+	return m.err
+}
+
+func TestPoller_DuplicateReportedThroughReporter(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		err      error
+		finished []string
+	}{
+		{name: "reported", finished: []string{"delivery-2"}},
+		{name: "report failed", err: errors.New("outbox full"), finished: nil},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method == "GET" {
+					response := api.EventsResponse{
+						Events: []api.Event{
+							{ID: "delivery-1", EventID: "event-1", Type: "test.event"},
+							{ID: "delivery-2", EventID: "event-1", Type: "test.event"},
+						},
+					}
+					w.Header().Set("Content-Type", "application/json")
+					json.NewEncoder(w).Encode(response)
+					return
+				}
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer server.Close()
+
+			client := api.NewClient(server.URL, "", "test-key", "test")
+			cfg := &config.PollerConfig{
+				PollingWaitIntervalMs: 1000,
+				MaxNumberOfMessages:   10,
+				VisibilityTimeoutSec:  30,
+			}
+			journal := &mockJournal{}
+			rep := &mockReporter{err: tt.err}
+			seen, err := dedup.New(t.TempDir(), &config.DedupConfig{TTLSec: 3600})
+			require.NoError(t, err)
+
+			p := poller.New(client, cfg, &mockWorkerPool{})
+			p.SetJournal(journal)
+			p.SetReporter(rep)
+			p.SetDeduplicator(seen)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			go p.Start(ctx)
+
+			time.Sleep(1200 * time.Millisecond)
+			cancel()
+
+			rep.mu.Lock()
+			result, ok := rep.results["delivery-2"]
+			rep.mu.Unlock()
+			require.True(t, ok, "Duplicate should be reported through the reporter")
+			assert.Equal(t, "duplicate of delivery delivery-1 (event event-1)", result.SkipReason)
+
+			journal.mu.Lock()
+			defer journal.mu.Unlock()
+			assert.Equal(t, tt.finished, journal.finished, "A duplicate whose report failed stays in the journal")
+		})
+	}
+}
+
+func TestPoller_DedupIgnoresUnclaimedDeliveries(t *testing.T) {
+	var claims int
+	var mu sync.Mutex
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" {
+			response := api.EventsResponse{
+				Events: []api.Event{
+					{ID: "delivery-1", EventID: "event-1", Type: "test.event"},
+				},
+			}
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(response)
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		claims++
+		if claims == 1 {
+			// First claim fails (without retries), so the delivery is left for redelivery
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := api.NewClient(server.URL, "", "test-key", "test")
+	cfg := &config.PollerConfig{
+		PollingWaitIntervalMs: 1000,
+		MaxNumberOfMessages:   10,
+		VisibilityTimeoutSec:  30,
+	}
+	pool := &mockWorkerPool{}
+	seen, err := dedup.New(t.TempDir(), &config.DedupConfig{TTLSec: 3600})
+	require.NoError(t, err)
+
+	p := poller.New(client, cfg, pool)
+	p.SetDeduplicator(seen)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go p.Start(ctx)
+
+	time.Sleep(2200 * time.Millisecond)
+	cancel()
+
+	mu.Lock()
+	assert.GreaterOrEqual(t, claims, 2, "Delivery should be claimed again after the failed claim")
+	mu.Unlock()
+
+	submitted := pool.GetSubmitted()
+	require.Len(t, submitted, 1, "Delivery that failed to be claimed should run once when redelivered")
+	assert.Equal(t, "delivery-1", submitted[0].ID)
+}
+
+func TestPoller_FollowsNextCursor(t *testing.T) {
+	var cursors []string
+	var mu sync.Mutex
+	served := false
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		cursor := r.URL.Query().Get("cursor")
+		cursors = append(cursors, cursor)
+
+		next := func(v int64) *int64 { return &v }
+		var response api.EventsResponse
+		switch cursor {
+		case "":
+			if !served {
+				served = true
+				response = api.EventsResponse{
+					Events:     []api.Event{{ID: "delivery-1"}, {ID: "delivery-2"}},
+					NextCursor: next(100),
+				}
+			}
+		case "100":
+			response = api.EventsResponse{
+				Events:     []api.Event{{ID: "delivery-3"}, {ID: "delivery-4"}},
+				NextCursor: next(200),
+			}
+		case "200":
+			response = api.EventsResponse{Events: []api.Event{{ID: "delivery-5"}}}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(response)
+	}))
+	defer server.Close()
+
+	client := api.NewClient(server.URL, "", "test-key", "test")
+	cfg := &config.PollerConfig{
+		PollingWaitIntervalMs: 50,
+		MaxNumberOfMessages:   2,
+		MaxPagesPerPoll:       10,
+		VisibilityTimeoutSec:  30,
+	}
+	pool := &mockWorkerPool{}
+
+	p := poller.New(client, cfg, pool)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go p.Start(ctx)
+
+	time.Sleep(130 * time.Millisecond)
+	cancel()
+
+	assert.Equal(t, 5, pool.Count(), "The whole backlog should be drained")
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.GreaterOrEqual(t, len(cursors), 4)
+	assert.Equal(t, []string{"", "100", "200", ""}, cursors[:4],
+		"Pages are fetched back to back, and the next cycle starts from the head of the queue")
+}
+
+func TestPoller_PageLimitContinuesFromCursor(t *testing.T) {
+	var cursors []string
+	var mu sync.Mutex
+	failed := false
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		cursor := r.URL.Query().Get("cursor")
+		cursors = append(cursors, cursor)
+
+		// The first fetch of the third page fails
+		if cursor == "2" && !failed {
+			failed = true
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		// An endless backlog: every page points at the next one
+		page, err := strconv.ParseInt(cursor, 10, 64)
+		if cursor == "" {
+			page, err = 0, nil
+		}
+		require.NoError(t, err)
+		next := page + 1
+		response := api.EventsResponse{
+			Events:     []api.Event{{ID: fmt.Sprintf("delivery-%d", page)}},
+			NextCursor: &next,
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(response)
+	}))
+	defer server.Close()
+
+	client := api.NewClient(server.URL, "", "test-key", "test")
+	cfg := &config.PollerConfig{
+		PollingWaitIntervalMs: 50,
+		MaxNumberOfMessages:   10,
+		MaxPagesPerPoll:       2,
+		VisibilityTimeoutSec:  30,
+	}
+	pool := &mockWorkerPool{}
+
+	p := poller.New(client, cfg, pool)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go p.Start(ctx)
+
+	time.Sleep(230 * time.Millisecond)
+	cancel()
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.GreaterOrEqual(t, len(cursors), 5)
+	assert.Equal(t, []string{"", "1", "2", "2", "3"}, cursors[:5],
+		"Each cycle fetches at most max_pages_per_poll pages, and a failed fetch keeps the cursor")
+}
+
+func TestPoller_WaitsForRateLimitReset(t *testing.T) {
+	var fetches int
+	var mu sync.Mutex
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		fetches++
+		mu.Unlock()
+		w.Header().Set("Retry-After", "1")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	client := api.NewClient(server.URL, "", "test-key", "test")
+	cfg := &config.PollerConfig{
+		PollingWaitIntervalMs: 50,
+		MaxNumberOfMessages:   10,
+		VisibilityTimeoutSec:  30,
+		RetryOnError:          true,
+		MaxRetries:            3,
+		RetryBackoff:          "linear",
+	}
+	pool := &mockWorkerPool{}
+
+	p := poller.New(client, cfg, pool)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+	p.Start(ctx)
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, 1, fetches, "Poller should wait for Retry-After instead of polling every interval")
+}
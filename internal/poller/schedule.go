@@ -0,0 +1,124 @@
+package poller
+
+import (
+	"errors"
+	"math/rand/v2"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/rootly/edge-connector/internal/api"
+	"github.com/rootly/edge-connector/internal/metrics"
+)
+
+// pollLoad is how many deliveries a poll found, used to adapt the polling interval
+type pollLoad int
+
+const (
+	loadIdle    pollLoad = iota // Nothing was fetched
+	loadPartial                 // Fewer deliveries than requested, or the worker queue was full
+	loadFull                    // Fetches came back full, so more deliveries are likely waiting
+)
+
+// lowQuotaFraction is the share of the API quota below which polls are spread out until the quota resets
+const lowQuotaFraction = 0.2
+
+// nextDelay returns how long to wait before the next poll, given the outcome of the last one
+// A rate limited fetch waits for the quota to reset; other errors back off as configured. After a
+// successful poll the interval adapts to the load and is stretched when the remaining quota runs low
+func (p *Poller) nextDelay(err error, now time.Time) time.Duration {
+	var rateLimited *api.RateLimitError
+	if errors.As(err, &rateLimited) {
+		if wait := rateLimited.RateLimit.Wait(now); wait > 0 {
+			log.WithFields(log.Fields{
+				"wait":      wait.String(),
+				"remaining": rateLimited.RateLimit.Remaining,
+			}).Warn("API rate limit reached, waiting for the quota to reset")
+			// Jitter only delays, so polls never arrive before the reset
+			return wait + p.jitterSpread(wait)
+		}
+	}
+	if err != nil {
+		delay := p.interval
+		if p.config.RetryOnError {
+			delay = p.handleError(err)
+		}
+		return p.jitter(delay)
+	}
+
+	// Reset retry count on success
+	p.retryCount = 0
+	p.adapt()
+
+	delay := p.interval
+	if quota := p.quotaDelay(now); quota > delay {
+		log.WithFields(log.Fields{
+			"remaining": p.rateLimit.Remaining,
+			"limit":     p.rateLimit.Limit,
+			"delay":     quota.String(),
+		}).Debug("API quota running low, stretching polling interval")
+		delay = quota
+	}
+	return p.jitter(delay)
+}
+
+// adapt moves the polling interval with the load of the last poll: halved (down to
+// min_polling_interval_ms) while fetches come back full, doubled (up to max_polling_interval_ms)
+// while idle, and back to polling_wait_interval_ms otherwise
+func (p *Poller) adapt() {
+	base := p.baseInterval()
+	switch p.load {
+	case loadFull:
+		minInterval := base
+		if p.config.MinPollingIntervalMs > 0 {
+			minInterval = min(time.Duration(p.config.MinPollingIntervalMs)*time.Millisecond, base)
+		}
+		p.interval = max(p.interval/2, minInterval)
+	case loadIdle:
+		maxInterval := max(time.Duration(p.config.MaxPollingIntervalMs)*time.Millisecond, base)
+		p.interval = min(max(p.interval, base)*2, maxInterval)
+	default:
+		p.interval = base
+	}
+	metrics.SetPollInterval(p.interval)
+}
+
+// quotaDelay spreads the remaining API quota over the time left until it resets, once less than
+// lowQuotaFraction of it is left. Returns 0 while the quota is comfortable or the headers are missing
+func (p *Poller) quotaDelay(now time.Time) time.Duration {
+	limit := p.rateLimit
+	if limit.Limit <= 0 || limit.Remaining < 0 || !limit.Reset.After(now) {
+		return 0
+	}
+	untilReset := limit.Reset.Sub(now)
+	if limit.Remaining == 0 {
+		return untilReset
+	}
+	if float64(limit.Remaining) >= lowQuotaFraction*float64(limit.Limit) {
+		return 0
+	}
+	return untilReset / time.Duration(limit.Remaining+1)
+}
+
+// baseInterval returns polling_wait_interval_ms
+func (p *Poller) baseInterval() time.Duration {
+	return time.Duration(p.config.PollingWaitIntervalMs) * time.Millisecond
+}
+
+// jitter shifts a delay by a random amount of up to +/- polling_jitter, so a fleet of connectors
+// does not poll in lockstep
+func (p *Poller) jitter(delay time.Duration) time.Duration {
+	if p.config.PollingJitter <= 0 {
+		return delay
+	}
+	offset := (rand.Float64()*2 - 1) * p.config.PollingJitter * float64(delay)
+	return delay + time.Duration(offset)
+}
+
+// jitterSpread returns a random extra delay of up to polling_jitter of the given delay
+func (p *Poller) jitterSpread(delay time.Duration) time.Duration {
+	if p.config.PollingJitter <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Float64() * p.config.PollingJitter * float64(delay))
+}
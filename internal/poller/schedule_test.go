@@ -0,0 +1,112 @@
+package poller
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/rootly/edge-connector/internal/api"
+	"github.com/rootly/edge-connector/internal/config"
+)
+
+func newTestPoller(cfg *config.PollerConfig) *Poller {
+	return New(api.NewClient("http://test.com", "", "test-key", "test"), cfg, nil)
+}
+
+func TestNextDelay_AdaptsToLoad(t *testing.T) {
+	p := newTestPoller(&config.PollerConfig{
+		PollingWaitIntervalMs: 4000,
+		MinPollingIntervalMs:  1000,
+		MaxPollingIntervalMs:  16000,
+	})
+	now := time.Now()
+
+	p.load = loadFull
+	assert.Equal(t, 2*time.Second, p.nextDelay(nil, now))
+	assert.Equal(t, time.Second, p.nextDelay(nil, now))
+	assert.Equal(t, time.Second, p.nextDelay(nil, now), "Never faster than min_polling_interval_ms")
+
+	p.load = loadPartial
+	assert.Equal(t, 4*time.Second, p.nextDelay(nil, now), "Back to polling_wait_interval_ms")
+
+	p.load = loadIdle
+	assert.Equal(t, 8*time.Second, p.nextDelay(nil, now))
+	assert.Equal(t, 16*time.Second, p.nextDelay(nil, now))
+	assert.Equal(t, 16*time.Second, p.nextDelay(nil, now), "Never slower than max_polling_interval_ms")
+}
+
+func TestNextDelay_FixedWithoutBounds(t *testing.T) {
+	p := newTestPoller(&config.PollerConfig{PollingWaitIntervalMs: 4000})
+
+	p.load = loadFull
+	assert.Equal(t, 4*time.Second, p.nextDelay(nil, time.Now()))
+	p.load = loadIdle
+	assert.Equal(t, 4*time.Second, p.nextDelay(nil, time.Now()))
+}
+
+func TestNextDelay_RateLimited(t *testing.T) {
+	p := newTestPoller(&config.PollerConfig{
+		PollingWaitIntervalMs: 4000,
+		RetryOnError:          true,
+		MaxRetries:            3,
+		RetryBackoff:          "exponential",
+	})
+	now := time.Now()
+
+	err := fmt.Errorf("failed to fetch events: %w", &api.RateLimitError{
+		RateLimit: api.RateLimit{Limit: 100, Remaining: 0, Reset: now.Add(90 * time.Second)},
+	})
+	assert.Equal(t, 90*time.Second, p.nextDelay(err, now), "Waits until the quota resets")
+
+	err = &api.RateLimitError{RateLimit: api.RateLimit{Limit: -1, Remaining: -1, RetryAfter: 10 * time.Second}}
+	assert.Equal(t, 10*time.Second, p.nextDelay(err, now), "Retry-After takes precedence")
+	assert.Equal(t, 0, p.retryCount, "Rate limits are not counted as errors")
+
+	// Without a hint, a 429 backs off like any other error
+	err = &api.RateLimitError{RateLimit: api.RateLimit{Limit: -1, Remaining: -1}}
+	assert.Equal(t, 8*time.Second, p.nextDelay(err, now))
+	assert.Equal(t, 16*time.Second, p.nextDelay(errors.New("connection refused"), now))
+	assert.Equal(t, 2, p.retryCount)
+
+	p.load = loadPartial
+	assert.Equal(t, 4*time.Second, p.nextDelay(nil, now))
+	assert.Equal(t, 0, p.retryCount, "Success resets the retry count")
+}
+
+func TestNextDelay_StretchesWhenQuotaRunsLow(t *testing.T) {
+	p := newTestPoller(&config.PollerConfig{PollingWaitIntervalMs: 1000})
+	now := time.Now()
+	p.load = loadPartial
+
+	p.rateLimit = api.RateLimit{Limit: 100, Remaining: 50, Reset: now.Add(time.Minute)}
+	assert.Equal(t, time.Second, p.nextDelay(nil, now), "Plenty of quota left")
+
+	p.rateLimit = api.RateLimit{Limit: 100, Remaining: 9, Reset: now.Add(time.Minute)}
+	assert.Equal(t, 6*time.Second, p.nextDelay(nil, now), "The remaining requests are spread until the reset")
+
+	p.rateLimit = api.RateLimit{Limit: 100, Remaining: 0, Reset: now.Add(time.Minute)}
+	assert.Equal(t, time.Minute, p.nextDelay(nil, now))
+
+	p.rateLimit = api.RateLimit{Limit: 100, Remaining: 0, Reset: now.Add(-time.Second)}
+	assert.Equal(t, time.Second, p.nextDelay(nil, now), "A reset in the past no longer applies")
+}
+
+func TestJitter(t *testing.T) {
+	p := newTestPoller(&config.PollerConfig{PollingWaitIntervalMs: 1000, PollingJitter: 0.1})
+
+	seen := make(map[time.Duration]bool)
+	for range 100 {
+		delay := p.jitter(10 * time.Second)
+		assert.GreaterOrEqual(t, delay, 9*time.Second)
+		assert.LessOrEqual(t, delay, 11*time.Second)
+		seen[delay] = true
+
+		spread := p.jitterSpread(10 * time.Second)
+		assert.GreaterOrEqual(t, spread, time.Duration(0))
+		assert.LessOrEqual(t, spread, time.Second)
+	}
+	assert.Greater(t, len(seen), 1, "Delays should vary")
+}
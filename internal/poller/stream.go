@@ -0,0 +1,173 @@
+package poller
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/rootly/edge-connector/internal/api"
+	"github.com/rootly/edge-connector/internal/backoff"
+	"github.com/rootly/edge-connector/internal/metrics"
+)
+
+const (
+	// streamReconnectBase is the delay before the first reconnect; it doubles with each failed attempt
+	streamReconnectBase = time.Second
+
+	// streamBacklogSize is the number of received batches waiting to be claimed
+	// Once it is full, further batches are left unclaimed and pushed again after the visibility timeout
+	streamBacklogSize = 64
+)
+
+// Streamer receives deliveries over the API's server-sent events stream
+// While the stream is down it polls like Poller, and reconnects with exponential backoff
+type Streamer struct {
+	poller        *Poller
+	reconnectBase time.Duration
+
+	// Polling while the stream is down, stopped once a reconnect succeeds
+	stopPolling context.CancelFunc
+	pollingDone chan struct{}
+}
+
+// NewStreamer creates a stream source that claims and submits deliveries through the given poller
+func NewStreamer(p *Poller) *Streamer {
+	return &Streamer{
+		poller:        p,
+		reconnectBase: streamReconnectBase,
+	}
+}
+
+// Start receives deliveries until ctx is done
+func (s *Streamer) Start(ctx context.Context) error {
+	cfg := s.poller.config
+	idleTimeout := time.Duration(cfg.Stream.IdleTimeoutSec) * time.Second
+	maxDelay := time.Duration(cfg.Stream.ReconnectMaxSec) * time.Second
+
+	log.WithFields(log.Fields{
+		"idle_timeout":       idleTimeout,
+		"reconnect_max":      maxDelay,
+		"visibility_timeout": cfg.VisibilityTimeoutSec,
+	}).Info("Starting delivery stream")
+
+	// Deliveries are claimed off the read loop, so slow claims never stall the stream into its idle timeout
+	backlog := make(chan []api.Event, streamBacklogSize)
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for events := range backlog {
+			s.receive(ctx, events)
+		}
+	}()
+	defer func() {
+		s.stopFallback()
+		close(backlog)
+		wg.Wait()
+	}()
+
+	failures := 0
+	for {
+		err := s.poller.client.StreamDeliveries(ctx, cfg.VisibilityTimeoutSec, idleTimeout, func() {
+			failures = 0
+			s.cancelFallback()
+			metrics.SetDeliveryStreamConnected(true)
+			log.Info("Delivery stream connected")
+		}, func(events []api.Event) {
+			enqueue(backlog, events)
+		})
+		metrics.SetDeliveryStreamConnected(false)
+		if ctx.Err() != nil {
+			log.Info("Delivery stream stopped")
+			return ctx.Err()
+		}
+
+		// A server without streaming support is only asked again at the longest interval
+		failures++
+		delay := backoff.Delay(backoff.Exponential, s.reconnectBase, failures-1, maxDelay)
+		if errors.Is(err, api.ErrStreamUnavailable) {
+			delay = maxDelay
+		}
+		// A rate limited connect waits at least until the quota resets
+		var rateLimited *api.RateLimitError
+		if errors.As(err, &rateLimited) {
+			delay = max(delay, rateLimited.RateLimit.Wait(time.Now()))
+		}
+
+		log.WithFields(log.Fields{
+			"attempt":      failures,
+			"reconnect_in": delay.String(),
+		}).WithError(err).Warn("Delivery stream disconnected, polling until it reconnects")
+
+		// Keep deliveries flowing by polling right away, and until a reconnect succeeds
+		s.startFallback(ctx)
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			log.Info("Delivery stream stopped")
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// startFallback starts polling unless it is already running
+// A poll still finishing from an earlier fallback is waited for, so only one runs at a time
+func (s *Streamer) startFallback(ctx context.Context) {
+	if s.stopPolling != nil {
+		return
+	}
+	if s.pollingDone != nil {
+		<-s.pollingDone
+	}
+	pollCtx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	s.stopPolling = cancel
+	s.pollingDone = done
+	go func() {
+		defer close(done)
+		s.poller.run(pollCtx, 0)
+	}()
+}
+
+// cancelFallback stops polling without waiting, so a reconnected stream is read right away
+func (s *Streamer) cancelFallback() {
+	if s.stopPolling != nil {
+		s.stopPolling()
+		s.stopPolling = nil
+	}
+}
+
+// stopFallback stops polling and waits for a poll in progress to finish
+func (s *Streamer) stopFallback() {
+	s.cancelFallback()
+	if s.pollingDone != nil {
+		<-s.pollingDone
+		s.pollingDone = nil
+	}
+}
+
+// enqueue passes a batch to the claim loop without blocking the stream
+func enqueue(backlog chan<- []api.Event, events []api.Event) {
+	select {
+	case backlog <- events:
+	default:
+		metrics.RecordEventsRejected(rejectedUnclaimed, len(events))
+		log.WithField("unclaimed_count", len(events)).Warn("Delivery stream backlog is full, leaving deliveries unclaimed")
+	}
+}
+
+// receive claims and submits deliveries pushed over the stream
+// Deliveries left unclaimed because the worker queue is full are pushed again after the visibility timeout
+func (s *Streamer) receive(ctx context.Context, events []api.Event) {
+	if metrics.EventsReceived != nil {
+		metrics.EventsReceived.Add(float64(len(events)))
+	}
+
+	log.WithField("event_count", len(events)).Info("Received events from delivery stream")
+	s.poller.processEvents(ctx, events)
+}
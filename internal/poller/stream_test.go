@@ -0,0 +1,233 @@
+package poller_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rootly/edge-connector/internal/api"
+	"github.com/rootly/edge-connector/internal/config"
+	"github.com/rootly/edge-connector/internal/poller"
+)
+
+// streamServer is a stand-in for the Rootly API serving GET /deliveries/stream, GET /deliveries and claims
+type streamServer struct {
+	*httptest.Server
+	stream      http.HandlerFunc
+	claimDelay  time.Duration
+	mu          sync.Mutex
+	connections int
+	fetches     int
+	claimed     []string
+}
+
+func newStreamServer(t *testing.T, stream http.HandlerFunc) *streamServer {
+	s := &streamServer{stream: stream}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/deliveries/stream":
+			s.mu.Lock()
+			s.connections++
+			s.mu.Unlock()
+			s.stream(w, r)
+		case r.Method == "GET":
+			s.mu.Lock()
+			s.fetches++
+			s.mu.Unlock()
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(api.EventsResponse{Events: []api.Event{{ID: "polled-1", Type: "test.event"}}})
+		default:
+			time.Sleep(s.claimDelay)
+			s.mu.Lock()
+			s.claimed = append(s.claimed, r.URL.Path)
+			s.mu.Unlock()
+			w.WriteHeader(http.StatusOK)
+		}
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// sendDeliveries writes a deliveries message to a stream
+func sendDeliveries(t *testing.T, w http.ResponseWriter, ids ...string) {
+	events := make([]api.Event, 0, len(ids))
+	for _, id := range ids {
+		events = append(events, api.Event{ID: id, Type: "test.event"})
+	}
+	data, err := json.Marshal(api.EventsResponse{Events: events})
+	assert.NoError(t, err)
+	fmt.Fprintf(w, "event: deliveries\ndata: %s\n\n", data)
+	w.(http.Flusher).Flush()
+}
+
+func streamConfig(pollingIntervalMs int) *config.PollerConfig {
+	return &config.PollerConfig{
+		Mode:                  config.PollerModeStream,
+		PollingWaitIntervalMs: pollingIntervalMs,
+		MaxNumberOfMessages:   10,
+		VisibilityTimeoutSec:  30,
+		Stream:                config.StreamConfig{IdleTimeoutSec: 60, ReconnectMaxSec: 1},
+	}
+}
+
+func TestStreamer_ReceivesDeliveries(t *testing.T) {
+	server := newStreamServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, ": connected\n\n")
+		sendDeliveries(t, w, "delivery-1", "delivery-2")
+		<-r.Context().Done()
+	})
+
+	client := api.NewClient(server.URL, "", "test-key", "test")
+	pool := &mockWorkerPool{}
+	// Polling would take a minute, so deliveries can only arrive over the stream
+	p := poller.New(client, streamConfig(60000), pool)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan error)
+	go func() { done <- poller.NewStreamer(p).Start(ctx) }()
+
+	require.Eventually(t, func() bool { return pool.Count() == 2 }, time.Second, 10*time.Millisecond)
+	cancel()
+	require.ErrorIs(t, <-done, context.Canceled)
+
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	assert.Equal(t, []string{"/deliveries/delivery-1", "/deliveries/delivery-2"}, server.claimed)
+	assert.Equal(t, 0, server.fetches)
+}
+
+func TestStreamer_FallsBackToPolling(t *testing.T) {
+	server := newStreamServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	client := api.NewClient(server.URL, "", "test-key", "test")
+	pool := &mockWorkerPool{}
+	p := poller.New(client, streamConfig(50), pool)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+	err := poller.NewStreamer(p).Start(ctx)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	assert.GreaterOrEqual(t, pool.Count(), 1, "Deliveries are polled while the stream is unavailable")
+	assert.Equal(t, "polled-1", pool.GetSubmitted()[0].ID)
+
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	assert.Equal(t, 1, server.connections, "An unsupported stream is retried at reconnect_max_sec")
+}
+
+func TestStreamer_Reconnects(t *testing.T) {
+	var mu sync.Mutex
+	connection := 0
+	server := newStreamServer(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		connection++
+		n := connection
+		mu.Unlock()
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		sendDeliveries(t, w, fmt.Sprintf("delivery-%d", n))
+		if n > 1 {
+			<-r.Context().Done()
+		}
+		// The first connection drops right after its message
+	})
+
+	client := api.NewClient(server.URL, "", "test-key", "test")
+	pool := &mockWorkerPool{}
+	p := poller.New(client, streamConfig(60000), pool)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go poller.NewStreamer(p).Start(ctx)
+
+	// Deliveries polled while reconnecting are left out; only the stream's order matters here
+	streamed := func() []string {
+		var ids []string
+		for _, event := range pool.GetSubmitted() {
+			if event.ID != "polled-1" {
+				ids = append(ids, event.ID)
+			}
+		}
+		return ids
+	}
+	require.Eventually(t, func() bool { return len(streamed()) == 2 }, 3*time.Second, 10*time.Millisecond,
+		"Delivery from the second connection should arrive after reconnecting")
+	cancel()
+
+	assert.Equal(t, []string{"delivery-1", "delivery-2"}, streamed())
+}
+
+func TestStreamer_PollsRightAwayWhileReconnecting(t *testing.T) {
+	// Every connection drops immediately
+	server := newStreamServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+	})
+
+	client := api.NewClient(server.URL, "", "test-key", "test")
+	pool := &mockWorkerPool{}
+	// The regular polling interval is far longer than the reconnect delay
+	p := poller.New(client, streamConfig(60000), pool)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+	err := poller.NewStreamer(p).Start(ctx)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	assert.GreaterOrEqual(t, server.fetches, 1, "Deliveries should be polled as soon as the stream drops")
+	assert.GreaterOrEqual(t, pool.Count(), 1)
+}
+
+func TestStreamer_SlowClaimsDoNotStallStream(t *testing.T) {
+	server := newStreamServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		sendDeliveries(t, w, "delivery-1", "delivery-2")
+		ticker := time.NewTicker(100 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-r.Context().Done():
+				return
+			case <-ticker.C:
+				fmt.Fprint(w, ": keepalive\n\n")
+				w.(http.Flusher).Flush()
+			}
+		}
+	})
+	// Claiming both deliveries takes longer than the idle timeout
+	server.claimDelay = 800 * time.Millisecond
+
+	client := api.NewClient(server.URL, "", "test-key", "test")
+	pool := &mockWorkerPool{}
+	cfg := streamConfig(60000)
+	cfg.Stream.IdleTimeoutSec = 1
+	p := poller.New(client, cfg, pool)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan error)
+	go func() { done <- poller.NewStreamer(p).Start(ctx) }()
+
+	require.Eventually(t, func() bool { return pool.Count() == 2 }, 3*time.Second, 10*time.Millisecond)
+	cancel()
+	require.ErrorIs(t, <-done, context.Canceled)
+
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	assert.Equal(t, 1, server.connections, "Keepalives should be read while deliveries are claimed")
+	assert.Equal(t, 0, server.fetches)
+}
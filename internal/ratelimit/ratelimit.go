@@ -0,0 +1,42 @@
+package ratelimit
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// Limiter is a token bucket: tokens refill at a fixed rate up to the burst size, and each allowed call takes one
+type Limiter struct {
+	last   time.Time
+	rate   float64 // Tokens added per second
+	burst  float64
+	tokens float64
+	mu     sync.Mutex
+}
+
+// New creates a limiter allowing perSec calls per second on average and up to burst at once
+// A burst below 1 defaults to perSec rounded up (at least 1). The bucket starts full
+func New(perSec float64, burst int) *Limiter {
+	size := float64(burst)
+	if burst < 1 {
+		size = max(math.Ceil(perSec), 1)
+	}
+	return &Limiter{rate: perSec, burst: size, tokens: size}
+}
+
+// Allow takes a token if one is available at the given time
+func (l *Limiter) Allow(now time.Time) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if !l.last.IsZero() {
+		l.tokens = min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	}
+	l.last = now
+	if l.tokens < 1 {
+		return false
+	}
+	l.tokens--
+	return true
+}
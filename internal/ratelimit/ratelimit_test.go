@@ -0,0 +1,57 @@
+package ratelimit_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/rootly/edge-connector/internal/ratelimit"
+)
+
+func TestLimiter_Allow(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	limiter := ratelimit.New(2, 3)
+
+	// The bucket starts full
+	assert.True(t, limiter.Allow(now))
+	assert.True(t, limiter.Allow(now))
+	assert.True(t, limiter.Allow(now))
+	assert.False(t, limiter.Allow(now), "Burst should be exhausted")
+
+	// Two tokens per second
+	assert.True(t, limiter.Allow(now.Add(500*time.Millisecond)))
+	assert.False(t, limiter.Allow(now.Add(500*time.Millisecond)))
+
+	// Refill is capped at the burst size
+	later := now.Add(time.Hour)
+	for range 3 {
+		assert.True(t, limiter.Allow(later))
+	}
+	assert.False(t, limiter.Allow(later))
+}
+
+func TestLimiter_DefaultBurst(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		perSec float64
+		want   int
+	}{
+		{"whole rate", 5, 5},
+		{"fractional rate rounds up", 2.5, 3},
+		{"slow rate allows one", 0.1, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limiter := ratelimit.New(tt.perSec, 0)
+			allowed := 0
+			for limiter.Allow(now) {
+				allowed++
+			}
+			assert.Equal(t, tt.want, allowed)
+		})
+	}
+}
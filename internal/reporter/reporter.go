@@ -8,11 +8,18 @@ import (
 	log "github.com/sirupsen/logrus"
 
 	"github.com/rootly/edge-connector/internal/api"
+	"github.com/rootly/edge-connector/internal/capture"
 )
 
 const (
 	executionStatusCompleted = "completed"
 	executionStatusFailed    = "failed"
+
+	// defaultMaxOutputBytes is the most of stdout or stderr sent to the API when no limit is set
+	defaultMaxOutputBytes = 10000
+
+	// maxAttemptStderrBytes is the most of each retried attempt's stderr sent to the API
+	maxAttemptStderrBytes = 1000
 )
 
 // ScriptResult represents the result of a script execution
@@ -22,59 +29,172 @@ type ScriptResult struct {
 	Stderr     string
 	DurationMs int64
 	ExitCode   int
+	SkipReason string        // Why nothing ran (e.g. no when condition matched); reported as completed with this reason
+	Truncated  bool          // Stdout, stderr or the response body was cut to the output limit
+	MaxOutput  int           // Per-stream limit the output was captured with (0 uses the reporter's limit)
+	Output     *ScriptOutput // Structured result written by the script to REC_OUTPUT_FILE, if any
+	Attempts   []Attempt     // Every run of an action with a retry policy, in order (empty without retries)
+}
+
+// Attempt is the outcome of one run of an action with a retry policy
+type Attempt struct {
+	StartedAt  time.Time
+	Error      error
+	Stderr     string
+	DurationMs int64
+	ExitCode   int
+	Failed     bool
+}
+
+// ScriptOutput is the structured result a script can write to REC_OUTPUT_FILE
+type ScriptOutput struct {
+	Summary        string                 `json:"summary,omitempty"`         // Short human-readable outcome (e.g. "restarted 3 pods")
+	Links          []api.ExecutionLink    `json:"links,omitempty"`           // Links to dashboards, runbooks, logs
+	StatusOverride string                 `json:"status_override,omitempty"` // "completed" or "failed", replaces the exit code based status
+	Outputs        map[string]interface{} `json:"outputs,omitempty"`         // Key/value results
+}
+
+// Validate checks the fields a script wrote
+func (o *ScriptOutput) Validate() error {
+	if o.StatusOverride != "" && o.StatusOverride != executionStatusCompleted && o.StatusOverride != executionStatusFailed {
+		return fmt.Errorf("status_override must be %q or %q, got %q", executionStatusCompleted, executionStatusFailed, o.StatusOverride)
+	}
+	for i, link := range o.Links {
+		if link.URL == "" {
+			return fmt.Errorf("links[%d]: url is required", i)
+		}
+	}
+	return nil
+}
+
+// Failed reports whether the result counts as a failed execution
+// For HTTP actions ExitCode is the HTTP status code, so 2xx is success
+// For Script actions ExitCode is the shell exit code (0 = success, 1-255 = error)
+// A status_override in the script's structured output takes precedence
+func (r ScriptResult) Failed() bool {
+	if r.Output != nil && r.Output.StatusOverride != "" {
+		return r.Output.StatusOverride == executionStatusFailed
+	}
+	if r.Error != nil {
+		return true
+	}
+	return r.ExitCode != 0 && !(r.ExitCode >= 200 && r.ExitCode < 300)
+}
+
+// Outbox persists execution reports that could not be delivered so they can be replayed later
+type Outbox interface {
+	Enqueue(execution api.ExecutionResult, cause error) error
 }
 
 // Reporter reports execution results back to the Rootly API
 type Reporter struct {
-	client *api.Client
+	client    *api.Client
+	outbox    Outbox
+	maxOutput int
 }
 
 // New creates a new reporter
 func New(client *api.Client) *Reporter {
 	return &Reporter{
-		client: client,
+		client:    client,
+		maxOutput: defaultMaxOutputBytes,
+	}
+}
+
+// SetMaxOutputBytes sets the most of stdout or stderr sent for results without their own limit
+func (r *Reporter) SetMaxOutputBytes(n int) {
+	if n > 0 {
+		r.maxOutput = n
+	}
+}
+
+// outputLimit returns the per-stream limit for a result; an action's own limit takes precedence
+func (r *Reporter) outputLimit(result ScriptResult) int {
+	if result.MaxOutput > 0 {
+		return result.MaxOutput
 	}
+	return r.maxOutput
+}
+
+// SetOutbox sets the outbox used to keep reports that fail to send
+func (r *Reporter) SetOutbox(outbox Outbox) {
+	r.outbox = outbox
 }
 
 // Report reports an execution result to the Rootly API
+// If sending fails and an outbox is configured, the report is stored for replay instead of being lost
 func (r *Reporter) Report(ctx context.Context, deliveryID, actionName, actionUUID string, result ScriptResult) error {
 	executionStatus := executionStatusCompleted
 	errorMsg := ""
 	timestamp := time.Now().UTC().Format(time.RFC3339) // ISO 8601 format
 
 	// Determine execution status based on Error field and ExitCode
-	// For HTTP actions: ExitCode is HTTP status code (200, 404, 500, etc.)
-	// For Script actions: ExitCode is shell exit code (0 = success, 1-255 = error)
-	if result.Error != nil {
+	if result.Failed() {
 		executionStatus = executionStatusFailed
+	}
+	if result.Error != nil {
 		errorMsg = result.Error.Error()
-	} else if result.ExitCode != 0 && !(result.ExitCode >= 200 && result.ExitCode < 300) {
-		// Non-zero exit code, but not HTTP 2xx (which is success)
-		// This handles edge cases where Error is nil but script failed
-		executionStatus = executionStatusFailed
 	}
 
+	// Output is capped when captured, so this only cuts results built elsewhere, such as pipelines
+	limit := r.outputLimit(result)
+	stdout, stdoutTruncated := capture.Truncate(result.Stdout, limit)
+	stderr, stderrTruncated := capture.Truncate(result.Stderr, limit)
+
 	execution := api.ExecutionResult{
 		DeliveryID:          deliveryID,
 		ExecutionStatus:     executionStatus,
 		ExecutionExitCode:   result.ExitCode,
-		ExecutionStdout:     result.Stdout,
-		ExecutionStderr:     result.Stderr,
+		ExecutionStdout:     stdout,
+		ExecutionStderr:     stderr,
 		ExecutionDurationMs: result.DurationMs,
 		ExecutionError:      errorMsg,
 		ExecutionActionName: actionName, // Action slug from config (e.g., "test_manual_action_http")
 		ExecutionActionID:   actionUUID, // Action UUID from event (e.g., "01939a0e-...", empty for non-action events)
+
+		ExecutionOutputTruncated: result.Truncated || stdoutTruncated || stderrTruncated,
+	}
+	if len(result.Attempts) > 0 {
+		execution.ExecutionAttempts = executionAttempts(result.Attempts)
+	}
+	if result.Output != nil {
+		execution.ExecutionSummary = result.Output.Summary
+		execution.ExecutionLinks = result.Output.Links
+		execution.ExecutionOutputs = result.Output.Outputs
+	}
+	// The API has no skipped status, so a skipped delivery completes with the reason as its summary
+	if result.SkipReason != "" && executionStatus == executionStatusCompleted {
+		execution.ExecutionSummary = "Skipped: " + result.SkipReason
+		if execution.ExecutionStdout == "" {
+			execution.ExecutionStdout = execution.ExecutionSummary
+		}
 	}
 
 	// Set appropriate timestamp based on status
-	if executionStatus == executionStatusCompleted {
-		execution.CompletedAt = timestamp
-	} else if executionStatus == executionStatusFailed {
+	if executionStatus == executionStatusFailed {
 		execution.FailedAt = timestamp
+	} else {
+		execution.CompletedAt = timestamp
 	}
 
 	if err := r.client.ReportExecution(ctx, execution); err != nil {
-		return fmt.Errorf("failed to report execution: %w", err)
+		if r.outbox == nil {
+			return fmt.Errorf("failed to report execution: %w", err)
+		}
+		if !api.IsRetryable(err) {
+			// Resending a report the API rejected would only fail again
+			return fmt.Errorf("failed to report execution, rejected by API: %w", err)
+		}
+		if enqueueErr := r.outbox.Enqueue(execution, err); enqueueErr != nil {
+			return fmt.Errorf("failed to report execution: %w (outbox: %v)", err, enqueueErr)
+		}
+
+		log.WithError(err).WithFields(log.Fields{
+			"delivery_id":      deliveryID,
+			"action_name":      actionName,
+			"execution_status": executionStatus,
+		}).Warn("Failed to report execution result, saved to outbox for retry")
+		return nil
 	}
 
 	log.WithFields(log.Fields{
@@ -87,3 +207,49 @@ func (r *Reporter) Report(ctx context.Context, deliveryID, actionName, actionUUI
 
 	return nil
 }
+
+// executionAttempts converts the attempt history for the execution report
+func executionAttempts(attempts []Attempt) []api.ExecutionAttempt {
+	converted := make([]api.ExecutionAttempt, 0, len(attempts))
+	for i, attempt := range attempts {
+		status := executionStatusCompleted
+		if attempt.Failed {
+			status = executionStatusFailed
+		}
+		errorMsg := ""
+		if attempt.Error != nil {
+			errorMsg = attempt.Error.Error()
+		}
+		stderr, _ := capture.Truncate(attempt.Stderr, maxAttemptStderrBytes)
+		converted = append(converted, api.ExecutionAttempt{
+			Attempt:    i + 1,
+			Status:     status,
+			StartedAt:  attempt.StartedAt.UTC().Format(time.RFC3339),
+			ExitCode:   attempt.ExitCode,
+			Error:      errorMsg,
+			Stderr:     stderr,
+			DurationMs: attempt.DurationMs,
+		})
+	}
+	return converted
+}
+
+// ReportProgress sends the output of a delivery that is still running
+// Only Stdout, Stderr, Truncated and MaxOutput of the result are used. Progress updates are best effort
+// and never go to the outbox; the final Report carries the complete result
+func (r *Reporter) ReportProgress(ctx context.Context, deliveryID string, result ScriptResult) error {
+	limit := r.outputLimit(result)
+	stdout, stdoutTruncated := capture.Truncate(result.Stdout, limit)
+	stderr, stderrTruncated := capture.Truncate(result.Stderr, limit)
+
+	progress := api.ExecutionProgress{
+		DeliveryID:               deliveryID,
+		ExecutionStdout:          stdout,
+		ExecutionStderr:          stderr,
+		ExecutionOutputTruncated: result.Truncated || stdoutTruncated || stderrTruncated,
+	}
+	if err := r.client.ReportProgress(ctx, progress); err != nil {
+		return fmt.Errorf("failed to report progress: %w", err)
+	}
+	return nil
+}
@@ -1,9 +1,11 @@
 package reporter_test
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -14,6 +16,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/rootly/edge-connector/internal/api"
+	"github.com/rootly/edge-connector/internal/capture"
 	"github.com/rootly/edge-connector/internal/reporter"
 )
 
@@ -69,6 +72,231 @@ func TestReporter_Report_Success(t *testing.T) {
 	assert.Empty(t, receivedExecution.FailedAt)
 }
 
+func TestReporter_Report_Skipped(t *testing.T) {
+	var receivedExecution api.ExecutionResult
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		err := json.NewDecoder(r.Body).Decode(&receivedExecution)
+		require.NoError(t, err)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	rep := reporter.New(api.NewClient(server.URL, "", "test-key", "test"))
+
+	result := reporter.ScriptResult{
+		SkipReason: "when condition not met for alert.created",
+	}
+
+	err := rep.Report(context.Background(), "delivery-123", "alert.created", "", result)
+	require.NoError(t, err)
+
+	assert.Equal(t, "completed", receivedExecution.ExecutionStatus, "The API has no skipped status")
+	assert.Equal(t, "Skipped: when condition not met for alert.created", receivedExecution.ExecutionSummary)
+	assert.Equal(t, "Skipped: when condition not met for alert.created", receivedExecution.ExecutionStdout)
+	assert.Equal(t, "", receivedExecution.ExecutionError)
+	assert.NotEmpty(t, receivedExecution.CompletedAt)
+	assert.Empty(t, receivedExecution.FailedAt)
+}
+
+func TestReporter_Report_StructuredOutput(t *testing.T) {
+	var receivedExecution api.ExecutionResult
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		err := json.NewDecoder(r.Body).Decode(&receivedExecution)
+		require.NoError(t, err)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	rep := reporter.New(api.NewClient(server.URL, "", "test-key", "test"))
+
+	result := reporter.ScriptResult{
+		ExitCode: 0,
+		Stdout:   "long log output",
+		Output: &reporter.ScriptOutput{
+			Summary:        "restarted 3 pods",
+			Links:          []api.ExecutionLink{{Title: "Dashboard", URL: "https://grafana.example.com/d/api"}},
+			StatusOverride: "failed",
+			Outputs:        map[string]interface{}{"pods": float64(3)},
+		},
+	}
+
+	err := rep.Report(context.Background(), "delivery-123", "restart_service", "", result)
+	require.NoError(t, err)
+
+	assert.Equal(t, "failed", receivedExecution.ExecutionStatus, "status_override should replace the exit code status")
+	assert.NotEmpty(t, receivedExecution.FailedAt)
+	assert.Equal(t, "restarted 3 pods", receivedExecution.ExecutionSummary)
+	assert.Equal(t, []api.ExecutionLink{{Title: "Dashboard", URL: "https://grafana.example.com/d/api"}}, receivedExecution.ExecutionLinks)
+	assert.Equal(t, map[string]interface{}{"pods": float64(3)}, receivedExecution.ExecutionOutputs)
+}
+
+func TestReporter_Report_OutputTruncated(t *testing.T) {
+	var receivedExecution api.ExecutionResult
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		err := json.NewDecoder(r.Body).Decode(&receivedExecution)
+		require.NoError(t, err)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	rep := reporter.New(api.NewClient(server.URL, "", "test-key", "test"))
+
+	// Already truncated when captured
+	err := rep.Report(context.Background(), "delivery-123", "chatty", "", reporter.ScriptResult{Stdout: "short", Truncated: true})
+	require.NoError(t, err)
+	assert.True(t, receivedExecution.ExecutionOutputTruncated)
+	assert.Equal(t, "short", receivedExecution.ExecutionStdout)
+
+	// Oversized output is capped before sending
+	receivedExecution = api.ExecutionResult{}
+	err = rep.Report(context.Background(), "delivery-124", "chatty", "", reporter.ScriptResult{Stderr: strings.Repeat("e", 50000)})
+	require.NoError(t, err)
+	assert.True(t, receivedExecution.ExecutionOutputTruncated)
+	assert.LessOrEqual(t, len(receivedExecution.ExecutionStderr), 10000)
+	assert.Contains(t, receivedExecution.ExecutionStderr, "bytes truncated")
+
+	// Small output is sent as-is
+	receivedExecution = api.ExecutionResult{}
+	err = rep.Report(context.Background(), "delivery-125", "chatty", "", reporter.ScriptResult{Stdout: "ok"})
+	require.NoError(t, err)
+	assert.False(t, receivedExecution.ExecutionOutputTruncated)
+}
+
+func TestReporter_Report_KeepsCapturedTruncationMarker(t *testing.T) {
+	var receivedExecution api.ExecutionResult
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		err := json.NewDecoder(r.Body).Decode(&receivedExecution)
+		require.NoError(t, err)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	rep := reporter.New(api.NewClient(server.URL, "", "test-key", "test"))
+
+	// 62 KB of script output captured with the default 10000 byte cap
+	var full bytes.Buffer
+	buf := capture.NewBuffer(10000)
+	buf.SpillTo(&full, "/var/log/rec/delivery-1-stdout.log")
+	_, err := buf.Write([]byte(strings.Repeat("Q", 31000) + strings.Repeat("Z", 31000)))
+	require.NoError(t, err)
+
+	result := reporter.ScriptResult{Stdout: buf.String(), Truncated: buf.Truncated(), MaxOutput: buf.Limit()}
+	err = rep.Report(context.Background(), "delivery-1", "chatty", "", result)
+	require.NoError(t, err)
+
+	stdout := receivedExecution.ExecutionStdout
+	assert.Equal(t, buf.String(), stdout, "Captured output should not be truncated a second time")
+	dropped := 62000 - strings.Count(stdout, "Q") - strings.Count(stdout, "Z")
+	assert.Contains(t, stdout, fmt.Sprintf("[%d bytes truncated, full output in /var/log/rec/delivery-1-stdout.log]", dropped))
+	assert.Greater(t, dropped, 50000)
+	assert.True(t, receivedExecution.ExecutionOutputTruncated)
+}
+
+func TestReporter_Report_OutputLimitAboveDefault(t *testing.T) {
+	var receivedExecution api.ExecutionResult
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		err := json.NewDecoder(r.Body).Decode(&receivedExecution)
+		require.NoError(t, err)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	rep := reporter.New(api.NewClient(server.URL, "", "test-key", "test"))
+	output := strings.Repeat("x", 15000)
+
+	// Per-action limit
+	err := rep.Report(context.Background(), "delivery-1", "chatty", "", reporter.ScriptResult{Stdout: output, MaxOutput: 20000})
+	require.NoError(t, err)
+	assert.Equal(t, output, receivedExecution.ExecutionStdout)
+	assert.False(t, receivedExecution.ExecutionOutputTruncated)
+
+	// Global output.max_bytes
+	rep.SetMaxOutputBytes(20000)
+	receivedExecution = api.ExecutionResult{}
+	err = rep.Report(context.Background(), "delivery-2", "chatty", "", reporter.ScriptResult{Stdout: output})
+	require.NoError(t, err)
+	assert.Equal(t, output, receivedExecution.ExecutionStdout)
+	assert.False(t, receivedExecution.ExecutionOutputTruncated)
+}
+
+func TestReporter_ReportProgress(t *testing.T) {
+	var received api.ExecutionProgress
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/deliveries/delivery-123", r.URL.Path)
+		err := json.NewDecoder(r.Body).Decode(&received)
+		require.NoError(t, err)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	rep := reporter.New(api.NewClient(server.URL, "", "test-key", "test"))
+
+	err := rep.ReportProgress(context.Background(), "delivery-123", reporter.ScriptResult{
+		Stdout: "replica promoted\n",
+		Stderr: strings.Repeat("w", 50000),
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "running", received.ExecutionStatus)
+	assert.Equal(t, "replica promoted\n", received.ExecutionStdout)
+	assert.Contains(t, received.ExecutionStderr, "bytes truncated")
+	assert.True(t, received.ExecutionOutputTruncated)
+}
+
+func TestReporter_Report_Attempts(t *testing.T) {
+	var receivedExecution api.ExecutionResult
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		err := json.NewDecoder(r.Body).Decode(&receivedExecution)
+		require.NoError(t, err)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	rep := reporter.New(api.NewClient(server.URL, "", "test-key", "test"))
+
+	started := time.Date(2025, 10, 26, 20, 0, 0, 0, time.UTC)
+	result := reporter.ScriptResult{
+		ExitCode:   0,
+		DurationMs: 3500,
+		Attempts: []reporter.Attempt{
+			{StartedAt: started, ExitCode: 75, Error: errors.New("exit status 75"), Stderr: strings.Repeat("x", 5000), DurationMs: 1000, Failed: true},
+			{StartedAt: started.Add(2 * time.Second), ExitCode: 0, DurationMs: 1500},
+		},
+	}
+
+	err := rep.Report(context.Background(), "delivery-123", "flaky", "", result)
+	require.NoError(t, err)
+
+	assert.Equal(t, "completed", receivedExecution.ExecutionStatus)
+	require.Len(t, receivedExecution.ExecutionAttempts, 2)
+
+	first := receivedExecution.ExecutionAttempts[0]
+	assert.Equal(t, 1, first.Attempt)
+	assert.Equal(t, "failed", first.Status)
+	assert.Equal(t, 75, first.ExitCode)
+	assert.Equal(t, "exit status 75", first.Error)
+	assert.Equal(t, "2025-10-26T20:00:00Z", first.StartedAt)
+	assert.Less(t, len(first.Stderr), 1100, "Attempt stderr should be truncated")
+
+	second := receivedExecution.ExecutionAttempts[1]
+	assert.Equal(t, 2, second.Attempt)
+	assert.Equal(t, "completed", second.Status)
+	assert.Equal(t, int64(1500), second.DurationMs)
+}
+
+func TestScriptOutput_Validate(t *testing.T) {
+	assert.NoError(t, (&reporter.ScriptOutput{StatusOverride: "completed"}).Validate())
+	assert.ErrorContains(t, (&reporter.ScriptOutput{StatusOverride: "skipped"}).Validate(), "status_override must be")
+	assert.ErrorContains(t, (&reporter.ScriptOutput{Links: []api.ExecutionLink{{Title: "Logs"}}}).Validate(), "links[0]: url is required")
+}
+
 func TestReporter_Report_FailureWithError(t *testing.T) {
 	var receivedExecution api.ExecutionResult
 
@@ -231,3 +459,109 @@ func TestReporter_New(t *testing.T) {
 
 	assert.NotNil(t, rep)
 }
+
+type fakeOutbox struct {
+	err       error
+	cause     error
+	execution *api.ExecutionResult
+}
+
+func (f *fakeOutbox) Enqueue(execution api.ExecutionResult, cause error) error {
+	f.execution = &execution
+	f.cause = cause
+	return f.err
+}
+
+func TestReporter_Report_APIErrorSavedToOutbox(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusRequestTimeout)
+	}))
+	defer server.Close()
+
+	client := api.NewClient(server.URL, "", "test-key", "test")
+	rep := reporter.New(client)
+	ob := &fakeOutbox{}
+	rep.SetOutbox(ob)
+
+	result := reporter.ScriptResult{
+		Error:      errors.New("script failed"),
+		Stderr:     "boom",
+		DurationMs: 100,
+		ExitCode:   2,
+	}
+
+	err := rep.Report(context.Background(), "delivery-outbox", "test_action", "action-uuid", result)
+	require.NoError(t, err, "Report should succeed once the result is saved to the outbox")
+
+	require.NotNil(t, ob.execution)
+	assert.Equal(t, "delivery-outbox", ob.execution.DeliveryID)
+	assert.Equal(t, "failed", ob.execution.ExecutionStatus)
+	assert.Equal(t, "script failed", ob.execution.ExecutionError)
+	assert.Equal(t, "action-uuid", ob.execution.ExecutionActionID)
+	assert.NotEmpty(t, ob.execution.FailedAt)
+	require.Error(t, ob.cause)
+	assert.Contains(t, ob.cause.Error(), "unexpected status code: 408")
+}
+
+func TestReporter_Report_PermanentErrorNotSavedToOutbox(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+	}))
+	defer server.Close()
+
+	client := api.NewClient(server.URL, "", "test-key", "test")
+	rep := reporter.New(client)
+	ob := &fakeOutbox{}
+	rep.SetOutbox(ob)
+
+	err := rep.Report(context.Background(), "delivery-outbox", "test_action", "", reporter.ScriptResult{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "rejected by API")
+	assert.Nil(t, ob.execution, "A report the API rejected should not be replayed")
+}
+
+func TestReporter_Report_OutboxFailureReturnsError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusRequestTimeout)
+	}))
+	defer server.Close()
+
+	client := api.NewClient(server.URL, "", "test-key", "test")
+	rep := reporter.New(client)
+	rep.SetOutbox(&fakeOutbox{err: errors.New("disk full")})
+
+	err := rep.Report(context.Background(), "delivery-outbox", "test_action", "", reporter.ScriptResult{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to report execution")
+	assert.Contains(t, err.Error(), "disk full")
+}
+
+func TestScriptResult_Failed(t *testing.T) {
+	tests := []struct {
+		name   string
+		result reporter.ScriptResult
+		failed bool
+	}{
+		{name: "script success", result: reporter.ScriptResult{ExitCode: 0}, failed: false},
+		{name: "script non-zero exit", result: reporter.ScriptResult{ExitCode: 2}, failed: true},
+		{name: "http 2xx", result: reporter.ScriptResult{ExitCode: 204}, failed: false},
+		{name: "http 5xx", result: reporter.ScriptResult{ExitCode: 503}, failed: true},
+		{name: "error with zero exit", result: reporter.ScriptResult{Error: errors.New("timeout")}, failed: true},
+		{
+			name:   "status override failed",
+			result: reporter.ScriptResult{ExitCode: 0, Output: &reporter.ScriptOutput{StatusOverride: "failed"}},
+			failed: true,
+		},
+		{
+			name:   "status override completed",
+			result: reporter.ScriptResult{ExitCode: 3, Output: &reporter.ScriptOutput{StatusOverride: "completed"}},
+			failed: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.failed, tt.result.Failed())
+		})
+	}
+}
@@ -2,7 +2,9 @@ package worker
 
 import (
 	"context"
+	"errors"
 	"sync"
+	"time"
 
 	log "github.com/sirupsen/logrus"
 
@@ -16,22 +18,50 @@ const (
 	fieldDeliveryID = "delivery_id"
 	fieldEventID    = "event_id"
 	fieldEventType  = "event_type"
+	fieldWorkerID   = "worker_id"
 )
 
+// Reasons reported for deliveries that a graceful drain could not complete
+var (
+	// ErrShutdownTimeout is the cancellation cause for actions still running when the drain deadline passes
+	ErrShutdownTimeout = errors.New("canceled by connector shutdown: shutdown_timeout_sec exceeded")
+
+	// ErrShutdownBeforeStart is reported for queued deliveries that never started before shutdown
+	ErrShutdownBeforeStart = errors.New("not started: connector shut down before the action ran")
+)
+
+// defaultCancelGracePeriod is how long Drain waits for canceled actions to return before abandoning them
+const defaultCancelGracePeriod = 10 * time.Second
+
 // Executor interface for processing events
 type Executor interface {
 	Execute(ctx context.Context, event api.Event)
 }
 
-// Pool manages a pool of workers for processing events
+// Pool manages an elastic pool of workers for processing events
+// The pool keeps minWorkers core workers alive and grows toward maxWorkers when the queue backs up.
+// Extra workers retire after being idle for keepAlive.
 type Pool struct {
-	queue      chan api.Event
-	ctx        context.Context
-	cancel     context.CancelFunc
-	executor   Executor
-	wg         sync.WaitGroup
-	maxWorkers int
-	minWorkers int
+	queue            chan api.Event
+	ctx              context.Context // Pool lifecycle (canceled on Shutdown, stops the monitor)
+	cancel           context.CancelFunc
+	runCtx           context.Context // Execution context passed to Start (used by all workers)
+	drainCh          chan struct{}   // Closed by Drain so workers stop taking events from the queue
+	executor         Executor
+	wg               sync.WaitGroup
+	mu               sync.Mutex
+	maxWorkers       int
+	minWorkers       int
+	workers          int               // Current number of worker goroutines (guarded by mu)
+	busy             int               // Workers currently executing an event (guarded by mu)
+	running          map[int]api.Event // Event each busy worker is executing, by worker ID (guarded by mu)
+	nextWorkerID     int
+	stopped          bool
+	draining         bool
+	notStarted       []api.Event // Events taken off the queue after draining began (guarded by mu)
+	keepAlive        time.Duration
+	monitoringPeriod time.Duration
+	cancelGrace      time.Duration // How long Drain waits for canceled actions to return
 }
 
 // NewPool creates a new worker pool
@@ -41,83 +71,274 @@ func NewPool(cfg *config.PoolConfig, executor Executor) *Pool {
 		queueSize = 1000 // Default queue size
 	}
 
+	keepAlive := time.Duration(cfg.KeepAliveTimeMs) * time.Millisecond
+	if keepAlive <= 0 {
+		keepAlive = 60 * time.Second // Default keep-alive
+	}
+
+	monitoringPeriod := time.Duration(cfg.MonitoringPeriodMs) * time.Millisecond
+	if monitoringPeriod <= 0 {
+		monitoringPeriod = 30 * time.Second // Default monitoring period
+	}
+
 	ctx, cancel := context.WithCancel(context.Background())
 
 	return &Pool{
-		maxWorkers: cfg.MaxNumberOfWorkers,
-		minWorkers: cfg.MinNumberOfWorkers,
-		queue:      make(chan api.Event, queueSize),
-		executor:   executor,
-		ctx:        ctx,
-		cancel:     cancel,
+		maxWorkers:       cfg.MaxNumberOfWorkers,
+		minWorkers:       cfg.MinNumberOfWorkers,
+		keepAlive:        keepAlive,
+		monitoringPeriod: monitoringPeriod,
+		queue:            make(chan api.Event, queueSize),
+		executor:         executor,
+		drainCh:          make(chan struct{}),
+		running:          make(map[int]api.Event),
+		cancelGrace:      defaultCancelGracePeriod,
+		ctx:              ctx,
+		cancel:           cancel,
 	}
 }
 
-// Start starts the worker pool with minimum number of workers
+// SetCancelGracePeriod sets how long Drain waits for canceled actions to return before abandoning them
+func (p *Pool) SetCancelGracePeriod(d time.Duration) {
+	p.cancelGrace = d
+}
+
+// Start starts the worker pool with minimum number of workers and the monitor loop
 func (p *Pool) Start(ctx context.Context) {
 	log.WithFields(log.Fields{
-		"min_workers": p.minWorkers,
-		"max_workers": p.maxWorkers,
-		"queue_size":  cap(p.queue),
+		"min_workers":       p.minWorkers,
+		"max_workers":       p.maxWorkers,
+		"queue_size":        cap(p.queue),
+		"keep_alive":        p.keepAlive,
+		"monitoring_period": p.monitoringPeriod,
 	}).Info("Starting worker pool")
 
-	// Set initial pool size metric
-	if metrics.WorkerPoolSize != nil {
-		metrics.WorkerPoolSize.Set(float64(p.minWorkers))
-	}
-
-	// Start minimum number of workers
+	p.mu.Lock()
+	p.runCtx = ctx
+	// Start minimum number of core workers (never retire)
 	for i := 0; i < p.minWorkers; i++ {
-		p.wg.Add(1)
-		go p.worker(ctx, i+1)
+		p.spawnLocked(true)
 	}
+	p.mu.Unlock()
+
+	go p.monitor(ctx)
 }
 
 // Submit submits an event to the worker pool for processing
-func (p *Pool) Submit(event api.Event) {
+// Returns false if the queue is full or the pool is draining; the caller owns the rejected event
+func (p *Pool) Submit(event api.Event) bool {
+	fields := log.Fields{
+		fieldDeliveryID: event.ID,
+		fieldEventID:    event.EventID,
+		fieldEventType:  event.Type,
+	}
+
+	// Hold mu so the send cannot race with Drain or Shutdown closing the queue
+	p.mu.Lock()
+	if p.stopped {
+		p.mu.Unlock()
+		log.WithFields(fields).Warn("Worker pool is shutting down, rejecting event")
+		return false
+	}
 	select {
 	case p.queue <- event:
-		// Update queue size metric
-		if metrics.WorkerPoolQueueSize != nil {
-			metrics.WorkerPoolQueueSize.Set(float64(len(p.queue)))
-		}
-		log.WithFields(log.Fields{
-			fieldDeliveryID: event.ID,
-			fieldEventID:    event.EventID,
-			fieldEventType:  event.Type,
-		}).Debug("Event submitted to worker pool")
+		p.mu.Unlock()
 	default:
+		p.mu.Unlock()
+		log.WithFields(fields).Warn("Worker pool queue is full, rejecting event")
+		return false
+	}
+
+	// Update queue size metric
+	if metrics.WorkerPoolQueueSize != nil {
+		metrics.WorkerPoolQueueSize.Set(float64(len(p.queue)))
+	}
+	log.WithFields(fields).Debug("Event submitted to worker pool")
+	p.scaleUp()
+	return true
+}
+
+// scaleUp adds workers while queued events outnumber idle workers, up to maxWorkers
+func (p *Pool) scaleUp() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	// Not started yet, already stopped, or execution context canceled
+	if p.runCtx == nil || p.stopped || p.runCtx.Err() != nil {
+		return
+	}
+
+	for p.workers < p.maxWorkers && len(p.queue) > p.workers-p.busy {
+		p.spawnLocked(false)
 		log.WithFields(log.Fields{
-			fieldDeliveryID: event.ID,
-			fieldEventID:    event.EventID,
-			fieldEventType:  event.Type,
-		}).Warn("Worker pool queue is full, dropping event")
+			"workers":    p.workers,
+			"queue_size": len(p.queue),
+		}).Debug("Scaled up worker pool")
+	}
+}
+
+// spawnLocked starts a new worker goroutine; callers must hold p.mu
+func (p *Pool) spawnLocked(core bool) {
+	p.nextWorkerID++
+	p.workers++
+	p.wg.Add(1)
+	go p.worker(p.runCtx, p.nextWorkerID, core)
+
+	if metrics.WorkerPoolSize != nil {
+		metrics.WorkerPoolSize.Set(float64(p.workers))
 	}
 }
 
 // worker is a worker goroutine that processes events from the queue
-func (p *Pool) worker(ctx context.Context, workerID int) {
+// Core workers run until shutdown; extra workers retire after keepAlive without work
+func (p *Pool) worker(ctx context.Context, workerID int, core bool) {
 	defer p.wg.Done()
 
-	log.WithField("worker_id", workerID).Debug("Worker started")
+	retired := false
+	defer func() {
+		if !retired {
+			p.retire()
+		}
+	}()
+
+	log.WithFields(log.Fields{
+		fieldWorkerID: workerID,
+		"core":        core,
+	}).Debug("Worker started")
+
+	// Core workers never time out (nil channel blocks forever)
+	var idleTimer *time.Timer
+	var idle <-chan time.Time
+	if !core {
+		idleTimer = time.NewTimer(p.keepAlive)
+		defer idleTimer.Stop()
+		idle = idleTimer.C
+	}
 
 	for {
 		select {
 		case <-ctx.Done():
-			log.WithField("worker_id", workerID).Debug("Worker stopped")
+			log.WithField(fieldWorkerID, workerID).Debug("Worker stopped")
+			return
+		case <-p.drainCh:
+			log.WithField(fieldWorkerID, workerID).Debug("Pool draining, worker exiting")
 			return
+		case <-idle:
+			if p.tryRetire() {
+				retired = true
+				log.WithField(fieldWorkerID, workerID).Debug("Idle worker retired after keep-alive time")
+				return
+			}
+			idleTimer.Reset(p.keepAlive)
 		case event, ok := <-p.queue:
 			if !ok {
-				log.WithField("worker_id", workerID).Debug("Queue closed, worker exiting")
+				log.WithField(fieldWorkerID, workerID).Debug("Queue closed, worker exiting")
+				return
+			}
+			// select picks randomly among ready cases, so an event can still arrive after draining began
+			if p.keepIfDraining(event) {
 				return
 			}
 			log.WithFields(log.Fields{
-				"worker_id":     workerID,
+				fieldWorkerID:   workerID,
 				fieldDeliveryID: event.ID,
 				fieldEventID:    event.EventID,
 				fieldEventType:  event.Type,
 			}).Debug("Worker processing event")
+
+			p.setRunning(workerID, &event)
 			p.executor.Execute(ctx, event)
+			p.setRunning(workerID, nil)
+
+			if idleTimer != nil {
+				if !idleTimer.Stop() {
+					select {
+					case <-idleTimer.C:
+					default:
+					}
+				}
+				idleTimer.Reset(p.keepAlive)
+			}
+		}
+	}
+}
+
+// tryRetire reports whether an idle extra worker may exit (pool stays at or above minWorkers)
+// On success the worker is removed from the count so concurrent retirements cannot undershoot
+func (p *Pool) tryRetire() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.workers <= p.minWorkers || len(p.queue) > 0 {
+		return false
+	}
+	p.workers--
+	p.updateSizeMetricLocked()
+	return true
+}
+
+// retire decrements the worker count for workers exiting for any reason other than idle retirement
+func (p *Pool) retire() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.workers > 0 {
+		p.workers--
+	}
+	p.updateSizeMetricLocked()
+}
+
+// setRunning records the event a worker started executing, or that it finished (nil event)
+func (p *Pool) setRunning(workerID int, event *api.Event) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if event == nil {
+		delete(p.running, workerID)
+		p.busy--
+		return
+	}
+	p.running[workerID] = *event
+	p.busy++
+}
+
+// updateSizeMetricLocked publishes the current worker count; callers must hold p.mu
+func (p *Pool) updateSizeMetricLocked() {
+	if metrics.WorkerPoolSize != nil {
+		metrics.WorkerPoolSize.Set(float64(p.workers))
+	}
+}
+
+// monitor periodically logs pool health, refreshes metrics and scales up if the queue backed up
+func (p *Pool) monitor(ctx context.Context) {
+	ticker := time.NewTicker(p.monitoringPeriod)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-p.ctx.Done():
+			return
+		case <-ticker.C:
+			p.scaleUp()
+
+			p.mu.Lock()
+			workers, busy := p.workers, p.busy
+			p.updateSizeMetricLocked()
+			p.mu.Unlock()
+
+			queued := len(p.queue)
+			if metrics.WorkerPoolQueueSize != nil {
+				metrics.WorkerPoolQueueSize.Set(float64(queued))
+			}
+
+			log.WithFields(log.Fields{
+				"workers":        workers,
+				"busy_workers":   busy,
+				"idle_workers":   workers - busy,
+				"min_workers":    p.minWorkers,
+				"max_workers":    p.maxWorkers,
+				"queue_size":     queued,
+				"queue_capacity": cap(p.queue),
+			}).Info("Worker pool health")
 		}
 	}
 }
@@ -125,11 +346,115 @@ func (p *Pool) worker(ctx context.Context, workerID int) {
 // Shutdown gracefully shuts down the worker pool
 func (p *Pool) Shutdown() {
 	log.Info("Shutting down worker pool")
+	p.mu.Lock()
+	p.stopped = true
+	p.mu.Unlock()
+	p.cancel()
+
 	close(p.queue)
 	p.wg.Wait()
 	log.Info("Worker pool shut down complete")
 }
 
+// Drain performs a graceful two-phase stop
+// New submissions are rejected and workers stop taking queued events while in-flight events
+// are given up to timeout to finish. If the deadline passes, cancelRunning is called so the
+// remaining executions are cut off (and report their own failure) before Drain returns.
+// Actions that still have not returned after the cancel grace period are abandoned and logged;
+// they stay in the delivery journal and are recovered on the next start.
+// The events that were queued but never started are returned for the caller to report.
+func (p *Pool) Drain(timeout time.Duration, cancelRunning func()) []api.Event {
+	p.mu.Lock()
+	p.stopped = true
+	p.draining = true
+	busy := p.busy
+	p.mu.Unlock()
+	close(p.drainCh)
+	p.cancel()
+
+	log.WithFields(log.Fields{
+		"in_flight":  busy,
+		"queue_size": len(p.queue),
+		"timeout":    timeout,
+	}).Info("Draining worker pool")
+
+	done := make(chan struct{})
+	go func() {
+		p.wg.Wait()
+		close(done)
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case <-done:
+		log.Info("In-flight actions finished")
+	case <-timer.C:
+		p.mu.Lock()
+		busy = p.busy
+		p.mu.Unlock()
+		log.WithField("in_flight", busy).Warn("Shutdown timeout reached, canceling running actions")
+		if cancelRunning != nil {
+			cancelRunning()
+		}
+		p.waitCanceled(done)
+	}
+
+	// Nothing can be added to the queue anymore (stopped), so collect what is left
+	p.mu.Lock()
+	notStarted := p.notStarted
+	p.notStarted = nil
+	p.mu.Unlock()
+	for {
+		select {
+		case event := <-p.queue:
+			notStarted = append(notStarted, event)
+		default:
+			if metrics.WorkerPoolQueueSize != nil {
+				metrics.WorkerPoolQueueSize.Set(0)
+			}
+			log.WithField("not_started", len(notStarted)).Info("Worker pool drained")
+			return notStarted
+		}
+	}
+}
+
+// waitCanceled waits up to the cancel grace period for canceled actions to return
+// Workers whose action ignores cancellation are abandoned so shutdown is not blocked forever
+func (p *Pool) waitCanceled(done <-chan struct{}) {
+	grace := time.NewTimer(p.cancelGrace)
+	defer grace.Stop()
+
+	select {
+	case <-done:
+		log.Info("Canceled actions finished")
+	case <-grace.C:
+		p.mu.Lock()
+		for workerID, event := range p.running {
+			log.WithFields(log.Fields{
+				fieldWorkerID:   workerID,
+				fieldDeliveryID: event.ID,
+				fieldEventID:    event.EventID,
+				fieldEventType:  event.Type,
+				"grace_period":  p.cancelGrace,
+			}).Error("Action did not stop after cancellation, abandoning worker")
+		}
+		p.mu.Unlock()
+	}
+}
+
+// keepIfDraining sets aside an event picked up after draining began; it reports whether the worker should exit
+func (p *Pool) keepIfDraining(event api.Event) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !p.draining {
+		return false
+	}
+	p.notStarted = append(p.notStarted, event)
+	return true
+}
+
 // QueueSize returns the current number of events in the queue
 func (p *Pool) QueueSize() int {
 	return len(p.queue)
@@ -139,3 +464,15 @@ func (p *Pool) QueueSize() int {
 func (p *Pool) QueueCapacity() int {
 	return cap(p.queue)
 }
+
+// FreeSlots returns the number of events the queue can currently accept
+func (p *Pool) FreeSlots() int {
+	return cap(p.queue) - len(p.queue)
+}
+
+// WorkerCount returns the current number of worker goroutines
+func (p *Pool) WorkerCount() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.workers
+}
@@ -87,9 +87,10 @@ func TestPool_Submit(t *testing.T) {
 		Type:    "test.event",
 	}
 
-	pool.Submit(event)
+	assert.True(t, pool.Submit(event))
 
 	assert.Equal(t, 1, pool.QueueSize())
+	assert.Equal(t, 9, pool.FreeSlots())
 }
 
 func TestPool_SubmitMultiple(t *testing.T) {
@@ -137,13 +138,15 @@ func TestPool_SubmitDropsWhenFull(t *testing.T) {
 
 	assert.Equal(t, 3, pool.QueueSize())
 
-	// Try to add one more (should be dropped)
+	assert.Equal(t, 0, pool.FreeSlots())
+
+	// Try to add one more (should be rejected)
 	extraEvent := api.Event{
 		ID:      "delivery-extra",
 		EventID: "event-extra",
 		Type:    "test.event",
 	}
-	pool.Submit(extraEvent)
+	assert.False(t, pool.Submit(extraEvent), "Submit should report rejection when queue is full")
 
 	// Queue should still be 3
 	assert.Equal(t, 3, pool.QueueSize())
@@ -377,9 +380,10 @@ func TestPool_ProcessingWithMultipleWorkers(t *testing.T) {
 
 func TestPool_StartWithZeroWorkers(t *testing.T) {
 	// Test edge case: Start with 0 minimum workers
+	// The pool scales up on demand, so queued events are still processed
 	cfg := &config.PoolConfig{
 		MaxNumberOfWorkers: 5,
-		MinNumberOfWorkers: 0, // Zero workers
+		MinNumberOfWorkers: 0, // Zero core workers
 		QueueSize:          10,
 	}
 
@@ -388,6 +392,7 @@ func TestPool_StartWithZeroWorkers(t *testing.T) {
 
 	ctx := context.Background()
 	pool.Start(ctx)
+	assert.Equal(t, 0, pool.WorkerCount())
 
 	// Submit an event
 	event := api.Event{
@@ -397,15 +402,11 @@ func TestPool_StartWithZeroWorkers(t *testing.T) {
 	}
 	pool.Submit(event)
 
-	// With zero workers, the event should remain in queue
-	assert.Equal(t, 1, pool.QueueSize())
-
-	// Shutdown should be graceful even with no workers
+	// Shutdown should be graceful and drain the queue
 	pool.Shutdown()
 
-	// Event should not be processed since there were no workers
 	executed := executor.GetExecuted()
-	assert.Equal(t, 0, len(executed))
+	assert.Equal(t, 1, len(executed))
 }
 
 func TestPool_SubmitWithCanceledContext(t *testing.T) {
@@ -665,3 +666,249 @@ func TestPool_SingleWorkerProcessing(t *testing.T) {
 	assert.Equal(t, "delivery-2", executed[1].ID)
 	assert.Equal(t, "delivery-3", executed[2].ID)
 }
+
+func TestPool_ScalesUpWhenQueueBacksUp(t *testing.T) {
+	cfg := &config.PoolConfig{
+		MaxNumberOfWorkers: 4,
+		MinNumberOfWorkers: 1,
+		QueueSize:          20,
+	}
+
+	executor := &mockExecutor{
+		delay: 100 * time.Millisecond,
+	}
+	pool := worker.NewPool(cfg, executor)
+
+	ctx := context.Background()
+	pool.Start(ctx)
+	assert.Equal(t, 1, pool.WorkerCount())
+
+	for i := 0; i < 8; i++ {
+		pool.Submit(api.Event{ID: "delivery-" + string(rune('a'+i)), Type: "test.event"})
+	}
+
+	// Pool grows to max but never beyond
+	assert.Equal(t, 4, pool.WorkerCount())
+
+	pool.Shutdown()
+	assert.Equal(t, 8, executor.ExecutedCount())
+}
+
+func TestPool_IdleWorkersRetireAfterKeepAlive(t *testing.T) {
+	cfg := &config.PoolConfig{
+		MaxNumberOfWorkers: 3,
+		MinNumberOfWorkers: 1,
+		QueueSize:          10,
+		KeepAliveTimeMs:    50,
+	}
+
+	executor := &mockExecutor{
+		delay: 20 * time.Millisecond,
+	}
+	pool := worker.NewPool(cfg, executor)
+
+	ctx := context.Background()
+	pool.Start(ctx)
+
+	for i := 0; i < 5; i++ {
+		pool.Submit(api.Event{ID: "delivery-" + string(rune('a'+i)), Type: "test.event"})
+	}
+	assert.Equal(t, 3, pool.WorkerCount())
+
+	// Extra workers retire once idle for keep_alive_time_ms; core workers stay
+	assert.Eventually(t, func() bool {
+		return pool.WorkerCount() == 1
+	}, 2*time.Second, 10*time.Millisecond)
+	assert.Equal(t, 5, executor.ExecutedCount())
+
+	pool.Shutdown()
+}
+
+func TestPool_MonitorScalesUpAndReportsSize(t *testing.T) {
+	cfg := &config.PoolConfig{
+		MaxNumberOfWorkers: 2,
+		MinNumberOfWorkers: 1,
+		QueueSize:          10,
+		MonitoringPeriodMs: 20,
+	}
+
+	executor := &mockExecutor{}
+	pool := worker.NewPool(cfg, executor)
+
+	// Events queued before Start are picked up by the monitor loop
+	pool.Submit(api.Event{ID: "delivery-1", Type: "test.event"})
+	pool.Submit(api.Event{ID: "delivery-2", Type: "test.event"})
+	pool.Submit(api.Event{ID: "delivery-3", Type: "test.event"})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	pool.Start(ctx)
+
+	assert.Eventually(t, func() bool {
+		return executor.ExecutedCount() == 3
+	}, time.Second, 10*time.Millisecond)
+	assert.LessOrEqual(t, pool.WorkerCount(), 2)
+
+	pool.Shutdown()
+}
+
+func TestPool_NoScaleUpAfterContextCanceled(t *testing.T) {
+	cfg := &config.PoolConfig{
+		MaxNumberOfWorkers: 5,
+		MinNumberOfWorkers: 1,
+		QueueSize:          10,
+	}
+
+	executor := &mockExecutor{}
+	pool := worker.NewPool(cfg, executor)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	pool.Start(ctx)
+	cancel()
+
+	assert.Eventually(t, func() bool {
+		return pool.WorkerCount() == 0
+	}, time.Second, 10*time.Millisecond)
+
+	pool.Submit(api.Event{ID: "delivery-1", Type: "test.event"})
+	assert.Equal(t, 0, pool.WorkerCount())
+	assert.Equal(t, 1, pool.QueueSize())
+
+	pool.Shutdown()
+}
+
+// blockingExecutor runs until released or until its context is canceled, recording the cancellation cause
+type blockingExecutor struct {
+	release  chan struct{}
+	started  chan string
+	mu       sync.Mutex
+	finished []string
+	causes   []error
+}
+
+func (b *blockingExecutor) Execute(ctx context.Context, event api.Event) {
+	b.started <- event.ID
+	select {
+	case <-b.release:
+	case <-ctx.Done():
+		b.mu.Lock()
+		b.causes = append(b.causes, context.Cause(ctx))
+		b.mu.Unlock()
+	}
+	b.mu.Lock()
+	b.finished = append(b.finished, event.ID)
+	b.mu.Unlock()
+}
+
+func TestPool_DrainWaitsForInFlightAndReturnsQueued(t *testing.T) {
+	executor := &blockingExecutor{release: make(chan struct{}), started: make(chan string, 10)}
+	pool := worker.NewPool(&config.PoolConfig{
+		MaxNumberOfWorkers: 1,
+		MinNumberOfWorkers: 1,
+		QueueSize:          10,
+	}, executor)
+	pool.Start(context.Background())
+
+	require.True(t, pool.Submit(api.Event{ID: "in-flight"}))
+	assert.Equal(t, "in-flight", <-executor.started)
+	require.True(t, pool.Submit(api.Event{ID: "queued-1"}))
+	require.True(t, pool.Submit(api.Event{ID: "queued-2"}))
+
+	// Let the in-flight action finish shortly after draining starts
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		close(executor.release)
+	}()
+
+	canceled := false
+	notStarted := pool.Drain(5*time.Second, func() { canceled = true })
+
+	assert.False(t, canceled, "Running actions should not be canceled when they finish within the timeout")
+	assert.Equal(t, []string{"in-flight"}, executor.finished)
+	require.Len(t, notStarted, 2)
+	assert.ElementsMatch(t, []string{"queued-1", "queued-2"}, []string{notStarted[0].ID, notStarted[1].ID})
+
+	assert.False(t, pool.Submit(api.Event{ID: "late"}), "Submit should be rejected while draining")
+}
+
+func TestPool_DrainCancelsRunningActionsAfterTimeout(t *testing.T) {
+	executor := &blockingExecutor{release: make(chan struct{}), started: make(chan string, 10)}
+	pool := worker.NewPool(&config.PoolConfig{
+		MaxNumberOfWorkers: 2,
+		MinNumberOfWorkers: 2,
+		QueueSize:          10,
+	}, executor)
+
+	execCtx, cancelExec := context.WithCancelCause(context.Background())
+	defer cancelExec(nil)
+	pool.Start(execCtx)
+
+	require.True(t, pool.Submit(api.Event{ID: "slow-1"}))
+	require.True(t, pool.Submit(api.Event{ID: "slow-2"}))
+	<-executor.started
+	<-executor.started
+
+	start := time.Now()
+	notStarted := pool.Drain(50*time.Millisecond, func() {
+		cancelExec(worker.ErrShutdownTimeout)
+	})
+
+	assert.Less(t, time.Since(start), 2*time.Second)
+	assert.Empty(t, notStarted)
+	executor.mu.Lock()
+	defer executor.mu.Unlock()
+	assert.ElementsMatch(t, []string{"slow-1", "slow-2"}, executor.finished, "Cut-off actions should still finish (and report) before Drain returns")
+	require.Len(t, executor.causes, 2)
+	for _, cause := range executor.causes {
+		assert.ErrorIs(t, cause, worker.ErrShutdownTimeout)
+	}
+}
+
+// stuckExecutor ignores cancellation and only returns when released
+type stuckExecutor struct {
+	release chan struct{}
+	started chan string
+}
+
+func (s *stuckExecutor) Execute(ctx context.Context, event api.Event) {
+	s.started <- event.ID
+	<-s.release
+}
+
+func TestPool_DrainAbandonsActionsThatIgnoreCancellation(t *testing.T) {
+	executor := &stuckExecutor{release: make(chan struct{}), started: make(chan string, 10)}
+	defer close(executor.release)
+	pool := worker.NewPool(&config.PoolConfig{
+		MaxNumberOfWorkers: 1,
+		MinNumberOfWorkers: 1,
+		QueueSize:          10,
+	}, executor)
+	pool.SetCancelGracePeriod(50 * time.Millisecond)
+	pool.Start(context.Background())
+
+	require.True(t, pool.Submit(api.Event{ID: "stuck"}))
+	<-executor.started
+	require.True(t, pool.Submit(api.Event{ID: "queued"}))
+
+	canceled := false
+	start := time.Now()
+	notStarted := pool.Drain(50*time.Millisecond, func() { canceled = true })
+
+	assert.True(t, canceled)
+	assert.Less(t, time.Since(start), 2*time.Second, "Drain should not wait forever for an action that ignores cancellation")
+	require.Len(t, notStarted, 1)
+	assert.Equal(t, "queued", notStarted[0].ID)
+}
+
+func TestPool_DrainIdlePool(t *testing.T) {
+	pool := worker.NewPool(&config.PoolConfig{
+		MaxNumberOfWorkers: 3,
+		MinNumberOfWorkers: 2,
+		QueueSize:          10,
+	}, &mockExecutor{})
+	pool.Start(context.Background())
+
+	notStarted := pool.Drain(time.Second, nil)
+	assert.Empty(t, notStarted)
+	assert.Equal(t, 0, pool.WorkerCount())
+}